@@ -12,7 +12,7 @@ require (
 	nhooyr.io/websocket v1.8.10
 )
 
-require golang.org/x/net v0.22.0 // indirect
+require golang.org/x/net v0.22.0
 
 require (
 	github.com/google/go-cmp v0.6.0
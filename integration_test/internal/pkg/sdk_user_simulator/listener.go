@@ -49,6 +49,26 @@ func (c *conversationCallBack) OnConversationUserInputStatusChanged(change strin
 
 }
 
+func (c *conversationCallBack) OnConversationLiveLocationChanged(change string) {
+
+}
+
+func (c *conversationCallBack) OnConversationPatched(delta string) {
+
+}
+
+func (c *conversationCallBack) OnConversationPinnedMessagesChanged(change string) {
+
+}
+
+func (c *conversationCallBack) OnConversationFolderUnreadCountChanged(folderUnreadCounts string) {
+
+}
+
+func (c *conversationCallBack) OnUnreadCountBreakdownChanged(breakdown string) {
+
+}
+
 type userCallback struct {
 }
 
@@ -141,6 +161,14 @@ func (m *MsgListenerCallBak) OnRecvOnlineOnlyMessage(message string) {
 
 }
 
+func (m *MsgListenerCallBak) OnPollResultChanged(pollResult string) {
+
+}
+
+func (m *MsgListenerCallBak) OnRecvMessageDelivered(deliveryReceiptList string) {
+
+}
+
 type testFriendshipListener struct {
 }
 
@@ -41,11 +41,73 @@ func (c *Conversation) GetConversationListSplit(ctx context.Context, offset, cou
 	return c.db.GetConversationListSplitDB(ctx, offset, count)
 }
 
+// QueryConversationList returns a page of the conversations matching the filters, in the requested order.
+func (c *Conversation) QueryConversationList(ctx context.Context, query *sdk_struct.ConversationQuery) (*sdk_params_callback.QueryConversationListCallback, error) {
+	return c.queryConversationList(ctx, query)
+}
+
+func (c *Conversation) GetConversationListSplitByFolder(ctx context.Context, folderID string, offset, count int) ([]*model_struct.LocalConversation, error) {
+	return c.getConversationListSplitByFolder(ctx, folderID, offset, count)
+}
+
+func (c *Conversation) CreateConversationFolder(ctx context.Context, folder *sdk_struct.ConversationFolder) (*sdk_struct.ConversationFolder, error) {
+	return c.createConversationFolder(ctx, folder)
+}
+
+func (c *Conversation) UpdateConversationFolder(ctx context.Context, folder *sdk_struct.ConversationFolder) error {
+	return c.updateConversationFolder(ctx, folder)
+}
+
+func (c *Conversation) DeleteConversationFolder(ctx context.Context, folderID string) error {
+	return c.deleteConversationFolder(ctx, folderID)
+}
+
+func (c *Conversation) GetConversationFolders(ctx context.Context) ([]*sdk_struct.ConversationFolder, error) {
+	return c.getConversationFolders(ctx)
+}
+
+func (c *Conversation) GetConversationFolderUnreadCounts(ctx context.Context) ([]*sdk_struct.ConversationFolderUnreadCount, error) {
+	return c.getConversationFolderUnreadCounts(ctx)
+}
+
+func (c *Conversation) StarMessage(ctx context.Context, conversationID, clientMsgID string, tagList []string) (*sdk_struct.StarredMessage, error) {
+	return c.starMessage(ctx, conversationID, clientMsgID, tagList)
+}
+
+func (c *Conversation) UnstarMessage(ctx context.Context, clientMsgID string) error {
+	return c.unstarMessage(ctx, clientMsgID)
+}
+
+func (c *Conversation) SetStarredMessageTags(ctx context.Context, clientMsgID string, tagList []string) error {
+	return c.setStarredMessageTags(ctx, clientMsgID, tagList)
+}
+
+func (c *Conversation) GetStarredMessageList(ctx context.Context, req *sdk_params_callback.GetStarredMessagesParams) (*sdk_params_callback.GetStarredMessagesCallback, error) {
+	return c.getStarredMessageList(ctx, req)
+}
+
+func (c *Conversation) GetStarredMessageTags(ctx context.Context) ([]string, error) {
+	return c.getStarredMessageTags(ctx)
+}
+
+func (c *Conversation) MuteConversationUntil(ctx context.Context, conversationID string, untilTime int64) error {
+	return c.muteConversationUntil(ctx, conversationID, untilTime)
+}
+
+func (c *Conversation) SnoozeConversationUntil(ctx context.Context, conversationID string, untilTime int64) error {
+	return c.snoozeConversationUntil(ctx, conversationID, untilTime)
+}
+
+func (c *Conversation) GetConversationTimerList(ctx context.Context) ([]*model_struct.LocalConversationTimer, error) {
+	return c.db.GetConversationTimerList(ctx)
+}
+
 func (c *Conversation) HideConversation(ctx context.Context, conversationID string) error {
 	err := c.db.ResetConversation(ctx, conversationID)
 	if err != nil {
 		return err
 	}
+	c.deleteRichDrafts(ctx, conversationID)
 	return nil
 }
 
@@ -107,26 +169,43 @@ func (c *Conversation) HideAllConversations(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	c.deleteRichDrafts(ctx)
 	return nil
 }
 
+// SetConversationDraft replaces the text of the draft and keeps its quote, mentions and attachments.
+// An empty text clears the whole draft.
 func (c *Conversation) SetConversationDraft(ctx context.Context, conversationID, draftText string) error {
+	draft := &sdk_struct.ConversationDraft{}
 	if draftText != "" {
-		err := c.db.SetConversationDraftDB(ctx, conversationID, draftText)
-		if err != nil {
+		var err error
+		if draft, err = c.getConversationRichDraft(ctx, conversationID); err != nil {
 			return err
 		}
-	} else {
-		err := c.db.RemoveConversationDraft(ctx, conversationID, draftText)
-		if err != nil {
+		draft.Text = draftText
+	}
+	return c.setConversationRichDraft(ctx, conversationID, draft)
+}
+
+func (c *Conversation) SetConversationRichDraft(ctx context.Context, conversationID string, draft *sdk_struct.ConversationDraft) error {
+	return c.setConversationRichDraft(ctx, conversationID, draft)
+}
+
+func (c *Conversation) GetConversationRichDraft(ctx context.Context, conversationID string) (*sdk_struct.ConversationDraft, error) {
+	return c.getConversationRichDraft(ctx, conversationID)
+}
+
+func (c *Conversation) SetConversation(ctx context.Context, conversationID string, req *pbConversation.ConversationReq) error {
+	if req.RecvMsgOpt != nil {
+		// an explicit option replaces the time bounded mute
+		if err := c.clearConversationMute(ctx, conversationID); err != nil {
 			return err
 		}
 	}
-	_ = common.TriggerCmdUpdateConversation(ctx, common.UpdateConNode{Action: constant.ConChange, Args: []string{conversationID}}, c.GetCh())
-	return nil
+	return c.setConversationAndSync(ctx, conversationID, req)
 }
 
-func (c *Conversation) SetConversation(ctx context.Context, conversationID string, req *pbConversation.ConversationReq) error {
+func (c *Conversation) setConversationAndSync(ctx context.Context, conversationID string, req *pbConversation.ConversationReq) error {
 	c.conversationSyncMutex.Lock()
 	defer c.conversationSyncMutex.Unlock()
 
@@ -146,6 +225,10 @@ func (c *Conversation) GetTotalUnreadMsgCount(ctx context.Context) (totalUnreadC
 	return c.db.GetTotalUnreadMsgCountDB(ctx)
 }
 
+func (c *Conversation) GetUnreadCountBreakdown(ctx context.Context) (*sdk_struct.UnreadCountBreakdown, error) {
+	return c.getUnreadCountBreakdown(ctx)
+}
+
 func (c *Conversation) SetConversationListener(listener func() open_im_sdk_callback.OnConversationListener) {
 	c.ConversationListener = listener
 }
@@ -180,6 +263,8 @@ func (c *Conversation) msgStructToLocalChatLog(src *sdk_struct.MsgStruct) *model
 		lc.Content = utils.StructToJsonString(src.FaceElem)
 	case constant.AdvancedText:
 		lc.Content = utils.StructToJsonString(src.AdvancedTextElem)
+	case constant.Poll:
+		lc.Content = utils.StructToJsonString(src.PollElem)
 	default:
 		lc.Content = utils.StructToJsonString(src.NotificationElem)
 	}
@@ -213,10 +298,10 @@ func (c *Conversation) updateMsgStatusAndTriggerConversation(ctx context.Context
 	if isOnlineOnly {
 		return
 	}
+	status, err := c.updateAckedMessage(ctx, lc.ConversationID, clientMsgID, serverMsgID, sendTime, status, s)
 	s.SendTime = sendTime
 	s.Status = status
 	s.ServerMsgID = serverMsgID
-	err := c.db.UpdateMessageTimeAndStatus(ctx, lc.ConversationID, clientMsgID, serverMsgID, sendTime, status)
 	if err != nil {
 		log.ZWarn(ctx, "send message update message status error", err,
 			"sendTime", sendTime, "status", status, "clientMsgID", clientMsgID, "serverMsgID", serverMsgID)
@@ -234,6 +319,99 @@ func (c *Conversation) fileName(ftype string, id string) string {
 	return fmt.Sprintf("msg_%s_%s", ftype, id)
 }
 
+// uploadPictureVariants uploads the big and snapshot copies of the picture, decoded once for both.
+// The source picture stands in for a copy it already fits in, the object storage resizes the
+// snapshot when it could not be made here.
+func (c *Conversation) uploadPictureVariants(ctx context.Context, s *sdk_struct.MsgStruct, sourcePath string) (bigPicture, snapshotPicture *sdk_struct.PictureBaseInfo) {
+	source := s.PictureElem.SourcePicture
+	img, err := decodeImage(sourcePath)
+	if err != nil {
+		log.ZWarn(ctx, "decode picture failed", err, "path", sourcePath)
+		return source, ossSnapshotPicture(ctx, source)
+	}
+	bigPicture, err = c.uploadPictureVariant(ctx, s, img, "big", bigPictureMaxSize)
+	if err != nil {
+		log.ZWarn(ctx, "upload big picture failed", err, "path", sourcePath)
+	}
+	if bigPicture == nil {
+		bigPicture = source
+	}
+	snapshotPicture, err = c.uploadPictureVariant(ctx, s, img, "snapshot", snapshotPictureMaxSize)
+	switch {
+	case err != nil:
+		log.ZWarn(ctx, "upload snapshot picture failed", err, "path", sourcePath)
+		snapshotPicture = ossSnapshotPicture(ctx, source)
+	case snapshotPicture == nil:
+		snapshotPicture = source
+	}
+	return bigPicture, snapshotPicture
+}
+
+// uploadPictureVariant uploads a copy of the picture resized to maxSize. It returns nil when the
+// picture already fits in maxSize.
+func (c *Conversation) uploadPictureVariant(ctx context.Context, s *sdk_struct.MsgStruct, img *decodedImage, variant string, maxSize int) (*sdk_struct.PictureBaseInfo, error) {
+	if !img.needsResize(maxSize) {
+		return nil, nil
+	}
+	ext := ".jpg"
+	if img.format == "png" {
+		ext = ".png"
+	}
+	tmp, err := os.CreateTemp(c.DataDir, "picture_"+variant+"_*"+ext)
+	if err != nil {
+		return nil, errs.WrapMsg(err, "create resized picture failed")
+	}
+	defer os.Remove(tmp.Name())
+	info, err := resizeImage(img, tmp, maxSize)
+	if err == nil {
+		var stat os.FileInfo
+		if stat, err = tmp.Stat(); err == nil {
+			info.Size = stat.Size()
+		}
+	}
+	if closeErr := tmp.Close(); err == nil && closeErr != nil {
+		err = errs.Wrap(closeErr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.file.UploadFile(ctx, &file.UploadFileReq{
+		ContentType: info.Type,
+		Filepath:    tmp.Name(),
+		Name:        c.fileName("picture_"+variant, s.ClientMsgID) + ext,
+		Cause:       "msg-picture-" + variant,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &sdk_struct.PictureBaseInfo{
+		Type:   info.Type,
+		Size:   info.Size,
+		Width:  info.Width,
+		Height: info.Height,
+		Url:    res.URL,
+	}, nil
+}
+
+// ossSnapshotPicture asks the object storage to resize the picture, for pictures not resized locally.
+func ossSnapshotPicture(ctx context.Context, source *sdk_struct.PictureBaseInfo) *sdk_struct.PictureBaseInfo {
+	u, err := url.Parse(source.Url)
+	if err != nil {
+		log.ZError(ctx, "parse url failed", err, "url", source.Url, "err", err)
+		return source
+	}
+	snapshot := u.Query()
+	snapshot.Set("type", "image")
+	snapshot.Set("width", "640")
+	snapshot.Set("height", "640")
+	u.RawQuery = snapshot.Encode()
+	return &sdk_struct.PictureBaseInfo{
+		Width:  640,
+		Height: 640,
+		Url:    u.String(),
+	}
+}
+
 func (c *Conversation) checkID(ctx context.Context, s *sdk_struct.MsgStruct,
 	recvID, groupID string, options map[string]bool) (*model_struct.LocalConversation, error) {
 	if recvID == "" && groupID == "" {
@@ -340,6 +518,13 @@ func (c *Conversation) SendMessage(ctx context.Context, s *sdk_struct.MsgStruct,
 	if err != nil {
 		return nil, err
 	}
+	if err := c.preSend(ctx, s); err != nil {
+		return nil, err
+	}
+	if err := c.filterSensitiveWords(ctx, s); err != nil {
+		return nil, err
+	}
+	c.embedLinkPreview(ctx, s)
 	callback, _ := ctx.Value("callback").(open_im_sdk_callback.SendMsgCallBack)
 	log.ZDebug(ctx, "before insert message is", "message", *s)
 	if !isOnlineOnly {
@@ -380,7 +565,7 @@ func (c *Conversation) SendMessage(ctx context.Context, s *sdk_struct.MsgStruct,
 	//media file handle
 	switch s.ContentType {
 	case constant.Picture:
-		if s.Status == constant.MsgStatusSendSuccess {
+		if isSentStatus(s.Status) {
 			s.Content = utils.StructToJsonString(s.PictureElem)
 			break
 		}
@@ -407,26 +592,10 @@ func (c *Conversation) SendMessage(ctx context.Context, s *sdk_struct.MsgStruct,
 			return nil, err
 		}
 		s.PictureElem.SourcePicture.Url = res.URL
-		s.PictureElem.BigPicture = s.PictureElem.SourcePicture
-		u, err := url.Parse(res.URL)
-		if err == nil {
-			snapshot := u.Query()
-			snapshot.Set("type", "image")
-			snapshot.Set("width", "640")
-			snapshot.Set("height", "640")
-			u.RawQuery = snapshot.Encode()
-			s.PictureElem.SnapshotPicture = &sdk_struct.PictureBaseInfo{
-				Width:  640,
-				Height: 640,
-				Url:    u.String(),
-			}
-		} else {
-			log.ZError(ctx, "parse url failed", err, "url", res.URL, "err", err)
-			s.PictureElem.SnapshotPicture = s.PictureElem.SourcePicture
-		}
+		s.PictureElem.BigPicture, s.PictureElem.SnapshotPicture = c.uploadPictureVariants(ctx, s, sourcePath)
 		s.Content = utils.StructToJsonString(s.PictureElem)
 	case constant.Sound:
-		if s.Status == constant.MsgStatusSendSuccess {
+		if isSentStatus(s.Status) {
 			s.Content = utils.StructToJsonString(s.SoundElem)
 			break
 		}
@@ -454,7 +623,7 @@ func (c *Conversation) SendMessage(ctx context.Context, s *sdk_struct.MsgStruct,
 		s.SoundElem.SourceURL = res.URL
 		s.Content = utils.StructToJsonString(s.SoundElem)
 	case constant.Video:
-		if s.Status == constant.MsgStatusSendSuccess {
+		if isSentStatus(s.Status) {
 			s.Content = utils.StructToJsonString(s.VideoElem)
 			break
 		}
@@ -516,7 +685,7 @@ func (c *Conversation) SendMessage(ctx context.Context, s *sdk_struct.MsgStruct,
 		}
 		s.Content = utils.StructToJsonString(s.VideoElem)
 	case constant.File:
-		if s.Status == constant.MsgStatusSendSuccess {
+		if isSentStatus(s.Status) {
 			s.Content = utils.StructToJsonString(s.FileElem)
 			break
 		}
@@ -558,6 +727,8 @@ func (c *Conversation) SendMessage(ctx context.Context, s *sdk_struct.MsgStruct,
 		s.Content = utils.StructToJsonString(s.FaceElem)
 	case constant.AdvancedText:
 		s.Content = utils.StructToJsonString(s.AdvancedTextElem)
+	case constant.Poll:
+		s.Content = utils.StructToJsonString(s.PollElem)
 	default:
 		return nil, sdkerrs.ErrMsgContentTypeNotSupport
 	}
@@ -582,6 +753,13 @@ func (c *Conversation) SendMessageNotOss(ctx context.Context, s *sdk_struct.MsgS
 	if err != nil {
 		return nil, err
 	}
+	if err := c.preSend(ctx, s); err != nil {
+		return nil, err
+	}
+	if err := c.filterSensitiveWords(ctx, s); err != nil {
+		return nil, err
+	}
+	c.embedLinkPreview(ctx, s)
 	callback, _ := ctx.Value("callback").(open_im_sdk_callback.SendMsgCallBack)
 	if !isOnlineOnly {
 		oldMessage, err := c.db.GetMessage(ctx, lc.ConversationID, s.ClientMsgID)
@@ -642,6 +820,8 @@ func (c *Conversation) SendMessageNotOss(ctx context.Context, s *sdk_struct.MsgS
 		s.Content = utils.StructToJsonString(s.FaceElem)
 	case constant.AdvancedText:
 		s.Content = utils.StructToJsonString(s.AdvancedTextElem)
+	case constant.Poll:
+		s.Content = utils.StructToJsonString(s.PollElem)
 	default:
 		return nil, sdkerrs.ErrMsgContentTypeNotSupport
 	}
@@ -679,7 +859,11 @@ func (c *Conversation) sendMessageToServer(ctx context.Context, s *sdk_struct.Ms
 	if wsMsgData.ContentType == constant.AtText {
 		wsMsgData.AtUserIDList = s.AtTextElem.AtUserList
 	}
-	wsMsgData.OfflinePushInfo = offlinePushInfo
+	if isOnlineOnly {
+		wsMsgData.OfflinePushInfo = offlinePushInfo
+	} else {
+		wsMsgData.OfflinePushInfo = c.fillOfflinePushInfo(ctx, s, lc, offlinePushInfo)
+	}
 	s.Content = ""
 	var sendMsgResp sdkws.UserSendMsgResp
 
@@ -688,7 +872,7 @@ func (c *Conversation) sendMessageToServer(ctx context.Context, s *sdk_struct.Ms
 		//if send message network timeout need to double-check message has received by db.
 		if sdkerrs.ErrNetworkTimeOut.Is(err) && !isOnlineOnly {
 			oldMessage, _ := c.db.GetMessage(ctx, lc.ConversationID, s.ClientMsgID)
-			if oldMessage.Status == constant.MsgStatusSendSuccess {
+			if isSentStatus(oldMessage.Status) {
 				sendMsgResp.SendTime = oldMessage.SendTime
 				sendMsgResp.ClientMsgID = oldMessage.ClientMsgID
 				sendMsgResp.ServerMsgID = oldMessage.ServerMsgID
@@ -821,16 +1005,68 @@ func (c *Conversation) GetAdvancedHistoryMessageListReverse(ctx context.Context,
 	return result, nil
 }
 
+// GetMessageListAroundAnchor returns a window of messages before and after a message given by clientMsgID or seq.
+func (c *Conversation) GetMessageListAroundAnchor(ctx context.Context, req sdk_params_callback.GetMessageListAroundAnchorParams) (*sdk_params_callback.GetMessageListAroundAnchorCallback, error) {
+	return c.getMessageListAroundAnchor(ctx, req)
+}
+
 func (c *Conversation) RevokeMessage(ctx context.Context, conversationID, clientMsgID string) error {
 	return c.revokeOneMessage(ctx, conversationID, clientMsgID)
 }
 
+// RevokeMessages revokes up to BatchMessageOperationMaxCount messages of a conversation. The server
+// revokes one message per request, so this sends one request per message, a few at a time, and
+// reports the messages it failed to revoke one by one.
+func (c *Conversation) RevokeMessages(ctx context.Context, conversationID string, clientMsgIDs []string) (*sdk_params_callback.BatchMessageOperationCallback, error) {
+	return c.revokeMessageList(ctx, conversationID, clientMsgIDs)
+}
+
+// RevokeUserMessages revokes the messages userID sent in a conversation between startTime and endTime.
+// Only the messages stored on this device are found: messages never synced here, such as the ones
+// older than the local history, are not revoked. Like RevokeMessages it sends one request per
+// message and revokes at most BatchMessageOperationMaxCount of them per call.
+func (c *Conversation) RevokeUserMessages(ctx context.Context, conversationID, userID string, startTime, endTime int64) (*sdk_params_callback.BatchMessageOperationCallback, error) {
+	return c.revokeUserMessages(ctx, conversationID, userID, startTime, endTime)
+}
+
+func (c *Conversation) PinMessage(ctx context.Context, conversationID, clientMsgID string) error {
+	return c.pinMessage(ctx, conversationID, clientMsgID, true)
+}
+
+func (c *Conversation) UnpinMessage(ctx context.Context, conversationID, clientMsgID string) error {
+	return c.pinMessage(ctx, conversationID, clientMsgID, false)
+}
+
+func (c *Conversation) GetPinnedMessageList(ctx context.Context, conversationID string) ([]*sdk_struct.MsgStruct, error) {
+	return c.getPinnedMessageList(ctx, conversationID)
+}
+
+func (c *Conversation) SetSensitiveWordFilter(ctx context.Context, mode int32, wordFilePath string) error {
+	return c.setSensitiveWordFilter(ctx, mode, wordFilePath)
+}
+
+func (c *Conversation) GetLinkPreview(ctx context.Context, url string) (*sdk_struct.LinkPreview, error) {
+	return c.getLinkPreview(ctx, url)
+}
+
+func (c *Conversation) VotePoll(ctx context.Context, conversationID, clientMsgID string, optionIDList []string) error {
+	return c.votePoll(ctx, conversationID, clientMsgID, optionIDList)
+}
+
 func (c *Conversation) TypingStatusUpdate(ctx context.Context, recvID, msgTip string) error {
 	return c.typingStatusUpdate(ctx, recvID, msgTip)
 }
 
 func (c *Conversation) MarkConversationMessageAsRead(ctx context.Context, conversationID string) error {
-	return c.markConversationMessageAsRead(ctx, conversationID)
+	return c.markConversationAsReadAndClearFlag(ctx, conversationID)
+}
+
+func (c *Conversation) MarkConversationAsUnread(ctx context.Context, conversationID string) error {
+	return c.markConversationAsUnread(ctx, conversationID)
+}
+
+func (c *Conversation) GetMessageReadMembers(ctx context.Context, conversationID, clientMsgID string) (*sdk_params_callback.GetMessageReadMembersCallback, error) {
+	return c.getMessageReadMembers(ctx, conversationID, clientMsgID)
 }
 
 func (c *Conversation) MarkMessagesAsReadByMsgID(ctx context.Context, conversationID string, clientMsgIDs []string) error {
@@ -845,6 +1081,10 @@ func (c *Conversation) DeleteMessage(ctx context.Context, conversationID string,
 	return c.deleteMessage(ctx, conversationID, clientMsgID)
 }
 
+func (c *Conversation) DeleteMessages(ctx context.Context, conversationID string, clientMsgIDs []string, forEveryone bool) (*sdk_params_callback.BatchMessageOperationCallback, error) {
+	return c.deleteMessages(ctx, conversationID, clientMsgIDs, forEveryone)
+}
+
 func (c *Conversation) DeleteAllMsgFromLocalAndServer(ctx context.Context) error {
 	return c.deleteAllMsgFromLocalAndServer(ctx)
 }
@@ -957,6 +1197,23 @@ func (c *Conversation) SearchLocalMessages(ctx context.Context, searchParam *sdk
 	return c.searchLocalMessages(ctx, searchParam)
 
 }
+
+func (c *Conversation) GlobalSearch(ctx context.Context, searchParam *sdk_params_callback.GlobalSearchParams) (*sdk_params_callback.GlobalSearchCallback, error) {
+	searchParam.KeywordList = utils.TrimStringList(searchParam.KeywordList)
+	return c.globalSearch(ctx, searchParam)
+}
+func (c *Conversation) ForwardMessages(ctx context.Context, messages []*sdk_struct.MsgStruct, targets []*sdk_params_callback.ForwardTarget, mode int32) (*sdk_params_callback.ForwardMessagesCallback, error) {
+	return c.forwardMessages(ctx, messages, targets, mode)
+}
+
+func (c *Conversation) ExportConversationHistory(ctx context.Context, req *sdk_params_callback.ExportConversationHistoryParams) (*sdk_params_callback.ExportConversationHistoryCallback, error) {
+	return c.exportConversationHistory(ctx, req)
+}
+
+func (c *Conversation) ImportConversationHistory(ctx context.Context, archivePath string) (*sdk_params_callback.ImportConversationHistoryCallback, error) {
+	return c.importConversationHistory(ctx, archivePath)
+}
+
 func (c *Conversation) SetMessageLocalEx(ctx context.Context, conversationID string, clientMsgID string, localEx string) error {
 	err := c.db.UpdateColumnsMessage(ctx, conversationID, clientMsgID, map[string]interface{}{"local_ex": localEx})
 	if err != nil {
@@ -0,0 +1,252 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversation_msg
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	sdk "github.com/openimsdk/openim-sdk-core/v3/pkg/sdk_params_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/protocol/sdkws"
+	"github.com/openimsdk/tools/errs"
+	"github.com/openimsdk/tools/log"
+	"github.com/openimsdk/tools/utils/datautil"
+	"github.com/openimsdk/tools/utils/timeutil"
+	"golang.org/x/sync/errgroup"
+)
+
+// revokeMessagesConcurrency is the number of revoke requests sent to the server at the same time
+const revokeMessagesConcurrency = 10
+
+// batchResult collects the outcome of every message of a batch operation.
+type batchResult struct {
+	lock sync.Mutex
+	res  sdk.BatchMessageOperationCallback
+}
+
+func newBatchResult() *batchResult {
+	return &batchResult{res: sdk.BatchMessageOperationCallback{SuccessClientMsgIDList: []string{}, FailedList: []*sdk.MessageOperationResult{}}}
+}
+
+func (r *batchResult) fail(clientMsgID string, err error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	result := &sdk.MessageOperationResult{ClientMsgID: clientMsgID}
+	result.ErrCode, result.ErrMsg = errCodeAndMsg(err)
+	r.res.FailedList = append(r.res.FailedList, result)
+}
+
+func (r *batchResult) succeed(clientMsgID string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.res.SuccessClientMsgIDList = append(r.res.SuccessClientMsgIDList, clientMsgID)
+}
+
+// getBatchMessages loads the messages of the conversation, the ones not found locally are reported as failed.
+func (c *Conversation) getBatchMessages(ctx context.Context, conversationID string, clientMsgIDs []string, result *batchResult) ([]*model_struct.LocalChatLog, error) {
+	clientMsgIDs = datautil.Distinct(clientMsgIDs)
+	if len(clientMsgIDs) == 0 || len(clientMsgIDs) > constant.BatchMessageOperationMaxCount {
+		return nil, sdkerrs.ErrArgs.WrapMsg("invalid clientMsgIDs count", "count", len(clientMsgIDs))
+	}
+	messages, err := c.db.GetMessagesByClientMsgIDs(ctx, conversationID, clientMsgIDs)
+	if err != nil {
+		return nil, err
+	}
+	found := datautil.SliceSetAny(messages, func(message *model_struct.LocalChatLog) string { return message.ClientMsgID })
+	for _, clientMsgID := range clientMsgIDs {
+		if _, ok := found[clientMsgID]; !ok {
+			result.fail(clientMsgID, errs.ErrRecordNotFound.WrapMsg("message not found", "clientMsgID", clientMsgID))
+		}
+	}
+	return messages, nil
+}
+
+// isBatchAdmin loads the admin state only when a message of another member is concerned.
+func (c *Conversation) isBatchAdmin(ctx context.Context, conversation *model_struct.LocalConversation, messages []*model_struct.LocalChatLog) (bool, error) {
+	if conversation.ConversationType != constant.ReadGroupChatType {
+		return false, nil
+	}
+	for _, message := range messages {
+		if message.SendID != c.loginUserID {
+			return c.isGroupAdmin(ctx, conversation.GroupID)
+		}
+	}
+	return false, nil
+}
+
+// revokeMessageLogs revokes messages of one conversation. The server revokes one message per
+// request, so the requests run concurrently, and the revokes are then applied locally together.
+func (c *Conversation) revokeMessageLogs(ctx context.Context, conversation *model_struct.LocalConversation, messages []*model_struct.LocalChatLog, result *batchResult) error {
+	isAdmin, err := c.isBatchAdmin(ctx, conversation, messages)
+	if err != nil {
+		return err
+	}
+	var (
+		lock     sync.Mutex
+		tipsList []*sdkws.RevokeMsgTips
+	)
+	revokeTime := timeutil.GetCurrentTimestampBySecond()
+	g := new(errgroup.Group)
+	g.SetLimit(revokeMessagesConcurrency)
+	for _, message := range messages {
+		if err := c.checkRevokeMessage(conversation, message, isAdmin); err != nil {
+			result.fail(message.ClientMsgID, sdkerrs.ErrArgs.WrapMsg(err.Error()))
+			continue
+		}
+		message := message
+		g.Go(func() error {
+			if err := c.revokeMessageFromServer(ctx, conversation.ConversationID, message.Seq); err != nil {
+				log.ZWarn(ctx, "revoke message failed", err, "conversationID", conversation.ConversationID, "seq", message.Seq)
+				result.fail(message.ClientMsgID, err)
+				return nil
+			}
+			lock.Lock()
+			tipsList = append(tipsList, &sdkws.RevokeMsgTips{
+				ConversationID: conversation.ConversationID,
+				Seq:            message.Seq,
+				RevokerUserID:  c.loginUserID,
+				RevokeTime:     revokeTime,
+				SesstionType:   conversation.ConversationType,
+				ClientMsgID:    message.ClientMsgID,
+			})
+			lock.Unlock()
+			result.succeed(message.ClientMsgID)
+			return nil
+		})
+	}
+	_ = g.Wait()
+	sort.Slice(tipsList, func(i, j int) bool {
+		return tipsList[i].Seq < tipsList[j].Seq
+	})
+	if err := c.revokeMessages(ctx, conversation.ConversationID, tipsList); err != nil {
+		log.ZWarn(ctx, "apply revoked messages failed", err, "conversationID", conversation.ConversationID)
+	}
+	return nil
+}
+
+func (c *Conversation) revokeMessageList(ctx context.Context, conversationID string, clientMsgIDs []string) (*sdk.BatchMessageOperationCallback, error) {
+	conversation, err := c.db.GetConversation(ctx, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	result := newBatchResult()
+	messages, err := c.getBatchMessages(ctx, conversationID, clientMsgIDs, result)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.revokeMessageLogs(ctx, conversation, messages, result); err != nil {
+		return nil, err
+	}
+	return &result.res, nil
+}
+
+// revokeUserMessages revokes the messages sent by userID between startTime and endTime, in
+// milliseconds, endTime 0 meaning now. At most BatchMessageOperationMaxCount messages are revoked,
+// the oldest first, calling it again continues with the next ones. The server can't find messages
+// by sender, so only the messages stored locally are revoked.
+func (c *Conversation) revokeUserMessages(ctx context.Context, conversationID, userID string, startTime, endTime int64) (*sdk.BatchMessageOperationCallback, error) {
+	if userID == "" {
+		return nil, sdkerrs.ErrArgs.WrapMsg("userID can't be empty")
+	}
+	if endTime == 0 {
+		endTime = timeutil.GetCurrentTimestampByMill()
+	}
+	if startTime > endTime {
+		return nil, sdkerrs.ErrArgs.WrapMsg("startTime is after endTime")
+	}
+	conversation, err := c.db.GetConversation(ctx, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	messages, err := c.db.GetMessagesBySendID(ctx, conversationID, userID, startTime, endTime)
+	if err != nil {
+		return nil, err
+	}
+	messages = datautil.Filter(messages, func(message *model_struct.LocalChatLog) (*model_struct.LocalChatLog, bool) {
+		return message, isSentStatus(message.Status) && message.Seq != 0 && message.ContentType < constant.NotificationBegin
+	})
+	if len(messages) > constant.BatchMessageOperationMaxCount {
+		messages = messages[:constant.BatchMessageOperationMaxCount]
+	}
+	result := newBatchResult()
+	if err := c.revokeMessageLogs(ctx, conversation, messages, result); err != nil {
+		return nil, err
+	}
+	return &result.res, nil
+}
+
+// deleteMessages deletes messages with one server request. Deleting for everyone needs the same
+// rights as revoking: own messages, or any message for the group admins.
+func (c *Conversation) deleteMessages(ctx context.Context, conversationID string, clientMsgIDs []string, forEveryone bool) (*sdk.BatchMessageOperationCallback, error) {
+	conversation, err := c.db.GetConversation(ctx, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	result := newBatchResult()
+	messages, err := c.getBatchMessages(ctx, conversationID, clientMsgIDs, result)
+	if err != nil {
+		return nil, err
+	}
+	var isAdmin bool
+	if forEveryone {
+		if isAdmin, err = c.isBatchAdmin(ctx, conversation, messages); err != nil {
+			return nil, err
+		}
+	}
+	var (
+		seqs         []int64
+		localDeletes []*model_struct.LocalChatLog
+		remotes      []*model_struct.LocalChatLog
+	)
+	for _, message := range messages {
+		switch {
+		case message.Status == constant.MsgStatusSendFailed:
+			localDeletes = append(localDeletes, message)
+		case message.Seq == 0:
+			result.fail(message.ClientMsgID, sdkerrs.ErrMsgHasNoSeq)
+		case forEveryone && message.SendID != c.loginUserID && !isAdmin:
+			result.fail(message.ClientMsgID, sdkerrs.ErrNoPermission)
+		default:
+			seqs = append(seqs, message.Seq)
+			remotes = append(remotes, message)
+		}
+	}
+	if len(seqs) > 0 {
+		if forEveryone {
+			err = c.deleteMessagesForEveryoneFromServer(ctx, conversationID, seqs)
+		} else {
+			err = c.deleteMessagesFromServer(ctx, conversationID, seqs)
+		}
+		if err != nil {
+			for _, message := range remotes {
+				result.fail(message.ClientMsgID, err)
+			}
+		} else {
+			localDeletes = append(localDeletes, remotes...)
+		}
+	}
+	for _, message := range localDeletes {
+		if err := c.deleteMessageFromLocal(ctx, conversationID, message.ClientMsgID); err != nil {
+			result.fail(message.ClientMsgID, err)
+			continue
+		}
+		result.succeed(message.ClientMsgID)
+	}
+	return &result.res, nil
+}
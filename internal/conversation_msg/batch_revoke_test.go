@@ -0,0 +1,177 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !js
+
+package conversation_msg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/ccontext"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+	pbMsg "github.com/openimsdk/protocol/msg"
+)
+
+// newRevokeServer answers the revoke requests, failing the ones of the failing seqs, and records the revoked seqs.
+func newRevokeServer(t *testing.T, failingSeqs ...int64) (context.Context, func() []int64) {
+	var (
+		lock sync.Mutex
+		seqs []int64
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req pbMsg.RevokeMsgReq
+		if r.URL.Path != "/msg/revoke_msg" || json.NewDecoder(r.Body).Decode(&req) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		lock.Lock()
+		seqs = append(seqs, req.Seq)
+		lock.Unlock()
+		for _, seq := range failingSeqs {
+			if seq == req.Seq {
+				fmt.Fprint(w, `{"errCode":1004,"errMsg":"RecordNotFoundError"}`)
+				return
+			}
+		}
+		fmt.Fprint(w, `{"errCode":0,"data":{}}`)
+	}))
+	t.Cleanup(server.Close)
+	ctx := ccontext.WithInfo(context.Background(), &ccontext.GlobalConfig{UserID: "u1", IMConfig: sdk_struct.IMConfig{ApiAddr: server.URL}})
+	return ccontext.WithOperationID(ctx, "revoke_test"), func() []int64 {
+		lock.Lock()
+		defer lock.Unlock()
+		res := append([]int64(nil), seqs...)
+		sort.Slice(res, func(i, j int) bool { return res[i] < res[j] })
+		return res
+	}
+}
+
+func newRevokeTestConversation(t *testing.T, ctx context.Context, role int32) *Conversation {
+	c, _ := newTestConversation(t, "u1")
+	if err := c.db.InsertConversation(ctx, &model_struct.LocalConversation{ConversationID: "sg_g1",
+		ConversationType: constant.ReadGroupChatType, GroupID: "g1", LatestMsgSendTime: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.db.InsertGroupMember(ctx, &model_struct.LocalGroupMember{GroupID: "g1", UserID: "u1", Nickname: "me", RoleLevel: role}); err != nil {
+		t.Fatal(err)
+	}
+	var messages []*model_struct.LocalChatLog
+	for i, sendID := range []string{"u1", "u1", "u1", "u2", "u2"} {
+		seq := int64(i + 1)
+		msg := newTestTextMessage("sg_g1", fmt.Sprintf("m%d", seq), sendID, seq, 1000*seq)
+		msg.RecvID, msg.SessionType = "g1", constant.ReadGroupChatType
+		messages = append(messages, msg)
+	}
+	for i, quoted := range []string{"m1", "m2", "m4"} {
+		seq := int64(i + 6)
+		msg := newTestTextMessage("sg_g1", fmt.Sprintf("q%d", seq), "u3", seq, 1000*seq)
+		msg.RecvID, msg.SessionType, msg.ContentType = "g1", constant.ReadGroupChatType, constant.Quote
+		msg.Content = utils.StructToJsonString(sdk_struct.QuoteElem{Text: "re",
+			QuoteMessage: &sdk_struct.MsgStruct{ClientMsgID: quoted, ContentType: constant.Text}})
+		messages = append(messages, msg)
+	}
+	if err := c.db.BatchInsertMessageList(ctx, "sg_g1", messages); err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// quotedContentTypes returns the content type of the message quoted by every quote message.
+func quotedContentTypes(t *testing.T, ctx context.Context, c *Conversation) map[string]int32 {
+	quotes, err := c.db.SearchAllMessageByContentType(ctx, "sg_g1", constant.Quote)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := make(map[string]int32)
+	for _, quote := range quotes {
+		var elem sdk_struct.QuoteElem
+		if err := json.Unmarshal([]byte(quote.Content), &elem); err != nil {
+			t.Fatal(err)
+		}
+		res[elem.QuoteMessage.ClientMsgID] = elem.QuoteMessage.ContentType
+	}
+	return res
+}
+
+func TestRevokeMessageList(t *testing.T) {
+	ctx, revokedSeqs := newRevokeServer(t, 2)
+	c := newRevokeTestConversation(t, ctx, constant.GroupOrdinaryUsers)
+
+	res, err := c.revokeMessageList(ctx, "sg_g1", []string{"m1", "m2", "m3", "m4", "m1", "missing"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(res.SuccessClientMsgIDList)
+	if fmt.Sprint(res.SuccessClientMsgIDList) != "[m1 m3]" {
+		t.Fatalf("revoked %v", res.SuccessClientMsgIDList)
+	}
+	failed := make(map[string]bool)
+	for _, v := range res.FailedList {
+		failed[v.ClientMsgID] = true
+	}
+	if len(failed) != 3 || !failed["m2"] || !failed["m4"] || !failed["missing"] {
+		t.Fatalf("failed %v", failed)
+	}
+	// a member can't revoke the messages of others, they are not sent to the server
+	if got := fmt.Sprint(revokedSeqs()); got != "[1 2 3]" {
+		t.Fatalf("revoke requests %s", got)
+	}
+	for clientMsgID, contentType := range map[string]int32{"m1": constant.RevokeNotification, "m2": constant.Text,
+		"m3": constant.RevokeNotification, "m4": constant.Text} {
+		msg, err := c.db.GetMessage(ctx, "sg_g1", clientMsgID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if msg.ContentType != contentType {
+			t.Fatalf("message %s content type %d, want %d", clientMsgID, msg.ContentType, contentType)
+		}
+	}
+	quoted := quotedContentTypes(t, ctx, c)
+	if quoted["m1"] != constant.RevokeNotification || quoted["m2"] != constant.Text || quoted["m4"] != constant.Text {
+		t.Fatalf("quoted content types %v", quoted)
+	}
+}
+
+func TestRevokeUserMessages(t *testing.T) {
+	ctx, revokedSeqs := newRevokeServer(t)
+	c := newRevokeTestConversation(t, ctx, constant.GroupAdmin)
+
+	res, err := c.revokeUserMessages(ctx, "sg_g1", "u2", 0, 4500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.SuccessClientMsgIDList) != 1 || res.SuccessClientMsgIDList[0] != "m4" || len(res.FailedList) != 0 {
+		t.Fatalf("revoked %v, failed %v", res.SuccessClientMsgIDList, res.FailedList)
+	}
+	if got := fmt.Sprint(revokedSeqs()); got != "[4]" {
+		t.Fatalf("revoke requests %s", got)
+	}
+	if quoted := quotedContentTypes(t, ctx, c); quoted["m4"] != constant.RevokeNotification || quoted["m1"] != constant.Text {
+		t.Fatalf("quoted content types %v", quoted)
+	}
+	if _, err := c.revokeUserMessages(ctx, "sg_g1", "", 0, 0); err == nil {
+		t.Fatal("revoked the messages of an empty userID")
+	}
+}
@@ -54,17 +54,14 @@ func (c *Conversation) getAdvancedHistoryMessageList(ctx context.Context, req sd
 	var messageListCallback sdk.GetAdvancedHistoryMessageListCallback
 	var conversationID string
 	var startTime int64
-	var sessionType int
 	var list []*model_struct.LocalChatLog
 	var err error
 	var messageList sdk_struct.NewMsgList
 	var notStartTime bool
 	conversationID = req.ConversationID
-	lc, err := c.db.GetConversation(ctx, conversationID)
-	if err != nil {
+	if _, err := c.db.GetConversation(ctx, conversationID); err != nil {
 		return nil, err
 	}
-	sessionType = int(lc.ConversationType)
 	if req.StartClientMsgID == "" {
 		notStartTime = true
 	} else {
@@ -118,50 +115,10 @@ func (c *Conversation) getAdvancedHistoryMessageList(ctx context.Context, req sd
 		if v.Seq < thisMinSeq && v.Seq != 0 {
 			thisMinSeq = v.Seq
 		}
-		if v.Status >= constant.MsgStatusHasDeleted {
-			log.ZDebug(ctx, "this message has been deleted or exception message", "msg", v)
-			continue
-		}
-		temp := sdk_struct.MsgStruct{}
-		temp.ClientMsgID = v.ClientMsgID
-		temp.ServerMsgID = v.ServerMsgID
-		temp.CreateTime = v.CreateTime
-		temp.SendTime = v.SendTime
-		temp.SessionType = v.SessionType
-		temp.SendID = v.SendID
-		temp.RecvID = v.RecvID
-		temp.MsgFrom = v.MsgFrom
-		temp.ContentType = v.ContentType
-		temp.SenderPlatformID = v.SenderPlatformID
-		temp.SenderNickname = v.SenderNickname
-		temp.SenderFaceURL = v.SenderFaceURL
-		temp.Content = v.Content
-		temp.Seq = v.Seq
-		temp.IsRead = v.IsRead
-		temp.Status = v.Status
-		var attachedInfo sdk_struct.AttachedInfoElem
-		_ = utils.JsonStringToStruct(v.AttachedInfo, &attachedInfo)
-		temp.AttachedInfoElem = &attachedInfo
-		temp.Ex = v.Ex
-		temp.LocalEx = v.LocalEx
-		err := c.msgHandleByContentType(&temp)
-		if err != nil {
-			log.ZError(ctx, "Parsing data error", err, "temp", temp)
-			continue
-		}
-		switch sessionType {
-		case constant.WriteGroupChatType:
-			fallthrough
-		case constant.ReadGroupChatType:
-			temp.GroupID = temp.RecvID
-			temp.RecvID = c.loginUserID
-		}
-		if attachedInfo.IsPrivateChat && temp.SendTime+int64(attachedInfo.BurnDuration) < time.Now().Unix() {
-			continue
-		}
-		messageList = append(messageList, &temp)
 	}
+	messageList = c.historyMessageListOf(ctx, list)
 	log.ZDebug(ctx, "message convert and unmarshal", "unmarshal cost time", time.Since(t))
+	c.attachLinkPreviews(ctx, messageList)
 	t = time.Now()
 	if !isReverse {
 		sort.Sort(messageList)
@@ -176,6 +133,29 @@ func (c *Conversation) getAdvancedHistoryMessageList(ctx context.Context, req sd
 
 }
 
+// historyMessageListOf converts the local messages of a history page, dropping the deleted and
+// burned ones.
+func (c *Conversation) historyMessageListOf(ctx context.Context, list []*model_struct.LocalChatLog) []*sdk_struct.MsgStruct {
+	messageList := make([]*sdk_struct.MsgStruct, 0, len(list))
+	for _, v := range list {
+		if isRemovedStatus(v.Status) {
+			log.ZDebug(ctx, "this message has been deleted or exception message", "msg", v)
+			continue
+		}
+		msg, err := c.localChatLogToMsgStruct(v)
+		if err != nil {
+			log.ZError(ctx, "Parsing data error", err, "msg", v)
+			continue
+		}
+		attachedInfo := msg.AttachedInfoElem
+		if attachedInfo.IsPrivateChat && msg.SendTime+int64(attachedInfo.BurnDuration) < time.Now().Unix() {
+			continue
+		}
+		messageList = append(messageList, msg)
+	}
+	return messageList
+}
+
 func (c *Conversation) typingStatusUpdate(ctx context.Context, recvID, msgTip string) error {
 	if recvID == "" {
 		return sdkerrs.ErrArgs
@@ -246,7 +226,6 @@ func (c *Conversation) searchLocalMessages(ctx context.Context, searchParam *sdk
 	var list []*model_struct.LocalChatLog                                   // Slice to store the search results
 	conversationMap := make(map[string]*sdk.SearchByConversationResult, 10) // Map to store results grouped by conversation, with initial capacity of 10
 	var err error                                                           // Variable to store any errors encountered
-	var conversationID string                                               // Variable to store the current conversation ID
 
 	// Set the end time for the search; if SearchTimePosition is 0, use the current timestamp
 	if searchParam.SearchTimePosition == 0 {
@@ -294,15 +273,23 @@ func (c *Conversation) searchLocalMessages(ctx context.Context, searchParam *sdk
 			if len(newContentTypeList) == 0 {
 				newContentTypeList = SearchContentType
 			}
-			list, err = c.db.SearchMessageByKeyword(ctx, newContentTypeList, searchParam.KeywordList, searchParam.KeywordListMatchType,
-				searchParam.ConversationID, startTime, endTime, offset, searchParam.Count)
+			var ok bool
+			list, ok = c.searchMessageByFullText(ctx, newContentTypeList, searchParam, startTime, endTime, offset, searchParam.Count)
+			if !ok {
+				list, err = c.db.SearchMessageByKeyword(ctx, newContentTypeList, searchParam.KeywordList, searchParam.KeywordListMatchType,
+					searchParam.ConversationID, startTime, endTime, offset, searchParam.Count)
+			}
 		}
 	} else {
 		// Comprehensive search across all conversations
 		if len(searchParam.MessageTypeList) == 0 {
 			searchParam.MessageTypeList = SearchContentType
 		}
-		list, err = c.searchMessageByContentTypeAndKeyword(ctx, searchParam.MessageTypeList, searchParam.KeywordList, searchParam.KeywordListMatchType, startTime, endTime)
+		var ok bool
+		list, ok = c.searchMessageByFullText(ctx, searchParam.MessageTypeList, searchParam, startTime, endTime, 0, 0)
+		if !ok {
+			list, err = c.searchMessageByContentTypeAndKeyword(ctx, searchParam.MessageTypeList, searchParam.KeywordList, searchParam.KeywordListMatchType, startTime, endTime)
+		}
 	}
 
 	// Handle any errors encountered during the search
@@ -322,54 +309,16 @@ func (c *Conversation) searchLocalMessages(ctx context.Context, searchParam *sdk
 	log.ZDebug(ctx, "get raw data length is", len(list))
 
 	for _, v := range list {
-		temp := sdk_struct.MsgStruct{}
-		temp.ClientMsgID = v.ClientMsgID
-		temp.ServerMsgID = v.ServerMsgID
-		temp.CreateTime = v.CreateTime
-		temp.SendTime = v.SendTime
-		temp.SessionType = v.SessionType
-		temp.SendID = v.SendID
-		temp.RecvID = v.RecvID
-		temp.MsgFrom = v.MsgFrom
-		temp.ContentType = v.ContentType
-		temp.SenderPlatformID = v.SenderPlatformID
-		temp.SenderNickname = v.SenderNickname
-		temp.SenderFaceURL = v.SenderFaceURL
-		temp.Content = v.Content
-		temp.Seq = v.Seq
-		temp.IsRead = v.IsRead
-		temp.Status = v.Status
-		var attachedInfo sdk_struct.AttachedInfoElem
-		_ = utils.JsonStringToStruct(v.AttachedInfo, &attachedInfo)
-		temp.AttachedInfoElem = &attachedInfo
-		temp.Ex = v.Ex
-		temp.LocalEx = v.LocalEx
-		err := c.msgHandleByContentType(&temp)
+		temp, err := c.localChatLogToMsgStruct(v)
 		if err != nil {
 			// log.Error("", "Parsing data error:", err.Error(), temp)
-			log.ZError(ctx, "Parsing data error:", err, "msg", temp)
+			log.ZError(ctx, "Parsing data error:", err, "msg", v)
 			continue
 		}
-		if c.filterMsg(&temp, searchParam) {
+		conversationID := utils.GetConversationIDByMsg(temp)
+		if c.filterMsg(temp, searchParam) {
 			continue
 		}
-		// Determine the conversation ID based on the session type
-		switch temp.SessionType {
-		case constant.SingleChatType:
-			if temp.SendID == c.loginUserID {
-				conversationID = c.getConversationIDBySessionType(temp.RecvID, constant.SingleChatType)
-			} else {
-				conversationID = c.getConversationIDBySessionType(temp.SendID, constant.SingleChatType)
-			}
-		case constant.WriteGroupChatType:
-			temp.GroupID = temp.RecvID
-			temp.RecvID = c.loginUserID
-			conversationID = c.getConversationIDBySessionType(temp.GroupID, constant.WriteGroupChatType)
-		case constant.ReadGroupChatType:
-			temp.GroupID = temp.RecvID
-			temp.RecvID = c.loginUserID
-			conversationID = c.getConversationIDBySessionType(temp.GroupID, constant.ReadGroupChatType)
-		}
 		// Populate the conversationMap with search results
 		if oldItem, ok := conversationMap[conversationID]; !ok {
 			searchResultItem := sdk.SearchByConversationResult{}
@@ -383,12 +332,12 @@ func (c *Conversation) searchLocalMessages(ctx context.Context, searchParam *sdk
 			searchResultItem.ShowName = localConversation.ShowName
 			searchResultItem.LatestMsgSendTime = localConversation.LatestMsgSendTime
 			searchResultItem.ConversationType = localConversation.ConversationType
-			searchResultItem.MessageList = append(searchResultItem.MessageList, &temp)
+			searchResultItem.MessageList = append(searchResultItem.MessageList, temp)
 			searchResultItem.MessageCount++
 			conversationMap[conversationID] = &searchResultItem
 		} else {
 			oldItem.MessageCount++
-			oldItem.MessageList = append(oldItem.MessageList, &temp)
+			oldItem.MessageList = append(oldItem.MessageList, temp)
 			conversationMap[conversationID] = oldItem
 		}
 	}
@@ -407,6 +356,27 @@ func (c *Conversation) searchLocalMessages(ctx context.Context, searchParam *sdk
 	return &r, nil // Return the final search results
 }
 
+// searchMessageByFullText searches the full text index, it reports false when the index is not
+// available or does not cover the content types, and the chat logs have to be scanned instead.
+func (c *Conversation) searchMessageByFullText(ctx context.Context, contentType []int, searchParam *sdk.SearchLocalMessagesParams,
+	startTime, endTime int64, offset, count int) ([]*model_struct.LocalChatLog, bool) {
+	if len(searchParam.KeywordList) == 0 {
+		return nil, false
+	}
+	for _, v := range contentType {
+		if !utils.IsContainInt(v, SearchContentType) {
+			return nil, false
+		}
+	}
+	list, err := c.db.SearchMessageByFullText(ctx, contentType, searchParam.KeywordList, searchParam.KeywordListMatchType,
+		searchParam.ConversationID, startTime, endTime, offset, count)
+	if err != nil {
+		log.ZWarn(ctx, "full text search failed, scan the chat logs", err, "conversationID", searchParam.ConversationID)
+		return nil, false
+	}
+	return list, true
+}
+
 func (c *Conversation) searchMessageByContentTypeAndKeyword(ctx context.Context, contentType []int, keywordList []string,
 	keywordListMatchType int, startTime, endTime int64) (result []*model_struct.LocalChatLog, err error) {
 	var list []*model_struct.LocalChatLog
@@ -472,6 +442,12 @@ func (c *Conversation) filterMsg(temp *sdk_struct.MsgStruct, searchParam *sdk.Se
 	case constant.Custom:
 		return !c.judgeMultipleSubString(searchParam.KeywordList, temp.CustomElem.Description,
 			searchParam.KeywordListMatchType)
+	case constant.Poll:
+		return !c.judgeMultipleSubString(searchParam.KeywordList, temp.PollElem.Question,
+			searchParam.KeywordListMatchType)
+	case constant.AdvancedText:
+		return !c.judgeMultipleSubString(searchParam.KeywordList, temp.AdvancedTextElem.Text,
+			searchParam.KeywordListMatchType)
 	case constant.Quote:
 		if !c.judgeMultipleSubString(searchParam.KeywordList, temp.QuoteElem.Text, searchParam.KeywordListMatchType) {
 			return c.filterMsg(temp.QuoteElem.QuoteMessage, searchParam)
@@ -0,0 +1,225 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversation_msg
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/tools/log"
+)
+
+// conversationDeltaMaxWindow is the longest time changes may be held back to be coalesced.
+const conversationDeltaMaxWindow = time.Second * 10
+
+// ConversationPatch holds the fields of a conversation changed since the previous delta, keyed by
+// their JSON names. A new conversation, or one not seen before, carries all its fields. latestMsg
+// is delivered as a JSON object instead of an embedded string.
+type ConversationPatch struct {
+	ConversationID string                     `json:"conversationID"`
+	IsNew          bool                       `json:"isNew"`
+	Fields         map[string]json.RawMessage `json:"fields"`
+}
+
+// ConversationDelta is delivered by OnConversationPatched. Seq grows by one with every delta since
+// the mode was enabled, a gap means an update was missed and the list should be reloaded.
+type ConversationDelta struct {
+	Seq              int64                `json:"seq"`
+	ConversationList []*ConversationPatch `json:"conversationList"`
+}
+
+type pendingConversationPatch struct {
+	conversation *model_struct.LocalConversation
+	isNew        bool
+}
+
+// conversationDelta coalesces the conversation changes into patches when the delta mode is enabled.
+type conversationDelta struct {
+	conv *Conversation
+
+	flushLock sync.Mutex // keeps the deltas delivered in seq order
+	lock      sync.Mutex
+	enabled   bool
+	window    time.Duration
+	seq       int64
+	snapshot  map[string]map[string]json.RawMessage // fields last delivered per conversation
+	pending   map[string]*pendingConversationPatch
+	order     []string
+	timer     *time.Timer
+}
+
+func (d *conversationDelta) setMode(ctx context.Context, enabled bool, window time.Duration) error {
+	if window < 0 || window > conversationDeltaMaxWindow {
+		return sdkerrs.ErrArgs.WrapMsg("invalid coalesce window", "window", window)
+	}
+	// Changes held back are still delivered as a delta before the mode changes.
+	d.flush()
+	var snapshot map[string]map[string]json.RawMessage
+	if enabled {
+		conversations, err := d.conv.db.GetAllConversationListDB(ctx)
+		if err != nil {
+			return err
+		}
+		snapshot = make(map[string]map[string]json.RawMessage, len(conversations))
+		for _, conversation := range conversations {
+			if fields, err := conversationFields(conversation); err == nil {
+				snapshot[conversation.ConversationID] = fields
+			}
+		}
+	}
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.enabled = enabled
+	d.window = window
+	d.seq = 0
+	d.snapshot = snapshot
+	return nil
+}
+
+// add queues the conversations for the next delta, it returns false when the delta mode is disabled
+// and the full conversations should be delivered instead.
+func (d *conversationDelta) add(conversations []*model_struct.LocalConversation, isNew bool) bool {
+	d.lock.Lock()
+	if !d.enabled {
+		d.lock.Unlock()
+		return false
+	}
+	if d.pending == nil {
+		d.pending = make(map[string]*pendingConversationPatch)
+	}
+	for _, conversation := range conversations {
+		if p, ok := d.pending[conversation.ConversationID]; ok {
+			p.conversation = conversation
+			p.isNew = p.isNew || isNew
+			continue
+		}
+		d.pending[conversation.ConversationID] = &pendingConversationPatch{conversation: conversation, isNew: isNew}
+		d.order = append(d.order, conversation.ConversationID)
+	}
+	window := d.window
+	if window > 0 && d.timer == nil {
+		d.timer = time.AfterFunc(window, d.flush)
+	}
+	d.lock.Unlock()
+	if window == 0 {
+		d.flush()
+	}
+	return true
+}
+
+// addJSON is add for the conversation lists already marshaled by the callers.
+func (d *conversationDelta) addJSON(conversationList string, isNew bool) bool {
+	if !d.isEnabled() {
+		return false
+	}
+	var conversations []*model_struct.LocalConversation
+	if err := json.Unmarshal([]byte(conversationList), &conversations); err != nil {
+		log.ZWarn(context.Background(), "unmarshal conversation list failed", err, "conversationList", conversationList)
+		return false
+	}
+	return d.add(conversations, isNew)
+}
+
+// stop drops the changes held back and disables the delta mode, on logout.
+func (d *conversationDelta) stop() {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+	d.pending, d.order = nil, nil
+	d.enabled = false
+	d.seq = 0
+	d.snapshot = nil
+}
+
+func (d *conversationDelta) isEnabled() bool {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return d.enabled
+}
+
+func (d *conversationDelta) flush() {
+	d.flushLock.Lock()
+	defer d.flushLock.Unlock()
+	d.lock.Lock()
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+	pending, order := d.pending, d.order
+	d.pending, d.order = nil, nil
+	var delta ConversationDelta
+	for _, conversationID := range order {
+		p := pending[conversationID]
+		fields, err := conversationFields(p.conversation)
+		if err != nil {
+			log.ZWarn(context.Background(), "conversationFields failed", err, "conversationID", conversationID)
+			continue
+		}
+		patch := &ConversationPatch{ConversationID: conversationID, IsNew: p.isNew, Fields: fields}
+		if last, ok := d.snapshot[conversationID]; ok && !p.isNew {
+			patch.Fields = diffConversationFields(last, fields)
+		}
+		if d.snapshot != nil {
+			d.snapshot[conversationID] = fields
+		}
+		if len(patch.Fields) == 0 {
+			continue
+		}
+		delta.ConversationList = append(delta.ConversationList, patch)
+	}
+	if len(delta.ConversationList) == 0 {
+		d.lock.Unlock()
+		return
+	}
+	d.seq++
+	delta.Seq = d.seq
+	d.lock.Unlock()
+	d.conv.ConversationListener().OnConversationPatched(utils.StructToJsonString(delta))
+}
+
+// conversationFields splits a conversation into its JSON fields, with latestMsg unwrapped.
+func conversationFields(conversation *model_struct.LocalConversation) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(conversation)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	if conversation.LatestMsg != "" && json.Valid([]byte(conversation.LatestMsg)) {
+		fields["latestMsg"] = json.RawMessage(conversation.LatestMsg)
+	}
+	return fields, nil
+}
+
+func diffConversationFields(last, fields map[string]json.RawMessage) map[string]json.RawMessage {
+	diff := make(map[string]json.RawMessage)
+	for key, value := range fields {
+		if !bytes.Equal(last[key], value) {
+			diff[key] = value
+		}
+	}
+	return diff
+}
@@ -0,0 +1,160 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !js
+
+package conversation_msg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+)
+
+func newDeltaTestConversation(t *testing.T, window time.Duration) (*Conversation, *testListener, *model_struct.LocalConversation) {
+	ctx := context.Background()
+	c, listener := newTestConversation(t, "u1")
+	conversation := &model_struct.LocalConversation{ConversationID: "si_u1_u2", ConversationType: constant.SingleChatType,
+		UserID: "u2", ShowName: "u2", LatestMsg: `{"clientMsgID":"m1"}`, LatestMsgSendTime: 1}
+	if err := c.db.InsertConversation(ctx, conversation); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.delta.setMode(ctx, true, window); err != nil {
+		t.Fatal(err)
+	}
+	return c, listener, conversation
+}
+
+func patchedDeltas(t *testing.T, listener *testListener) []*ConversationDelta {
+	var res []*ConversationDelta
+	for _, v := range listener.get("OnConversationPatched") {
+		var delta ConversationDelta
+		if err := json.Unmarshal([]byte(v), &delta); err != nil {
+			t.Fatal(err)
+		}
+		res = append(res, &delta)
+	}
+	return res
+}
+
+func TestConversationDeltaFields(t *testing.T) {
+	c, listener, conversation := newDeltaTestConversation(t, 0)
+	changed := *conversation
+	changed.ShowName, changed.LatestMsg = "renamed", `{"clientMsgID":"m2"}`
+	if !c.delta.add([]*model_struct.LocalConversation{&changed}, false) {
+		t.Fatal("the delta mode is disabled")
+	}
+	// unchanged conversations are not delivered
+	c.delta.add([]*model_struct.LocalConversation{&changed}, false)
+	newConversation := &model_struct.LocalConversation{ConversationID: "sg_g1", ConversationType: constant.ReadGroupChatType, GroupID: "g1"}
+	c.delta.add([]*model_struct.LocalConversation{newConversation}, true)
+
+	deltas := patchedDeltas(t, listener)
+	if len(deltas) != 2 || deltas[0].Seq != 1 || deltas[1].Seq != 2 {
+		t.Fatalf("deltas %s", listener.get("OnConversationPatched"))
+	}
+	patch := deltas[0].ConversationList[0]
+	keys := make([]string, 0, len(patch.Fields))
+	for key := range patch.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	if patch.IsNew || fmt.Sprint(keys) != "[latestMsg showName]" || string(patch.Fields["latestMsg"]) != `{"clientMsgID":"m2"}` {
+		t.Fatalf("patch %v %s", patch.IsNew, listener.get("OnConversationPatched")[0])
+	}
+	if patch := deltas[1].ConversationList[0]; !patch.IsNew || patch.ConversationID != "sg_g1" || string(patch.Fields["groupID"]) != `"g1"` {
+		t.Fatalf("new conversation patch %+v", patch)
+	}
+}
+
+func TestConversationDeltaCoalesce(t *testing.T) {
+	c, listener, conversation := newDeltaTestConversation(t, 50*time.Millisecond)
+	renamed := *conversation
+	renamed.ShowName = "first"
+	c.delta.add([]*model_struct.LocalConversation{&renamed}, false)
+	c.delta.add([]*model_struct.LocalConversation{{ConversationID: "sg_g1", GroupID: "g1"}}, true)
+	renamedAgain := renamed
+	renamedAgain.ShowName = "second"
+	c.delta.add([]*model_struct.LocalConversation{&renamedAgain}, false)
+	if got := listener.get("OnConversationPatched"); len(got) != 0 {
+		t.Fatalf("delivered before the window ended %s", got)
+	}
+	for deadline := time.Now().Add(2 * time.Second); len(listener.get("OnConversationPatched")) == 0; {
+		if time.Now().After(deadline) {
+			t.Fatal("the coalesced delta was not delivered")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	deltas := patchedDeltas(t, listener)
+	if len(deltas) != 1 || deltas[0].Seq != 1 || len(deltas[0].ConversationList) != 2 {
+		t.Fatalf("deltas %s", listener.get("OnConversationPatched"))
+	}
+	if patch := deltas[0].ConversationList[0]; patch.ConversationID != "si_u1_u2" || string(patch.Fields["showName"]) != `"second"` {
+		t.Fatalf("coalesced patch %+v", patch)
+	}
+	if patch := deltas[0].ConversationList[1]; patch.ConversationID != "sg_g1" || !patch.IsNew {
+		t.Fatalf("coalesced patch %+v", patch)
+	}
+}
+
+func TestConversationDeltaSeqOrder(t *testing.T) {
+	c, listener, conversation := newDeltaTestConversation(t, 0)
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			changed := *conversation
+			changed.ShowName = fmt.Sprintf("name %d", i)
+			c.delta.add([]*model_struct.LocalConversation{&changed}, false)
+		}(i)
+	}
+	wg.Wait()
+	deltas := patchedDeltas(t, listener)
+	if len(deltas) == 0 {
+		t.Fatal("no delta delivered")
+	}
+	for i, delta := range deltas {
+		if delta.Seq != int64(i+1) {
+			t.Fatalf("delta %d has seq %d", i, delta.Seq)
+		}
+	}
+}
+
+func TestConversationDeltaClose(t *testing.T) {
+	c, listener, conversation := newDeltaTestConversation(t, conversationDeltaMaxWindow)
+	changed := *conversation
+	changed.ShowName = "renamed"
+	c.delta.add([]*model_struct.LocalConversation{&changed}, false)
+	c.Close(context.Background())
+	c.delta.lock.Lock()
+	timer, pending := c.delta.timer, len(c.delta.pending)
+	c.delta.lock.Unlock()
+	if timer != nil || pending != 0 {
+		t.Fatalf("timer %v, %d pending after logout", timer, pending)
+	}
+	if c.delta.add([]*model_struct.LocalConversation{&changed}, false) {
+		t.Fatal("the delta mode is still enabled after logout")
+	}
+	if got := listener.get("OnConversationPatched"); len(got) != 0 {
+		t.Fatalf("delivered after logout %s", got)
+	}
+}
@@ -45,7 +45,7 @@ const (
 	searchMessageGoroutineLimit       = 10
 )
 
-var SearchContentType = []int{constant.Text, constant.AtText, constant.File}
+var SearchContentType = []int{constant.Text, constant.AtText, constant.File, constant.Quote, constant.AdvancedText, constant.Merger}
 
 type Conversation struct {
 	*interaction.LongConnMgr
@@ -67,13 +67,28 @@ type Conversation struct {
 	cache                 *cache.Cache[string, *model_struct.LocalConversation]
 	maxSeqRecorder        MaxSeqRecorder
 	IsExternalExtensions  bool
+	EnableLinkPreview     bool
 	msgOffset             int
 	progress              int
 	conversationSyncMutex sync.Mutex
 
 	startTime time.Time
 
-	typing *typing
+	typing       *typing
+	liveLocation *liveLocation
+
+	interceptors interceptors
+	sensitive    sensitiveWordFilter
+	folders      conversationFolders
+	timer        conversationTimer
+	offlinePush  offlinePush
+	delta        conversationDelta
+
+	unreadBreakdownLock sync.Mutex
+	unreadBreakdown     *sdk_struct.UnreadCountBreakdown
+
+	// deliveryLock orders the delivery receipts with the acks of the messages they acknowledge.
+	deliveryLock sync.Mutex
 }
 
 func (c *Conversation) SetMsgListener(msgListener func() open_im_sdk_callback.OnAdvancedMsgListener) {
@@ -107,16 +122,30 @@ func NewConversation(ctx context.Context, longConnMgr *interaction.LongConnMgr,
 		user:                 user,
 		file:                 file,
 		IsExternalExtensions: info.IsExternalExtensions(),
+		EnableLinkPreview:    info.EnableLinkPreview(),
 		maxSeqRecorder:       NewMaxSeqRecorder(),
 		msgOffset:            0,
 		progress:             0,
 	}
 	n.typing = newTyping(n)
+	n.liveLocation = newLiveLocation(n)
+	n.delta.conv = n
+	n.offlinePush.enabled = len(info.OfflinePushTemplates()) > 0
+	n.offlinePush.template = resolveOfflinePushTemplate(info.OfflinePushLanguage(), info.OfflinePushTemplates())
 	n.initSyncer()
 	n.cache = cache.NewCache[string, *model_struct.LocalConversation]()
+	n.scheduleConversationTimers(ctx, 0)
 	return n
 }
 
+// Close stops the background work started for the login user, it is called on logout.
+func (c *Conversation) Close(ctx context.Context) {
+	c.liveLocation.stopAll(ctx)
+	c.timer.stop()
+	c.delta.stop()
+	c.typing.stopDelayed()
+}
+
 func (c *Conversation) initSyncer() {
 	c.conversationSyncer = syncer.New2[*model_struct.LocalConversation, pbConversation.GetOwnerConversationResp, string](
 		syncer.WithInsert[*model_struct.LocalConversation, pbConversation.GetOwnerConversationResp, string](func(ctx context.Context, value *model_struct.LocalConversation) error {
@@ -220,6 +249,7 @@ func (c *Conversation) doMsgNew(c2v common.Cmd2Value) {
 	b := time.Now()
 
 	onlineMap := make(map[onlineMsgKey]struct{})
+	deliveryReceipts := make(map[deliveryReceiptKey][]string)
 
 	for conversationID, msgs := range allMsg {
 		log.ZDebug(ctx, "parse message in one conversation", "conversationID",
@@ -273,6 +303,15 @@ func (c *Conversation) doMsgNew(c2v common.Cmd2Value) {
 				log.ZError(ctx, "conversationID is empty", errors.New("conversationID is empty"), "msg", msg)
 				continue
 			}
+			if v.SendID != c.loginUserID {
+				if err := c.postReceive(ctx, conversationID, msg); err != nil {
+					// keep the dropped message as filtered, so the seq does not look lost to the continuity check.
+					log.ZInfo(ctx, "message dropped by interceptor", "msg", msg, "err", err)
+					msg.Status = constant.MsgStatusFiltered
+					insertMessage = append(insertMessage, c.msgStructToLocalChatLog(msg))
+					continue
+				}
+			}
 			if !isHistory {
 				onlineMap[onlineMsgKey{ClientMsgID: v.ClientMsgID, ServerMsgID: v.ServerMsgID}] = struct{}{}
 				newMessages = append(newMessages, msg)
@@ -349,6 +388,7 @@ func (c *Conversation) doMsgNew(c2v common.Cmd2Value) {
 					}
 					if isHistory {
 						othersInsertMessage = append(othersInsertMessage, c.msgStructToLocalChatLog(msg))
+						collectDeliveryReceipt(deliveryReceipts, msg)
 					}
 
 				} else {
@@ -392,6 +432,7 @@ func (c *Conversation) doMsgNew(c2v common.Cmd2Value) {
 
 	//Normal message storage
 	_ = c.batchInsertMessageList(ctx, insertMsg)
+	c.sendDeliveryReceipts(ctx, deliveryReceipts)
 
 	hList, _ := c.db.GetHiddenConversationList(ctx)
 	for _, v := range hList {
@@ -430,6 +471,8 @@ func (c *Conversation) doMsgNew(c2v common.Cmd2Value) {
 		log.ZError(ctx, "insert new conversation err:", err)
 	}
 	log.ZDebug(ctx, "before trigger msg", "cost time", time.Since(b).Seconds(), "len", len(allMsg))
+	c.attachLinkPreviews(ctx, newMessages)
+	c.prefetchLinkPreviews(ctx, newMessages)
 
 	if c.batchMsgListener() != nil {
 		c.batchNewMessages(ctx, newMessages, conversationChangedSet, newConversationSet, onlineMap)
@@ -447,10 +490,17 @@ func (c *Conversation) doMsgNew(c2v common.Cmd2Value) {
 		c.doUpdateConversation(common.Cmd2Value{Value: common.UpdateConNode{Action: constant.TotalUnreadMessageChanged, Args: ""}})
 	}
 
+	c.doConversationMsgNotification(ctx, allMsg)
+
 	for _, msgs := range allMsg {
 		for _, msg := range msgs.Msgs {
-			if msg.ContentType == constant.Typing {
+			switch msg.ContentType {
+			case constant.Typing:
 				c.typing.onNewMsg(ctx, msg)
+			case constant.DeliveryReceipt:
+				c.doDeliveryReceipt(ctx, msg)
+			case constant.LiveLocation:
+				c.liveLocation.onNewMsg(ctx, msg)
 			}
 		}
 	}
@@ -518,6 +568,12 @@ func (c *Conversation) doMsgSyncByReinstalled(c2v common.Cmd2Value) {
 
 				selfInsertMessage = append(selfInsertMessage, c.msgStructToLocalChatLog(msg))
 			} else { //Sent by others
+				if err := c.postReceive(ctx, conversationID, msg); err != nil {
+					log.ZInfo(ctx, "message dropped by interceptor", "msg", msg, "err", err)
+					msg.Status = constant.MsgStatusFiltered
+					insertMessage = append(insertMessage, c.msgStructToLocalChatLog(msg))
+					continue
+				}
 				othersInsertMessage = append(othersInsertMessage, c.msgStructToLocalChatLog(msg))
 
 				latestMsg = msg
@@ -539,6 +595,7 @@ func (c *Conversation) doMsgSyncByReinstalled(c2v common.Cmd2Value) {
 
 	// message storage
 	_ = c.batchInsertMessageList(ctx, insertMsg)
+	c.doConversationMsgNotification(ctx, allMsg)
 
 	// conversation storage
 	if err := c.db.BatchUpdateConversationList(ctx, conversationList); err != nil {
@@ -726,7 +783,7 @@ func (c *Conversation) newMessage(ctx context.Context, newMessagesList sdk_struc
 		}
 	} else {
 		for _, w := range newMessagesList {
-			if w.ContentType == constant.Typing {
+			if isOnlineSignalContentType(w.ContentType) {
 				continue
 			}
 			if _, ok := onlineMsg[onlineMsgKey{ClientMsgID: w.ClientMsgID, ServerMsgID: w.ServerMsgID}]; ok {
@@ -773,7 +830,7 @@ func (c *Conversation) batchNewMessages(ctx context.Context, newMessagesList sdk
 		}
 	} else { // online
 		for _, w := range newMessagesList {
-			if w.ContentType == constant.Typing {
+			if isOnlineSignalContentType(w.ContentType) {
 				continue
 			}
 
@@ -799,6 +856,27 @@ func (c *Conversation) msgConvert(msg *sdk_struct.MsgStruct) (err error) {
 	}
 }
 
+// isSentStatus reports the messages accepted by the server, delivered or not.
+func isSentStatus(status int32) bool {
+	return status == constant.MsgStatusSendSuccess || status == constant.MsgStatusDelivered
+}
+
+// isRemovedStatus reports the messages kept locally but not shown, deleted or filtered.
+func isRemovedStatus(status int32) bool {
+	return status == constant.MsgStatusHasDeleted || status == constant.MsgStatusFiltered
+}
+
+// isOnlineSignalContentType reports the signal messages handled by the SDK itself, they are not
+// delivered to the message listeners.
+func isOnlineSignalContentType(contentType int32) bool {
+	switch contentType {
+	case constant.Typing, constant.DeliveryReceipt, constant.LiveLocation, constant.PollVoteNotification, constant.PollResultNotification:
+		return true
+	default:
+		return false
+	}
+}
+
 func (c *Conversation) msgHandleByContentType(msg *sdk_struct.MsgStruct) (err error) {
 	switch msg.ContentType {
 	case constant.Text:
@@ -850,6 +928,10 @@ func (c *Conversation) msgHandleByContentType(msg *sdk_struct.MsgStruct) (err er
 		t := sdk_struct.TypingElem{}
 		err = utils.JsonStringToStruct(msg.Content, &t)
 		msg.TypingElem = &t
+	case constant.LiveLocation:
+		t := sdk_struct.LiveLocationElem{}
+		err = utils.JsonStringToStruct(msg.Content, &t)
+		msg.LiveLocationElem = &t
 	case constant.Quote:
 		t := sdk_struct.QuoteElem{}
 		err = utils.JsonStringToStruct(msg.Content, &t)
@@ -866,6 +948,10 @@ func (c *Conversation) msgHandleByContentType(msg *sdk_struct.MsgStruct) (err er
 		t := sdk_struct.CardElem{}
 		err = utils.JsonStringToStruct(msg.Content, &t)
 		msg.CardElem = &t
+	case constant.Poll:
+		t := sdk_struct.PollElem{}
+		err = utils.JsonStringToStruct(msg.Content, &t)
+		msg.PollElem = &t
 	default:
 		t := sdk_struct.NotificationElem{}
 		err = utils.JsonStringToStruct(msg.Content, &t)
@@ -1013,3 +1099,33 @@ func (c *Conversation) GetInputStates(ctx context.Context, conversationID string
 func (c *Conversation) ChangeInputStates(ctx context.Context, conversationID string, focus bool) error {
 	return c.typing.ChangeInputStates(ctx, conversationID, focus)
 }
+
+func (c *Conversation) ChangeInputActivity(ctx context.Context, conversationID string, activity int32) error {
+	return c.typing.ChangeInputActivity(ctx, conversationID, activity)
+}
+
+func (c *Conversation) GetConversationInputStates(ctx context.Context, conversationID string) (*ConversationInputStates, error) {
+	return c.typing.GetConversationInputStates(ctx, conversationID), nil
+}
+
+func (c *Conversation) StartLiveLocation(ctx context.Context, conversationID string, duration int64, longitude, latitude, accuracy float64) (*LiveLocation, error) {
+	return c.liveLocation.StartLiveLocation(ctx, conversationID, duration, longitude, latitude, accuracy)
+}
+
+func (c *Conversation) UpdateLiveLocation(ctx context.Context, conversationID string, longitude, latitude, accuracy float64) error {
+	return c.liveLocation.UpdateLiveLocation(ctx, conversationID, longitude, latitude, accuracy)
+}
+
+func (c *Conversation) StopLiveLocation(ctx context.Context, conversationID string) error {
+	return c.liveLocation.StopLiveLocation(ctx, conversationID)
+}
+
+func (c *Conversation) GetLiveLocations(ctx context.Context, conversationID string) ([]*LiveLocation, error) {
+	return c.liveLocation.GetLiveLocations(conversationID), nil
+}
+
+// SetConversationDeltaMode switches OnNewConversation and OnConversationChanged to OnConversationPatched,
+// coalescing the changes made within coalesceWindow milliseconds into one delta.
+func (c *Conversation) SetConversationDeltaMode(ctx context.Context, enabled bool, coalesceWindow int64) error {
+	return c.delta.setMode(ctx, enabled, time.Duration(coalesceWindow)*time.Millisecond)
+}
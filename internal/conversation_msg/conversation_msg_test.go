@@ -0,0 +1,145 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !js
+
+package conversation_msg
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/openimsdk/openim-sdk-core/v3/open_im_sdk_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/cache"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/common"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+// testListener records the conversation and message callbacks by name.
+type testListener struct {
+	lock   sync.Mutex
+	events map[string][]string
+}
+
+func (l *testListener) add(name, data string) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.events == nil {
+		l.events = make(map[string][]string)
+	}
+	l.events[name] = append(l.events[name], data)
+}
+
+func (l *testListener) get(name string) []string {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return l.events[name]
+}
+
+func (l *testListener) reset() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.events = nil
+}
+
+func (l *testListener) OnSyncServerStart(reinstalled bool)  {}
+func (l *testListener) OnSyncServerFinish(reinstalled bool) {}
+func (l *testListener) OnSyncServerProgress(progress int)   {}
+func (l *testListener) OnSyncServerFailed(reinstalled bool) {}
+func (l *testListener) OnNewConversation(list string)       { l.add("OnNewConversation", list) }
+func (l *testListener) OnConversationChanged(list string)   { l.add("OnConversationChanged", list) }
+func (l *testListener) OnTotalUnreadMessageCountChanged(count int32) {
+	l.add("OnTotalUnreadMessageCountChanged", fmt.Sprint(count))
+}
+func (l *testListener) OnConversationUserInputStatusChanged(change string) {}
+func (l *testListener) OnConversationLiveLocationChanged(change string)    {}
+func (l *testListener) OnConversationPinnedMessagesChanged(change string)  {}
+func (l *testListener) OnConversationFolderUnreadCountChanged(counts string) {
+	l.add("OnConversationFolderUnreadCountChanged", counts)
+}
+func (l *testListener) OnUnreadCountBreakdownChanged(breakdown string) {
+	l.add("OnUnreadCountBreakdownChanged", breakdown)
+}
+func (l *testListener) OnConversationPatched(delta string) { l.add("OnConversationPatched", delta) }
+
+func (l *testListener) OnRecvNewMessage(message string)               { l.add("OnRecvNewMessage", message) }
+func (l *testListener) OnRecvC2CReadReceipt(list string)              {}
+func (l *testListener) OnRecvGroupReadReceipt(list string)            { l.add("OnRecvGroupReadReceipt", list) }
+func (l *testListener) OnNewRecvMessageRevoked(messageRevoked string) {}
+func (l *testListener) OnRecvOfflineNewMessage(message string)        {}
+func (l *testListener) OnMsgDeleted(message string)                   {}
+func (l *testListener) OnRecvOnlineOnlyMessage(message string)        {}
+func (l *testListener) OnPollResultChanged(pollResult string)         {}
+func (l *testListener) OnRecvMessageDelivered(list string)            { l.add("OnRecvMessageDelivered", list) }
+
+// newTestConversation returns a conversation on a new local database, without any connection.
+func newTestConversation(t *testing.T, loginUserID string) (*Conversation, *testListener) {
+	ctx := context.Background()
+	database, err := db.NewDataBase(ctx, loginUserID, t.TempDir(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = database.Close(ctx) })
+	listener := &testListener{}
+	c := &Conversation{
+		db:                   database,
+		loginUserID:          loginUserID,
+		recvCH:               make(chan common.Cmd2Value, 1000),
+		ConversationListener: func() open_im_sdk_callback.OnConversationListener { return listener },
+		msgListener:          func() open_im_sdk_callback.OnAdvancedMsgListener { return listener },
+		batchMsgListener:     func() open_im_sdk_callback.OnBatchMsgListener { return nil },
+		maxSeqRecorder:       NewMaxSeqRecorder(),
+		cache:                cache.NewCache[string, *model_struct.LocalConversation](),
+	}
+	c.delta.conv = c
+	c.liveLocation = newLiveLocation(c)
+	c.typing = newTyping(c)
+	return c, listener
+}
+
+// applyTestCommands runs the conversation updates queued on the channel, as the work loop does.
+func applyTestCommands(c *Conversation) {
+	for {
+		select {
+		case c2v := <-c.recvCH:
+			if c2v.Cmd == constant.CmdUpdateConversation {
+				c.doUpdateConversation(c2v)
+			}
+		default:
+			return
+		}
+	}
+}
+
+func newTestTextMessage(conversationID, clientMsgID, sendID string, seq, sendTime int64) *model_struct.LocalChatLog {
+	return &model_struct.LocalChatLog{
+		ClientMsgID: clientMsgID,
+		ServerMsgID: clientMsgID,
+		SendID:      sendID,
+		RecvID:      conversationID,
+		SessionType: constant.SingleChatType,
+		ContentType: constant.Text,
+		Content:     utils.StructToJsonString(sdk_struct.TextElem{Content: "text " + clientMsgID}),
+		Seq:         seq,
+		SendTime:    sendTime,
+		CreateTime:  sendTime,
+		Status:      constant.MsgStatusSendSuccess,
+	}
+}
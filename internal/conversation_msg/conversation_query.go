@@ -0,0 +1,95 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversation_msg
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	sdk "github.com/openimsdk/openim-sdk-core/v3/pkg/sdk_params_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+func (c *Conversation) queryConversationList(ctx context.Context, query *sdk_struct.ConversationQuery) (*sdk.QueryConversationListCallback, error) {
+	if query.Count <= 0 || query.Count > constant.ConversationQueryMaxCount {
+		return nil, sdkerrs.ErrArgs.WrapMsg("invalid count", "count", query.Count)
+	}
+	switch query.SortBy {
+	case constant.ConversationSortByLatestMsg, constant.ConversationSortByUnreadCount, constant.ConversationSortByName:
+	default:
+		return nil, sdkerrs.ErrArgs.WrapMsg("invalid sortBy", "sortBy", query.SortBy)
+	}
+	for _, filter := range []int32{query.PinnedFilter, query.MutedFilter} {
+		switch filter {
+		case constant.ConversationQueryFilterAny, constant.ConversationQueryFilterOnly, constant.ConversationQueryFilterExclude:
+		default:
+			return nil, sdkerrs.ErrArgs.WrapMsg("invalid filter", "filter", filter)
+		}
+	}
+	cursor, err := decodeConversationCursor(query.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	// One more conversation than asked tells whether another page follows.
+	conversations, err := c.db.QueryConversationList(ctx, query, cursor, query.Count+1)
+	if err != nil {
+		return nil, err
+	}
+	result := &sdk.QueryConversationListCallback{IsEnd: len(conversations) <= query.Count}
+	if !result.IsEnd {
+		conversations = conversations[:query.Count]
+	}
+	result.ConversationList = conversations
+	if result.ConversationList == nil {
+		result.ConversationList = make([]*model_struct.LocalConversation, 0)
+	}
+	if len(conversations) > 0 {
+		result.NextCursor = encodeConversationCursor(query.SortBy, conversations[len(conversations)-1])
+	}
+	return result, nil
+}
+
+func encodeConversationCursor(sortBy int32, conversation *model_struct.LocalConversation) string {
+	cursor := sdk_struct.ConversationCursor{IsPinned: conversation.IsPinned, ConversationID: conversation.ConversationID}
+	switch sortBy {
+	case constant.ConversationSortByUnreadCount:
+		cursor.SortKey = int64(conversation.UnreadCount)
+	case constant.ConversationSortByName:
+		cursor.SortName = conversation.ShowName
+	default:
+		cursor.SortKey = max(conversation.LatestMsgSendTime, conversation.DraftTextTime)
+	}
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeConversationCursor(s string) (*sdk_struct.ConversationCursor, error) {
+	if s == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, sdkerrs.ErrArgs.WrapMsg("invalid cursor", "cursor", s)
+	}
+	var cursor sdk_struct.ConversationCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ConversationID == "" {
+		return nil, sdkerrs.ErrArgs.WrapMsg("invalid cursor", "cursor", s)
+	}
+	return &cursor, nil
+}
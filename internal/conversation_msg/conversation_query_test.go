@@ -0,0 +1,72 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !js
+
+package conversation_msg
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+func TestQueryConversationListByLatestMsg(t *testing.T) {
+	ctx := context.Background()
+	c, _ := newTestConversation(t, "u1")
+	conversations := []*model_struct.LocalConversation{
+		{ConversationID: "si_u1_u2", ConversationType: constant.SingleChatType, LatestMsgSendTime: 3000},
+		{ConversationID: "si_u1_u3", ConversationType: constant.SingleChatType, LatestMsgSendTime: 1000, DraftText: "draft", DraftTextTime: 4000},
+		{ConversationID: "si_u1_u4", ConversationType: constant.SingleChatType, LatestMsgSendTime: 2000, IsPinned: true},
+	}
+	for _, conversation := range conversations {
+		if err := c.db.InsertConversation(ctx, conversation); err != nil {
+			t.Fatal(err)
+		}
+	}
+	list := func(query sdk_struct.ConversationQuery) string {
+		t.Helper()
+		var ids []string
+		query.Count = 1
+		for {
+			res, err := c.queryConversationList(ctx, &query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, conversation := range res.ConversationList {
+				ids = append(ids, conversation.ConversationID)
+			}
+			if res.IsEnd {
+				return fmt.Sprint(ids)
+			}
+			query.Cursor = res.NextCursor
+		}
+	}
+	if got := list(sdk_struct.ConversationQuery{}); got != "[si_u1_u3 si_u1_u2 si_u1_u4]" {
+		t.Fatalf("conversations %s", got)
+	}
+	if got := list(sdk_struct.ConversationQuery{PinnedFirst: true}); got != "[si_u1_u4 si_u1_u3 si_u1_u2]" {
+		t.Fatalf("pinned first conversations %s", got)
+	}
+	if got := list(sdk_struct.ConversationQuery{PinnedFilter: constant.ConversationQueryFilterOnly}); got != "[si_u1_u4]" {
+		t.Fatalf("pinned conversations %s", got)
+	}
+	if _, err := c.queryConversationList(ctx, &sdk_struct.ConversationQuery{Count: 1, MutedFilter: 3}); err == nil {
+		t.Fatal("an invalid filter was accepted")
+	}
+}
@@ -0,0 +1,282 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversation_msg
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/common"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+	pbConversation "github.com/openimsdk/protocol/conversation"
+	"github.com/openimsdk/protocol/wrapperspb"
+	"github.com/openimsdk/tools/errs"
+	"github.com/openimsdk/tools/log"
+)
+
+// conversationTimerRetryInterval is the delay before restoring a conversation again after a failure.
+const conversationTimerRetryInterval = time.Minute
+
+// conversationTimer is armed for the nearest mute or snooze deadline stored in the database,
+// so the previous state is restored on time, and on the next login if the SDK was not running.
+type conversationTimer struct {
+	lock  sync.Mutex
+	timer *time.Timer
+}
+
+func (t *conversationTimer) reset(ctx context.Context, ch chan common.Cmd2Value, delay time.Duration) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.timer != nil {
+		t.timer.Stop()
+	}
+	t.timer = time.AfterFunc(delay, func() {
+		if err := common.TriggerCmdConversationTimerExpired(ctx, ch); err != nil {
+			log.ZWarn(ctx, "trigger conversation timer expired failed", err)
+		}
+	})
+}
+
+func (t *conversationTimer) stop() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.timer != nil {
+		t.timer.Stop()
+		t.timer = nil
+	}
+}
+
+// scheduleConversationTimers arms the timer for the nearest deadline, the deadlines already passed
+// are tried again after retryDelay.
+func (c *Conversation) scheduleConversationTimers(ctx context.Context, retryDelay time.Duration) {
+	timers, err := c.db.GetConversationTimerList(ctx)
+	if err != nil {
+		log.ZWarn(ctx, "get conversation timer list failed", err)
+		return
+	}
+	next := time.Duration(-1)
+	for _, timer := range timers {
+		for _, until := range []int64{timer.MuteUntil, timer.SnoozeUntil} {
+			if until == 0 {
+				continue
+			}
+			delay := time.Until(time.UnixMilli(until))
+			if delay <= 0 {
+				delay = retryDelay
+			}
+			if next < 0 || delay < next {
+				next = delay
+			}
+		}
+	}
+	if next < 0 {
+		c.timer.stop()
+		return
+	}
+	c.timer.reset(ctx, c.GetCh(), next)
+}
+
+func (c *Conversation) getConversationTimer(ctx context.Context, conversationID string) (*model_struct.LocalConversationTimer, error) {
+	timer, err := c.db.GetConversationTimer(ctx, conversationID)
+	if errs.ErrRecordNotFound.Is(err) {
+		return &model_struct.LocalConversationTimer{ConversationID: conversationID}, nil
+	}
+	return timer, err
+}
+
+func (c *Conversation) saveConversationTimer(ctx context.Context, timer *model_struct.LocalConversationTimer) error {
+	if timer.MuteUntil == 0 && timer.SnoozeUntil == 0 {
+		return c.db.DeleteConversationTimer(ctx, timer.ConversationID)
+	}
+	return c.db.SetConversationTimer(ctx, timer)
+}
+
+func (c *Conversation) setConversationRecvMsgOpt(ctx context.Context, conversationID string, opt int32) error {
+	return c.setConversationAndSync(ctx, conversationID, &pbConversation.ConversationReq{RecvMsgOpt: wrapperspb.Int32(opt)})
+}
+
+// muteConversationUntil stops the notifications of the conversation until the time in milliseconds,
+// then the previous receive option is restored. An untilTime of 0 restores it now.
+func (c *Conversation) muteConversationUntil(ctx context.Context, conversationID string, untilTime int64) error {
+	conversation, err := c.db.GetConversation(ctx, conversationID)
+	if err != nil {
+		return err
+	}
+	timer, err := c.getConversationTimer(ctx, conversationID)
+	if err != nil {
+		return err
+	}
+	if untilTime == 0 {
+		if timer.MuteUntil == 0 {
+			return nil
+		}
+		if conversation.RecvMsgOpt == constant.ReceiveNotNotifyMessage && timer.RestoreRecvMsgOpt != constant.ReceiveNotNotifyMessage {
+			if err := c.setConversationRecvMsgOpt(ctx, conversationID, timer.RestoreRecvMsgOpt); err != nil {
+				return err
+			}
+		}
+	} else {
+		if untilTime <= time.Now().UnixMilli() {
+			return sdkerrs.ErrArgs.WrapMsg("untilTime must be in the future")
+		}
+		if timer.MuteUntil == 0 {
+			timer.RestoreRecvMsgOpt = conversation.RecvMsgOpt
+		}
+		if conversation.RecvMsgOpt != constant.ReceiveNotNotifyMessage {
+			if err := c.setConversationRecvMsgOpt(ctx, conversationID, constant.ReceiveNotNotifyMessage); err != nil {
+				return err
+			}
+		}
+	}
+	timer.MuteUntil = untilTime
+	if err := c.saveConversationTimer(ctx, timer); err != nil {
+		return err
+	}
+	c.scheduleConversationTimers(ctx, 0)
+	return nil
+}
+
+// snoozeConversationUntil hides the conversation from the conversation list until the time in
+// milliseconds. A new message shows it again earlier. An untilTime of 0 shows it now.
+func (c *Conversation) snoozeConversationUntil(ctx context.Context, conversationID string, untilTime int64) error {
+	conversation, err := c.db.GetConversation(ctx, conversationID)
+	if err != nil {
+		return err
+	}
+	timer, err := c.getConversationTimer(ctx, conversationID)
+	if err != nil {
+		return err
+	}
+	if untilTime == 0 {
+		if timer.SnoozeUntil == 0 {
+			return nil
+		}
+		if err := c.unsnoozeConversation(ctx, conversation); err != nil {
+			return err
+		}
+	} else {
+		if untilTime <= time.Now().UnixMilli() {
+			return sdkerrs.ErrArgs.WrapMsg("untilTime must be in the future")
+		}
+		if conversation.LatestMsgSendTime != 0 {
+			if err := c.db.UpdateColumnsConversation(ctx, conversationID, map[string]interface{}{"latest_msg_send_time": 0}); err != nil {
+				return err
+			}
+			conversation.LatestMsgSendTime = 0
+			c.snoozedConversationChanged(ctx, conversation)
+		}
+	}
+	timer.SnoozeUntil = untilTime
+	if err := c.saveConversationTimer(ctx, timer); err != nil {
+		return err
+	}
+	c.scheduleConversationTimers(ctx, 0)
+	return nil
+}
+
+// snoozedConversationChanged notifies the conversation hidden by the snooze. ConChange skips the
+// conversations without a latest message time, so it is delivered here with the time set to 0.
+func (c *Conversation) snoozedConversationChanged(ctx context.Context, conversation *model_struct.LocalConversation) {
+	conversations := []*model_struct.LocalConversation{conversation}
+	if !c.delta.add(conversations, false) {
+		c.ConversationListener().OnConversationChanged(utils.StructToJsonStringDefault(conversations))
+	}
+	c.doUpdateConversation(common.Cmd2Value{Ctx: ctx, Value: common.UpdateConNode{Action: constant.TotalUnreadMessageChanged}})
+}
+
+// unsnoozeConversation shows a hidden conversation again, sorted by its latest message.
+func (c *Conversation) unsnoozeConversation(ctx context.Context, conversation *model_struct.LocalConversation) error {
+	if conversation.LatestMsgSendTime != 0 || conversation.LatestMsg == "" {
+		return nil
+	}
+	var latestMsg sdk_struct.MsgStruct
+	if err := json.Unmarshal([]byte(conversation.LatestMsg), &latestMsg); err != nil || latestMsg.SendTime == 0 {
+		log.ZWarn(ctx, "snoozed conversation has no latest message", err, "conversationID", conversation.ConversationID)
+		return nil
+	}
+	if err := c.db.UpdateColumnsConversation(ctx, conversation.ConversationID, map[string]interface{}{"latest_msg_send_time": latestMsg.SendTime}); err != nil {
+		return err
+	}
+	c.doUpdateConversation(common.Cmd2Value{Ctx: ctx, Value: common.UpdateConNode{Action: constant.ConChange, Args: []string{conversation.ConversationID}}})
+	c.doUpdateConversation(common.Cmd2Value{Ctx: ctx, Value: common.UpdateConNode{Action: constant.TotalUnreadMessageChanged}})
+	return nil
+}
+
+// clearConversationMute drops the time bounded mute, the receive option set afterwards is kept.
+func (c *Conversation) clearConversationMute(ctx context.Context, conversationID string) error {
+	timer, err := c.getConversationTimer(ctx, conversationID)
+	if err != nil || timer.MuteUntil == 0 {
+		return err
+	}
+	timer.MuteUntil = 0
+	if err := c.saveConversationTimer(ctx, timer); err != nil {
+		return err
+	}
+	c.scheduleConversationTimers(ctx, 0)
+	return nil
+}
+
+// expireConversationTimers restores the conversations whose deadline has passed. The ones failing
+// to restore, for example when offline, are tried again later.
+func (c *Conversation) expireConversationTimers(ctx context.Context) {
+	timers, err := c.db.GetConversationTimerList(ctx)
+	if err != nil {
+		log.ZWarn(ctx, "get conversation timer list failed", err)
+		c.scheduleConversationTimers(ctx, conversationTimerRetryInterval)
+		return
+	}
+	now := time.Now().UnixMilli()
+	for _, timer := range timers {
+		expired := (timer.MuteUntil > 0 && timer.MuteUntil <= now) || (timer.SnoozeUntil > 0 && timer.SnoozeUntil <= now)
+		if !expired {
+			continue
+		}
+		conversation, err := c.db.GetConversation(ctx, timer.ConversationID)
+		if err != nil {
+			if errs.ErrRecordNotFound.Is(err) {
+				_ = c.db.DeleteConversationTimer(ctx, timer.ConversationID)
+			} else {
+				log.ZWarn(ctx, "get conversation failed", err, "conversationID", timer.ConversationID)
+			}
+			continue
+		}
+		if timer.MuteUntil > 0 && timer.MuteUntil <= now {
+			if conversation.RecvMsgOpt != constant.ReceiveNotNotifyMessage || timer.RestoreRecvMsgOpt == constant.ReceiveNotNotifyMessage {
+				timer.MuteUntil = 0
+			} else if err := c.setConversationRecvMsgOpt(ctx, timer.ConversationID, timer.RestoreRecvMsgOpt); err != nil {
+				log.ZWarn(ctx, "restore conversation recv msg opt failed", err, "conversationID", timer.ConversationID)
+			} else {
+				timer.MuteUntil = 0
+			}
+		}
+		if timer.SnoozeUntil > 0 && timer.SnoozeUntil <= now {
+			if err := c.unsnoozeConversation(ctx, conversation); err != nil {
+				log.ZWarn(ctx, "unsnooze conversation failed", err, "conversationID", timer.ConversationID)
+			} else {
+				timer.SnoozeUntil = 0
+			}
+		}
+		if err := c.saveConversationTimer(ctx, timer); err != nil {
+			log.ZWarn(ctx, "save conversation timer failed", err, "conversationID", timer.ConversationID)
+		}
+	}
+	c.scheduleConversationTimers(ctx, conversationTimerRetryInterval)
+}
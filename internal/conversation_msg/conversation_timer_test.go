@@ -0,0 +1,62 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !js
+
+package conversation_msg
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+)
+
+func TestSnoozeConversation(t *testing.T) {
+	ctx := context.Background()
+	c, listener := newTestConversation(t, "u1")
+	conversationID := "si_u1_u2"
+	if err := c.db.InsertConversation(ctx, &model_struct.LocalConversation{ConversationID: conversationID,
+		ConversationType: constant.SingleChatType, UserID: "u2", LatestMsgSendTime: 100, UnreadCount: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.snoozeConversationUntil(ctx, conversationID, time.Now().Add(time.Hour).UnixMilli()); err != nil {
+		t.Fatal(err)
+	}
+	changed := listener.get("OnConversationChanged")
+	if len(changed) != 1 {
+		t.Fatalf("conversation changed callbacks %v", changed)
+	}
+	var conversations []*model_struct.LocalConversation
+	if err := json.Unmarshal([]byte(changed[0]), &conversations); err != nil {
+		t.Fatal(err)
+	}
+	if len(conversations) != 1 || conversations[0].ConversationID != conversationID || conversations[0].LatestMsgSendTime != 0 {
+		t.Fatalf("snoozed conversation %s", changed[0])
+	}
+	if got := listener.get("OnTotalUnreadMessageCountChanged"); len(got) != 1 || got[0] != "0" {
+		t.Fatalf("total unread callbacks %v", got)
+	}
+	if c.timer.timer == nil {
+		t.Fatal("snooze timer not armed")
+	}
+
+	c.Close(ctx)
+	if c.timer.timer != nil {
+		t.Fatal("snooze timer kept after close")
+	}
+}
@@ -15,7 +15,10 @@
 package conversation_msg
 
 import (
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
 
 	pbConversation "github.com/openimsdk/protocol/conversation"
 )
@@ -55,3 +58,37 @@ func LocalConversationToServer(conversation *model_struct.LocalConversation) *pb
 		IsMsgDestruct:    conversation.IsMsgDestruct,
 	}
 }
+
+func (c *Conversation) localChatLogToMsgStruct(v *model_struct.LocalChatLog) (*sdk_struct.MsgStruct, error) {
+	temp := sdk_struct.MsgStruct{}
+	temp.ClientMsgID = v.ClientMsgID
+	temp.ServerMsgID = v.ServerMsgID
+	temp.CreateTime = v.CreateTime
+	temp.SendTime = v.SendTime
+	temp.SessionType = v.SessionType
+	temp.SendID = v.SendID
+	temp.RecvID = v.RecvID
+	temp.MsgFrom = v.MsgFrom
+	temp.ContentType = v.ContentType
+	temp.SenderPlatformID = v.SenderPlatformID
+	temp.SenderNickname = v.SenderNickname
+	temp.SenderFaceURL = v.SenderFaceURL
+	temp.Content = v.Content
+	temp.Seq = v.Seq
+	temp.IsRead = v.IsRead
+	temp.Status = v.Status
+	var attachedInfo sdk_struct.AttachedInfoElem
+	_ = utils.JsonStringToStruct(v.AttachedInfo, &attachedInfo)
+	temp.AttachedInfoElem = &attachedInfo
+	temp.Ex = v.Ex
+	temp.LocalEx = v.LocalEx
+	if err := c.msgHandleByContentType(&temp); err != nil {
+		return nil, err
+	}
+	switch v.SessionType {
+	case constant.WriteGroupChatType, constant.ReadGroupChatType:
+		temp.GroupID = temp.RecvID
+		temp.RecvID = c.loginUserID
+	}
+	return &temp, nil
+}
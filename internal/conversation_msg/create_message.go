@@ -23,8 +23,10 @@ import (
 
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/linkpreview"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
 	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
@@ -36,7 +38,7 @@ func (c *Conversation) CreateTextMessage(ctx context.Context, text string) (*sdk
 	if err != nil {
 		return nil, err
 	}
-	s.TextElem = &sdk_struct.TextElem{Content: text}
+	s.TextElem = &sdk_struct.TextElem{Content: text, MessageEntityList: linkpreview.ExtractURLEntities(text)}
 	return &s, nil
 }
 func (c *Conversation) CreateAdvancedTextMessage(ctx context.Context, text string, messageEntities []*sdk_struct.MessageEntity) (*sdk_struct.MsgStruct, error) {
@@ -47,7 +49,7 @@ func (c *Conversation) CreateAdvancedTextMessage(ctx context.Context, text strin
 	}
 	s.AdvancedTextElem = &sdk_struct.AdvancedTextElem{
 		Text:              text,
-		MessageEntityList: messageEntities,
+		MessageEntityList: mergeURLEntities(text, messageEntities),
 	}
 	return &s, nil
 }
@@ -107,6 +109,39 @@ func (c *Conversation) CreateCustomMessage(ctx context.Context, data, extension
 	return &s, nil
 
 }
+func (c *Conversation) CreatePollMessage(ctx context.Context, question string, optionList []string, isMultiple, isAnonymous bool,
+	deadline int64) (*sdk_struct.MsgStruct, error) {
+	if question == "" {
+		return nil, sdkerrs.ErrArgs.WrapMsg("question can not be empty")
+	}
+	if len(optionList) < 2 {
+		return nil, sdkerrs.ErrArgs.WrapMsg("poll needs at least two options")
+	}
+	if deadline != 0 && deadline <= utils.GetCurrentTimestampByMill() {
+		return nil, sdkerrs.ErrArgs.WrapMsg("deadline has already passed", "deadline", deadline)
+	}
+	s := sdk_struct.MsgStruct{}
+	err := c.initBasicInfo(ctx, &s, constant.UserMsgType, constant.Poll)
+	if err != nil {
+		return nil, err
+	}
+	options := make([]*sdk_struct.PollOption, 0, len(optionList))
+	for i, text := range optionList {
+		if text == "" {
+			return nil, sdkerrs.ErrArgs.WrapMsg("poll option can not be empty")
+		}
+		options = append(options, &sdk_struct.PollOption{OptionID: strconv.Itoa(i + 1), Text: text})
+	}
+	s.PollElem = &sdk_struct.PollElem{
+		Question:    question,
+		OptionList:  options,
+		IsMultiple:  isMultiple,
+		IsAnonymous: isAnonymous,
+		Deadline:    deadline,
+	}
+	return &s, nil
+}
+
 func (c *Conversation) CreateQuoteMessage(ctx context.Context, text string, qs *sdk_struct.MsgStruct) (*sdk_struct.MsgStruct, error) {
 	s := sdk_struct.MsgStruct{}
 	err := c.initBasicInfo(ctx, &s, constant.UserMsgType, constant.Quote)
@@ -478,7 +513,7 @@ func (c *Conversation) CreateFaceMessage(ctx context.Context, index int, data st
 }
 
 func (c *Conversation) CreateForwardMessage(ctx context.Context, s *sdk_struct.MsgStruct) (*sdk_struct.MsgStruct, error) {
-	if s.Status != constant.MsgStatusSendSuccess {
+	if !isSentStatus(s.Status) {
 		log.ZError(ctx, "only send success message can be Forward",
 			errors.New("only send success message can be Forward"))
 		return nil, errors.New("only send success message can be Forward")
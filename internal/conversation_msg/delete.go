@@ -69,6 +69,7 @@ func (c *Conversation) clearConversationAndDeleteAllMsg(ctx context.Context, con
 	if err != nil {
 		return err
 	}
+	c.deleteRichDrafts(ctx, conversationID)
 	return nil
 }
 
@@ -201,6 +202,9 @@ func (c *Conversation) doDeleteMsgs(ctx context.Context, msg *sdkws.MsgData) err
 			log.ZWarn(ctx, "GetMessageBySeq err", err, "conversationID", tips.ConversationID, "seq", v)
 			continue
 		}
+		if msg.Status == constant.MsgStatusHasDeleted {
+			continue
+		}
 		var s sdk_struct.MsgStruct
 		copier.Copy(&s, msg)
 		err = c.msgConvert(&s)
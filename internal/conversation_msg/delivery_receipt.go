@@ -0,0 +1,208 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversation_msg
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/jinzhu/copier"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/common"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+	"github.com/openimsdk/protocol/sdkws"
+	"github.com/openimsdk/tools/log"
+	"github.com/openimsdk/tools/utils/datautil"
+)
+
+// deliveryReceiptMaxMsgCount is the largest number of messages acknowledged by one receipt.
+const deliveryReceiptMaxMsgCount = 100
+
+type deliveryReceiptKey struct {
+	sendID  string
+	groupID string
+}
+
+// collectDeliveryReceipt records a message stored from another user, to be acknowledged to its
+// sender. Only the chat messages of single chats and small groups are acknowledged.
+func collectDeliveryReceipt(receipts map[deliveryReceiptKey][]string, msg *sdk_struct.MsgStruct) {
+	if msg.ContentType >= constant.NotificationBegin || isOnlineSignalContentType(msg.ContentType) {
+		return
+	}
+	key := deliveryReceiptKey{sendID: msg.SendID}
+	switch msg.SessionType {
+	case constant.SingleChatType:
+	case constant.WriteGroupChatType, constant.ReadGroupChatType:
+		memberCount := msg.AttachedInfoElem.GroupHasReadInfo.GroupMemberCount
+		if memberCount == 0 || memberCount > constant.GroupReadReceiptMaxMemberCount {
+			return
+		}
+		key.groupID = msg.GroupID
+	default:
+		return
+	}
+	receipts[key] = append(receipts[key], msg.ClientMsgID)
+}
+
+// sendDeliveryReceipts acknowledges the stored messages to their senders. The receipts are sent
+// online only, they take no seq and are not counted as unread, a sender offline at that time keeps
+// the messages as sent.
+func (c *Conversation) sendDeliveryReceipts(ctx context.Context, receipts map[deliveryReceiptKey][]string) {
+	if len(receipts) == 0 {
+		return
+	}
+	go func() {
+		for key, clientMsgIDs := range receipts {
+			for start := 0; start < len(clientMsgIDs); start += deliveryReceiptMaxMsgCount {
+				chunk := clientMsgIDs[start:min(start+deliveryReceiptMaxMsgCount, len(clientMsgIDs))]
+				if err := c.sendDeliveryReceipt(ctx, key, chunk); err != nil {
+					log.ZWarn(ctx, "send delivery receipt failed", err, "sendID", key.sendID, "groupID", key.groupID)
+				}
+			}
+		}
+	}()
+}
+
+func (c *Conversation) sendDeliveryReceipt(ctx context.Context, key deliveryReceiptKey, clientMsgIDs []string) error {
+	s := sdk_struct.MsgStruct{}
+	if err := c.initBasicInfo(ctx, &s, constant.UserMsgType, constant.DeliveryReceipt); err != nil {
+		return err
+	}
+	s.RecvID = key.sendID
+	s.SessionType = constant.SingleChatType
+	s.Content = utils.StructToJsonString(sdk_struct.DeliveryReceiptElem{GroupID: key.groupID, ClientMsgIDs: clientMsgIDs})
+	options := make(map[string]bool, 7)
+	utils.SetSwitchFromOptions(options, constant.IsHistory, false)
+	utils.SetSwitchFromOptions(options, constant.IsPersistent, false)
+	utils.SetSwitchFromOptions(options, constant.IsSenderSync, false)
+	utils.SetSwitchFromOptions(options, constant.IsConversationUpdate, false)
+	utils.SetSwitchFromOptions(options, constant.IsSenderConversationUpdate, false)
+	utils.SetSwitchFromOptions(options, constant.IsUnreadCount, false)
+	utils.SetSwitchFromOptions(options, constant.IsOfflinePush, false)
+	var wsMsgData sdkws.MsgData
+	copier.Copy(&wsMsgData, s)
+	wsMsgData.Content = []byte(s.Content)
+	wsMsgData.CreateTime = s.CreateTime
+	wsMsgData.Options = options
+	var sendMsgResp sdkws.UserSendMsgResp
+	return c.LongConnMgr.SendReqWaitResp(ctx, &wsMsgData, constant.SendMsg, &sendMsgResp)
+}
+
+// doDeliveryReceipt marks the messages acknowledged by a recipient as delivered, the delivery time and
+// the group members who got them are kept in the attached info. A receipt can come before the ack
+// of the server, the ack then keeps the delivered status, see updateAckedMessage.
+func (c *Conversation) doDeliveryReceipt(ctx context.Context, msg *sdkws.MsgData) {
+	if msg.SendID == c.loginUserID {
+		return
+	}
+	var elem sdk_struct.DeliveryReceiptElem
+	if err := json.Unmarshal(msg.Content, &elem); err != nil {
+		log.ZWarn(ctx, "delivery receipt unmarshal failed", err, "message", msg)
+		return
+	}
+	var conversationID string
+	sessionType := int32(constant.SingleChatType)
+	if elem.GroupID == "" {
+		conversationID = c.getConversationIDBySessionType(msg.SendID, constant.SingleChatType)
+	} else {
+		sessionType = constant.ReadGroupChatType
+		conversationID = c.getConversationIDBySessionType(elem.GroupID, constant.ReadGroupChatType)
+	}
+	msgIDs := c.applyDeliveryReceipt(ctx, conversationID, &elem, msg)
+	if len(msgIDs) == 0 {
+		return
+	}
+	c.deliveredLatestMsg(ctx, conversationID, msgIDs, msg.SendTime)
+	receipts := []*sdk_struct.MessageDeliveryReceipt{{ConversationID: conversationID, GroupID: elem.GroupID, UserID: msg.SendID,
+		MsgIDList: msgIDs, DeliverTime: msg.SendTime, SessionType: sessionType}}
+	c.msgListener().OnRecvMessageDelivered(utils.StructToJsonString(receipts))
+}
+
+// applyDeliveryReceipt stores the delivery of the messages of the receipt, and returns the ones newly delivered.
+func (c *Conversation) applyDeliveryReceipt(ctx context.Context, conversationID string, elem *sdk_struct.DeliveryReceiptElem, msg *sdkws.MsgData) []string {
+	c.deliveryLock.Lock()
+	defer c.deliveryLock.Unlock()
+	messages, err := c.db.GetMessagesByClientMsgIDs(ctx, conversationID, elem.ClientMsgIDs)
+	if err != nil {
+		log.ZWarn(ctx, "get delivered messages failed", err, "conversationID", conversationID)
+		return nil
+	}
+	var msgIDs []string
+	for _, message := range messages {
+		if message.SendID != c.loginUserID || (message.Status != constant.MsgStatusSending && !isSentStatus(message.Status)) {
+			continue
+		}
+		attachInfo := sdk_struct.AttachedInfoElem{}
+		_ = utils.JsonStringToStruct(message.AttachedInfo, &attachInfo)
+		if elem.GroupID != "" {
+			if datautil.Contain(msg.SendID, attachInfo.DeliveredUserIDList...) {
+				continue
+			}
+			attachInfo.DeliveredUserIDList = append(attachInfo.DeliveredUserIDList, msg.SendID)
+		} else if attachInfo.DeliveredTime != 0 {
+			continue
+		}
+		if attachInfo.DeliveredTime == 0 {
+			attachInfo.DeliveredTime = msg.SendTime
+		}
+		err := c.db.UpdateColumnsMessage(ctx, conversationID, message.ClientMsgID,
+			map[string]any{"status": constant.MsgStatusDelivered, "attached_info": utils.StructToJsonString(attachInfo)})
+		if err != nil {
+			log.ZWarn(ctx, "update delivered message failed", err, "conversationID", conversationID, "clientMsgID", message.ClientMsgID)
+			continue
+		}
+		msgIDs = append(msgIDs, message.ClientMsgID)
+	}
+	return msgIDs
+}
+
+// updateAckedMessage stores the ack of the server for a message sent by the login user. A delivery
+// receipt may have come before the ack, the message then stays delivered.
+func (c *Conversation) updateAckedMessage(ctx context.Context, conversationID, clientMsgID, serverMsgID string, sendTime int64, status int32, s *sdk_struct.MsgStruct) (int32, error) {
+	c.deliveryLock.Lock()
+	defer c.deliveryLock.Unlock()
+	if status == constant.MsgStatusSendSuccess {
+		if message, err := c.db.GetMessage(ctx, conversationID, clientMsgID); err == nil && message.Status == constant.MsgStatusDelivered {
+			status = constant.MsgStatusDelivered
+			s.AttachedInfoElem = &sdk_struct.AttachedInfoElem{}
+			_ = utils.JsonStringToStruct(message.AttachedInfo, s.AttachedInfoElem)
+		}
+	}
+	return status, c.db.UpdateMessageTimeAndStatus(ctx, conversationID, clientMsgID, serverMsgID, sendTime, status)
+}
+
+// deliveredLatestMsg refreshes the latest message of the conversation when it was delivered.
+func (c *Conversation) deliveredLatestMsg(ctx context.Context, conversationID string, msgIDs []string, deliverTime int64) {
+	conversation, err := c.db.GetConversation(ctx, conversationID)
+	if err != nil {
+		return
+	}
+	latestMsg := &sdk_struct.MsgStruct{}
+	if err := json.Unmarshal([]byte(conversation.LatestMsg), latestMsg); err != nil || !datautil.Contain(latestMsg.ClientMsgID, msgIDs...) {
+		return
+	}
+	if latestMsg.AttachedInfoElem == nil {
+		latestMsg.AttachedInfoElem = &sdk_struct.AttachedInfoElem{}
+	}
+	if latestMsg.AttachedInfoElem.DeliveredTime == 0 {
+		latestMsg.AttachedInfoElem.DeliveredTime = deliverTime
+	}
+	if latestMsg.Status != constant.MsgStatusSending {
+		latestMsg.Status = constant.MsgStatusDelivered
+	}
+	conversation.LatestMsg = utils.StructToJsonString(latestMsg)
+	_ = common.TriggerCmdUpdateConversation(ctx, common.UpdateConNode{ConID: conversationID, Action: constant.AddConOrUpLatMsg, Args: *conversation}, c.GetCh())
+}
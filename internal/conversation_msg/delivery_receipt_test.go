@@ -0,0 +1,170 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !js
+
+package conversation_msg
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+	"github.com/openimsdk/protocol/sdkws"
+	"github.com/openimsdk/tools/utils/datautil"
+)
+
+func newTestDeliveryReceipt(sendID, groupID string, sendTime int64, clientMsgIDs ...string) *sdkws.MsgData {
+	return &sdkws.MsgData{SendID: sendID, RecvID: "u1", SessionType: constant.SingleChatType, ContentType: constant.DeliveryReceipt, SendTime: sendTime,
+		Content: []byte(utils.StructToJsonString(sdk_struct.DeliveryReceiptElem{GroupID: groupID, ClientMsgIDs: clientMsgIDs}))}
+}
+
+func deliveredInfo(t *testing.T, c *Conversation, conversationID, clientMsgID string) (int32, sdk_struct.AttachedInfoElem) {
+	t.Helper()
+	message, err := c.db.GetMessage(context.Background(), conversationID, clientMsgID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var attachInfo sdk_struct.AttachedInfoElem
+	_ = utils.JsonStringToStruct(message.AttachedInfo, &attachInfo)
+	return message.Status, attachInfo
+}
+
+func TestDoDeliveryReceipt(t *testing.T) {
+	ctx := context.Background()
+	c, listener := newTestConversation(t, "u1")
+	conversationID := "si_u1_u2"
+	sent := newTestTextMessage(conversationID, "m1", "u1", 1, 1000)
+	sending := newTestTextMessage(conversationID, "m2", "u1", 0, 2000)
+	sending.Status = constant.MsgStatusSending
+	received := newTestTextMessage(conversationID, "m3", "u2", 2, 3000)
+	if err := c.db.BatchInsertMessageList(ctx, conversationID, []*model_struct.LocalChatLog{sent, sending, received}); err != nil {
+		t.Fatal(err)
+	}
+
+	c.doDeliveryReceipt(ctx, newTestDeliveryReceipt("u2", "", 5000, "m1", "m2", "m3"))
+	for _, clientMsgID := range []string{"m1", "m2"} {
+		if status, attachInfo := deliveredInfo(t, c, conversationID, clientMsgID); status != constant.MsgStatusDelivered || attachInfo.DeliveredTime != 5000 {
+			t.Fatalf("%s status %d, delivered at %d", clientMsgID, status, attachInfo.DeliveredTime)
+		}
+	}
+	if status, _ := deliveredInfo(t, c, conversationID, "m3"); status != constant.MsgStatusSendSuccess {
+		t.Fatalf("message of the peer marked delivered, status %d", status)
+	}
+	receipts := listener.get("OnRecvMessageDelivered")
+	var list []*sdk_struct.MessageDeliveryReceipt
+	if len(receipts) != 1 || utils.JsonStringToStruct(receipts[0], &list) != nil || len(list) != 1 {
+		t.Fatalf("delivery callbacks %v", receipts)
+	}
+	sort.Strings(list[0].MsgIDList)
+	if got := list[0]; got.ConversationID != conversationID || got.UserID != "u2" || !datautil.Equal(got.MsgIDList, []string{"m1", "m2"}) {
+		t.Fatalf("delivery receipt %+v", got)
+	}
+
+	// the first delivery is kept, and the own receipts of other devices are ignored
+	listener.reset()
+	c.doDeliveryReceipt(ctx, newTestDeliveryReceipt("u2", "", 6000, "m1"))
+	c.doDeliveryReceipt(ctx, newTestDeliveryReceipt("u1", "", 6000, "m1"))
+	if got := listener.get("OnRecvMessageDelivered"); len(got) != 0 {
+		t.Fatalf("repeated delivery callbacks %v", got)
+	}
+	if _, attachInfo := deliveredInfo(t, c, conversationID, "m1"); attachInfo.DeliveredTime != 5000 {
+		t.Fatalf("delivered at %d, want 5000", attachInfo.DeliveredTime)
+	}
+}
+
+func TestDoGroupDeliveryReceipt(t *testing.T) {
+	ctx := context.Background()
+	c, _ := newTestConversation(t, "u1")
+	conversationID := "sg_g1"
+	msg := newTestTextMessage(conversationID, "m1", "u1", 1, 1000)
+	msg.RecvID = "g1"
+	msg.SessionType = constant.ReadGroupChatType
+	if err := c.db.BatchInsertMessageList(ctx, conversationID, []*model_struct.LocalChatLog{msg}); err != nil {
+		t.Fatal(err)
+	}
+	c.doDeliveryReceipt(ctx, newTestDeliveryReceipt("u2", "g1", 5000, "m1"))
+	c.doDeliveryReceipt(ctx, newTestDeliveryReceipt("u3", "g1", 6000, "m1"))
+	c.doDeliveryReceipt(ctx, newTestDeliveryReceipt("u2", "g1", 7000, "m1"))
+	status, attachInfo := deliveredInfo(t, c, conversationID, "m1")
+	if status != constant.MsgStatusDelivered || attachInfo.DeliveredTime != 5000 || !datautil.Equal(attachInfo.DeliveredUserIDList, []string{"u2", "u3"}) {
+		t.Fatalf("status %d, delivered at %d to %v", status, attachInfo.DeliveredTime, attachInfo.DeliveredUserIDList)
+	}
+}
+
+func TestDeliveryReceiptBeforeAck(t *testing.T) {
+	ctx := context.Background()
+	c, _ := newTestConversation(t, "u1")
+	lc := &model_struct.LocalConversation{ConversationID: "si_u1_u2", ConversationType: constant.SingleChatType, UserID: "u2"}
+	if err := c.db.InsertConversation(ctx, lc); err != nil {
+		t.Fatal(err)
+	}
+	var messages []*model_struct.LocalChatLog
+	for _, clientMsgID := range []string{"m1", "m2"} {
+		msg := newTestTextMessage(lc.ConversationID, clientMsgID, "u1", 0, 1000)
+		msg.Status = constant.MsgStatusSending
+		messages = append(messages, msg)
+	}
+	if err := c.db.BatchInsertMessageList(ctx, lc.ConversationID, messages); err != nil {
+		t.Fatal(err)
+	}
+	c.doDeliveryReceipt(ctx, newTestDeliveryReceipt("u2", "", 1500, "m1"))
+
+	ack := func(clientMsgID string) *sdk_struct.MsgStruct {
+		s := &sdk_struct.MsgStruct{ClientMsgID: clientMsgID, SendID: "u1", ContentType: constant.Text, Status: constant.MsgStatusSending}
+		c.updateMsgStatusAndTriggerConversation(ctx, clientMsgID, "server_"+clientMsgID, 2000, constant.MsgStatusSendSuccess, s, lc, false)
+		applyTestCommands(c)
+		return s
+	}
+	s := ack("m1")
+	if s.Status != constant.MsgStatusDelivered || s.AttachedInfoElem == nil || s.AttachedInfoElem.DeliveredTime != 1500 {
+		t.Fatalf("acked message status %d, attached info %+v", s.Status, s.AttachedInfoElem)
+	}
+	if status, attachInfo := deliveredInfo(t, c, lc.ConversationID, "m1"); status != constant.MsgStatusDelivered || attachInfo.DeliveredTime != 1500 {
+		t.Fatalf("stored status %d, delivered at %d", status, attachInfo.DeliveredTime)
+	}
+	if s := ack("m2"); s.Status != constant.MsgStatusSendSuccess {
+		t.Fatalf("message acked without a receipt has status %d", s.Status)
+	}
+	if status, _ := deliveredInfo(t, c, lc.ConversationID, "m2"); status != constant.MsgStatusSendSuccess {
+		t.Fatalf("stored status %d", status)
+	}
+}
+
+func TestCollectDeliveryReceipt(t *testing.T) {
+	receipts := make(map[deliveryReceiptKey][]string)
+	groupMsg := func(clientMsgID string, memberCount int32) *sdk_struct.MsgStruct {
+		return &sdk_struct.MsgStruct{ClientMsgID: clientMsgID, SendID: "u2", GroupID: "g1", SessionType: constant.ReadGroupChatType, ContentType: constant.Text,
+			AttachedInfoElem: &sdk_struct.AttachedInfoElem{GroupHasReadInfo: sdk_struct.GroupHasReadInfo{GroupMemberCount: memberCount}}}
+	}
+	for _, msg := range []*sdk_struct.MsgStruct{
+		{ClientMsgID: "m1", SendID: "u2", SessionType: constant.SingleChatType, ContentType: constant.Text, AttachedInfoElem: &sdk_struct.AttachedInfoElem{}},
+		{ClientMsgID: "m2", SendID: "u2", SessionType: constant.SingleChatType, ContentType: constant.Typing, AttachedInfoElem: &sdk_struct.AttachedInfoElem{}},
+		groupMsg("m3", 10),
+		groupMsg("m4", constant.GroupReadReceiptMaxMemberCount+1),
+		groupMsg("m5", 0),
+	} {
+		collectDeliveryReceipt(receipts, msg)
+	}
+	if got := receipts[deliveryReceiptKey{sendID: "u2"}]; !datautil.Equal(got, []string{"m1"}) {
+		t.Fatalf("single chat receipts %v", got)
+	}
+	if got := receipts[deliveryReceiptKey{sendID: "u2", groupID: "g1"}]; !datautil.Equal(got, []string{"m3"}) {
+		t.Fatalf("group receipts %v", got)
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversation_msg
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/common"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+	userPb "github.com/openimsdk/protocol/user"
+	"github.com/openimsdk/protocol/wrapperspb"
+	"github.com/openimsdk/tools/errs"
+	"github.com/openimsdk/tools/log"
+	"github.com/openimsdk/tools/utils/datautil"
+)
+
+func isEmptyDraft(draft *sdk_struct.ConversationDraft) bool {
+	return draft.Text == "" && draft.QuoteClientMsgID == "" && len(draft.MessageEntityList) == 0 &&
+		len(draft.AtUserIDList) == 0 && len(draft.AtUsersInfo) == 0 && len(draft.AttachmentPathList) == 0
+}
+
+// setConversationRichDraft saves the draft locally, and then syncs it to the other devices through
+// a user command. A cleared draft is synced as an empty one, so that the other devices clear it too.
+func (c *Conversation) setConversationRichDraft(ctx context.Context, conversationID string, draft *sdk_struct.ConversationDraft) error {
+	if _, err := c.db.GetConversation(ctx, conversationID); err != nil {
+		return err
+	}
+	if isEmptyDraft(draft) {
+		draft = &sdk_struct.ConversationDraft{}
+	}
+	draft.ConversationID = conversationID
+	draft.UpdateTime = utils.GetCurrentTimestampByMill()
+	if err := c.applyRichDraft(ctx, draft, true); err != nil {
+		return err
+	}
+	if err := c.pushRichDraft(ctx, draft); err != nil {
+		log.ZWarn(ctx, "sync conversation draft failed", err, "conversationID", conversationID)
+	}
+	return nil
+}
+
+// applyRichDraft saves the draft and its text. Only a draft typed on this device shows the
+// conversation again when it is hidden, a synced one leaves the conversation as it is.
+func (c *Conversation) applyRichDraft(ctx context.Context, draft *sdk_struct.ConversationDraft, show bool) error {
+	err := c.db.SaveRichDraft(ctx, &model_struct.LocalConversationDraft{ConversationID: draft.ConversationID,
+		Content: utils.StructToJsonString(draft), UpdateTime: draft.UpdateTime})
+	if err != nil {
+		return err
+	}
+	if draft.Text != "" && show {
+		err = c.db.SetConversationDraftDB(ctx, draft.ConversationID, draft.Text)
+	} else if draft.Text != "" {
+		err = c.db.UpdateConversationDraftText(ctx, draft.ConversationID, draft.Text, draft.UpdateTime)
+	} else {
+		err = c.db.RemoveConversationDraft(ctx, draft.ConversationID, "")
+	}
+	if err != nil {
+		return err
+	}
+	_ = common.TriggerCmdUpdateConversation(ctx, common.UpdateConNode{Action: constant.ConChange, Args: []string{draft.ConversationID}}, c.GetCh())
+	return nil
+}
+
+func (c *Conversation) pushRichDraft(ctx context.Context, draft *sdk_struct.ConversationDraft) error {
+	commands, err := c.db.ProcessUserCommandGetAll(ctx)
+	if err != nil {
+		return err
+	}
+	value := wrapperspb.String(utils.StructToJsonString(draft))
+	for _, command := range commands {
+		if command.Type == constant.ConversationDraftUserCommandType && command.Uuid == draft.ConversationID {
+			return c.user.ProcessUserCommandUpdate(ctx, &userPb.ProcessUserCommandUpdateReq{Type: constant.ConversationDraftUserCommandType,
+				Uuid: draft.ConversationID, Value: value})
+		}
+	}
+	if isEmptyDraft(draft) {
+		// the other devices have no draft to clear
+		return nil
+	}
+	return c.user.ProcessUserCommandAdd(ctx, &userPb.ProcessUserCommandAddReq{Type: constant.ConversationDraftUserCommandType,
+		Uuid: draft.ConversationID, Value: value})
+}
+
+// deleteRichDrafts drops the drafts of the hidden or cleared conversations along with their user
+// commands, so that the next sync does not bring them back. No conversation IDs means all of them.
+func (c *Conversation) deleteRichDrafts(ctx context.Context, conversationIDs ...string) {
+	var err error
+	if len(conversationIDs) == 0 {
+		err = c.db.DeleteAllRichDrafts(ctx)
+	} else {
+		for _, conversationID := range conversationIDs {
+			if err = c.db.DeleteRichDraft(ctx, conversationID); err != nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		log.ZWarn(ctx, "delete conversation drafts failed", err, "conversationIDs", conversationIDs)
+		return
+	}
+	commands, err := c.db.ProcessUserCommandGetAll(ctx)
+	if err != nil {
+		log.ZWarn(ctx, "get user commands failed", err)
+		return
+	}
+	for _, command := range commands {
+		if command.Type != constant.ConversationDraftUserCommandType ||
+			(len(conversationIDs) > 0 && !datautil.Contain(command.Uuid, conversationIDs...)) {
+			continue
+		}
+		err := c.user.ProcessUserCommandDelete(ctx, &userPb.ProcessUserCommandDeleteReq{Type: constant.ConversationDraftUserCommandType,
+			Uuid: command.Uuid})
+		if err != nil {
+			log.ZWarn(ctx, "delete conversation draft command failed", err, "conversationID", command.Uuid)
+		}
+	}
+}
+
+// conversationDraftsChanged applies the drafts synced from the other devices, when newer than the local ones.
+func (c *Conversation) conversationDraftsChanged(ctx context.Context) {
+	commands, err := c.db.ProcessUserCommandGetAll(ctx)
+	if err != nil {
+		log.ZWarn(ctx, "get user commands failed", err)
+		return
+	}
+	for _, command := range commands {
+		if command.Type != constant.ConversationDraftUserCommandType {
+			continue
+		}
+		var draft sdk_struct.ConversationDraft
+		if err := json.Unmarshal([]byte(command.Value), &draft); err != nil {
+			log.ZWarn(ctx, "invalid conversation draft", err, "uuid", command.Uuid, "value", command.Value)
+			continue
+		}
+		draft.ConversationID = command.Uuid
+		local, err := c.db.GetRichDraft(ctx, draft.ConversationID)
+		if err == nil && local.UpdateTime >= draft.UpdateTime {
+			continue
+		}
+		if err != nil && !errs.ErrRecordNotFound.Is(err) {
+			log.ZWarn(ctx, "get conversation draft failed", err, "conversationID", draft.ConversationID)
+			continue
+		}
+		if err := c.applyRichDraft(ctx, &draft, false); err != nil {
+			log.ZWarn(ctx, "apply conversation draft failed", err, "conversationID", draft.ConversationID)
+		}
+	}
+}
+
+// getConversationRichDraft returns the structured draft, or the plain draft text if there is none.
+func (c *Conversation) getConversationRichDraft(ctx context.Context, conversationID string) (*sdk_struct.ConversationDraft, error) {
+	local, err := c.db.GetRichDraft(ctx, conversationID)
+	if err != nil {
+		if !errs.ErrRecordNotFound.Is(err) {
+			return nil, err
+		}
+		conversation, err := c.db.GetConversation(ctx, conversationID)
+		if err != nil {
+			return nil, err
+		}
+		return &sdk_struct.ConversationDraft{ConversationID: conversationID, Text: conversation.DraftText,
+			UpdateTime: conversation.DraftTextTime}, nil
+	}
+	var draft sdk_struct.ConversationDraft
+	if err := json.Unmarshal([]byte(local.Content), &draft); err != nil {
+		return nil, errs.WrapMsg(err, "invalid conversation draft", "conversationID", conversationID)
+	}
+	return &draft, nil
+}
@@ -14,26 +14,25 @@ import (
 	"github.com/openimsdk/tools/log"
 	"github.com/openimsdk/tools/utils/datautil"
 	"github.com/patrickmn/go-cache"
+	"sort"
+	"sync"
 	"time"
 )
 
 const (
-	_ int = iota
-	stateCodeSuccess
-	stateCodeEnd
-)
-
-const (
-	inputStatesSendTime   = time.Second * 10                            // input status sending interval time
-	inputStatesTimeout    = inputStatesSendTime + inputStatesSendTime/2 // input status timeout
-	inputStatesMsgTimeout = inputStatesSendTime / 2                     // message sending timeout
+	inputStatesSendTime     = time.Second * 10                            // input status sending interval time
+	inputStatesTimeout      = inputStatesSendTime + inputStatesSendTime/2 // input status timeout
+	inputStatesMsgTimeout   = inputStatesSendTime / 2                     // message sending timeout
+	inputStatesMinInterval  = time.Second * 2                             // minimum interval between two activity changes sent
+	inputStatesMaxNameCount = 2                                           // number of names shown before "and N others"
 )
 
 func newTyping(c *Conversation) *typing {
 	e := &typing{
-		conv:  c,
-		send:  cache.New(inputStatesSendTime, inputStatesTimeout),
-		state: cache.New(inputStatesTimeout, inputStatesTimeout),
+		conv:    c,
+		send:    cache.New(inputStatesSendTime, inputStatesTimeout),
+		state:   cache.New(inputStatesTimeout, inputStatesTimeout),
+		delayed: make(map[string]*delayedActivity),
 	}
 	e.platformIDs = make([]int32, 0, len(pconstant.PlatformID2Name))
 	e.platformIDSet = make(map[int32]struct{})
@@ -60,47 +59,133 @@ type typing struct {
 
 	platformIDs   []int32
 	platformIDSet map[int32]struct{}
+
+	delayedLock sync.Mutex
+	delayed     map[string]*delayedActivity
+}
+
+// delayedActivity is the last activity change of a conversation that came within the minimum interval.
+type delayedActivity struct {
+	activity int32
+	timer    *time.Timer
+}
+
+// inputSendState is the last input activity sent for a conversation.
+type inputSendState struct {
+	Activity int32
+	SendTime time.Time
+}
+
+// inputState is the input activity of a user on one platform.
+type inputState struct {
+	Activity  int32
+	StartTime int64
+	SendTime  int64
 }
 
 func (e *typing) ChangeInputStates(ctx context.Context, conversationID string, focus bool) error {
+	if focus {
+		return e.ChangeInputActivity(ctx, conversationID, constant.InputActivityTyping)
+	}
+	return e.ChangeInputActivity(ctx, conversationID, constant.InputActivityNone)
+}
+
+func (e *typing) ChangeInputActivity(ctx context.Context, conversationID string, activity int32) error {
 	if conversationID == "" {
 		return errs.ErrArgs.WrapMsg("conversationID can't be empty")
 	}
+	if activity < constant.InputActivityNone || activity > constant.InputActivityUploadingFile {
+		return errs.ErrArgs.WrapMsg("invalid input activity")
+	}
 	conversation, err := e.conv.db.GetConversation(ctx, conversationID)
 	if err != nil {
 		return err
 	}
+	if conversation.ConversationType == constant.ReadGroupChatType || conversation.ConversationType == constant.WriteGroupChatType {
+		group, err := e.conv.db.GetGroupInfoByGroupID(ctx, conversation.GroupID)
+		if err != nil {
+			return err
+		}
+		if group.MemberCount > constant.GroupInputStatesMaxMemberCount {
+			log.ZDebug(ctx, "typing group too large", "conversationID", conversationID, "memberCount", group.MemberCount)
+			return nil
+		}
+	}
 	key := conversation.ConversationID
-	if focus {
-		if val, ok := e.send.Get(key); ok {
-			if val.(int) == stateCodeSuccess {
-				log.ZDebug(ctx, "typing stateCodeSuccess", "conversationID", conversationID, "focus", focus)
-				return nil
-			}
+	now := time.Now()
+	e.cancelDelayed(key)
+	if val, ok := e.send.Get(key); ok {
+		last := val.(inputSendState)
+		if last.Activity == activity {
+			log.ZDebug(ctx, "typing activity already sent", "conversationID", conversationID, "activity", activity)
+			return nil
 		}
-		e.send.SetDefault(key, stateCodeSuccess)
-	} else {
-		if val, ok := e.send.Get(key); ok {
-			if val.(int) == stateCodeEnd {
-				log.ZDebug(ctx, "typing stateCodeEnd", "conversationID", conversationID, "focus", focus)
-				return nil
-			}
-			e.send.SetDefault(key, stateCodeEnd)
-		} else {
-			log.ZDebug(ctx, "typing send not found", "conversationID", conversationID, "focus", focus)
+		if activity != constant.InputActivityNone && last.Activity != constant.InputActivityNone && now.Sub(last.SendTime) < inputStatesMinInterval {
+			log.ZDebug(ctx, "typing activity change delayed", "conversationID", conversationID, "activity", activity)
+			e.delay(ctx, key, activity, inputStatesMinInterval-now.Sub(last.SendTime))
 			return nil
 		}
+	} else if activity == constant.InputActivityNone {
+		log.ZDebug(ctx, "typing send not found", "conversationID", conversationID, "activity", activity)
+		return nil
 	}
+	e.send.SetDefault(key, inputSendState{Activity: activity, SendTime: now})
 	ctx, cancel := context.WithTimeout(ctx, inputStatesMsgTimeout)
 	defer cancel()
-	if err := e.sendMsg(ctx, conversation, focus); err != nil {
+	if err := e.sendMsg(ctx, conversation, activity); err != nil {
 		e.send.Delete(key)
 		return err
 	}
 	return nil
 }
 
-func (e *typing) sendMsg(ctx context.Context, conversation *model_struct.LocalConversation, focus bool) error {
+// delay sends the activity once the minimum interval has passed, so that the last change always
+// goes out. A later change replaces it.
+func (e *typing) delay(ctx context.Context, conversationID string, activity int32, wait time.Duration) {
+	e.delayedLock.Lock()
+	defer e.delayedLock.Unlock()
+	if d, ok := e.delayed[conversationID]; ok {
+		d.activity = activity
+		return
+	}
+	ctx = context.WithoutCancel(ctx)
+	d := &delayedActivity{activity: activity}
+	d.timer = time.AfterFunc(wait, func() {
+		e.delayedLock.Lock()
+		if e.delayed[conversationID] != d {
+			e.delayedLock.Unlock()
+			return
+		}
+		delete(e.delayed, conversationID)
+		activity := d.activity
+		e.delayedLock.Unlock()
+		if err := e.ChangeInputActivity(ctx, conversationID, activity); err != nil {
+			log.ZWarn(ctx, "send delayed typing activity failed", err, "conversationID", conversationID, "activity", activity)
+		}
+	})
+	e.delayed[conversationID] = d
+}
+
+func (e *typing) cancelDelayed(conversationID string) {
+	e.delayedLock.Lock()
+	defer e.delayedLock.Unlock()
+	if d, ok := e.delayed[conversationID]; ok {
+		d.timer.Stop()
+		delete(e.delayed, conversationID)
+	}
+}
+
+// stopDelayed drops the activity changes not sent yet, on logout.
+func (e *typing) stopDelayed() {
+	e.delayedLock.Lock()
+	defer e.delayedLock.Unlock()
+	for conversationID, d := range e.delayed {
+		d.timer.Stop()
+		delete(e.delayed, conversationID)
+	}
+}
+
+func (e *typing) sendMsg(ctx context.Context, conversation *model_struct.LocalConversation, activity int32) error {
 	s := sdk_struct.MsgStruct{}
 	err := e.conv.initBasicInfo(ctx, &s, constant.UserMsgType, constant.Typing)
 	if err != nil {
@@ -109,8 +194,8 @@ func (e *typing) sendMsg(ctx context.Context, conversation *model_struct.LocalCo
 	s.RecvID = conversation.UserID
 	s.GroupID = conversation.GroupID
 	s.SessionType = conversation.ConversationType
-	var typingElem sdk_struct.TypingElem
-	if focus {
+	typingElem := sdk_struct.TypingElem{Activity: activity}
+	if activity != constant.InputActivityNone {
 		typingElem.MsgTips = "yes"
 	} else {
 		typingElem.MsgTips = "no"
@@ -178,14 +263,23 @@ func (e *typing) onNewMsg(ctx context.Context, msg *sdkws.MsgData) {
 	conversationID := e.conv.getConversationIDBySessionType(sourceID, int(msg.SessionType))
 	key := e.getStateKey(conversationID, msg.SendID, msg.SenderPlatformID)
 	if enteringElem.MsgTips == "yes" {
+		activity := enteringElem.Activity
+		if activity == constant.InputActivityNone {
+			// sent by a client without input activities
+			activity = constant.InputActivityTyping
+		}
 		d := time.Duration(expirationTimestamp-now) * time.Millisecond
 		if v, t, ok := e.state.GetWithExpiration(key); ok {
-			if t.UnixMilli() >= expirationTimestamp {
+			state := v.(inputState)
+			if state.SendTime >= msg.SendTime || (state.Activity == activity && t.UnixMilli() >= expirationTimestamp) {
 				return
 			}
-			e.state.Set(key, v, d)
+			e.state.Set(key, inputState{Activity: activity, StartTime: state.StartTime, SendTime: msg.SendTime}, d)
+			if state.Activity != activity {
+				e.changes(conversationID, msg.SendID)
+			}
 		} else {
-			e.state.Set(key, struct{}{}, d)
+			e.state.Set(key, inputState{Activity: activity, StartTime: msg.SendTime, SendTime: msg.SendTime}, d)
 			e.changes(conversationID, msg.SendID)
 		}
 	} else {
@@ -199,13 +293,106 @@ type InputStatesChangedData struct {
 	ConversationID string  `json:"conversationID"`
 	UserID         string  `json:"userID"`
 	PlatformIDs    []int32 `json:"platformIDs"`
+	// Activity is the latest input activity of UserID, InputActivityNone once the user stopped.
+	Activity int32 `json:"activity"`
+	// InputUsers lists everyone in the conversation who currently has an input activity.
+	InputUsers *ConversationInputStates `json:"inputUsers"`
+}
+
+// ConversationInputUser is the input activity of one user in a conversation.
+type ConversationInputUser struct {
+	UserID      string  `json:"userID"`
+	Nickname    string  `json:"nickname"`
+	Activity    int32   `json:"activity"`
+	PlatformIDs []int32 `json:"platformIDs"`
+	StartTime   int64   `json:"startTime"`
+}
+
+// ConversationInputStates aggregates the input activities of a conversation, the users are
+// ordered by the time they started, so that NameList and OtherCount read as
+// "A, B and 3 others are typing".
+type ConversationInputStates struct {
+	ConversationID string                   `json:"conversationID"`
+	UserList       []*ConversationInputUser `json:"userList"`
+	NameList       []string                 `json:"nameList"`
+	OtherCount     int                      `json:"otherCount"`
 }
 
 func (e *typing) changes(conversationID string, userID string) {
 	data := InputStatesChangedData{ConversationID: conversationID, UserID: userID, PlatformIDs: e.GetInputStates(conversationID, userID)}
+	data.InputUsers = e.GetConversationInputStates(context.Background(), conversationID)
+	for _, user := range data.InputUsers.UserList {
+		if user.UserID == userID {
+			data.Activity = user.Activity
+			break
+		}
+	}
 	e.conv.ConversationListener().OnConversationUserInputStatusChanged(utils.StructToJsonString(data))
 }
 
+// GetConversationInputStates returns the users of a conversation with an input activity. A user
+// active on several platforms reports the activity received last.
+func (e *typing) GetConversationInputStates(ctx context.Context, conversationID string) *ConversationInputStates {
+	users := make(map[string]*ConversationInputUser)
+	sendTimes := make(map[string]int64)
+	for k, item := range e.state.Items() {
+		var key inputStatesKey
+		if err := json.Unmarshal([]byte(k), &key); err != nil || key.ConversationID != conversationID {
+			continue
+		}
+		state := item.Object.(inputState)
+		user, ok := users[key.UserID]
+		if !ok {
+			user = &ConversationInputUser{UserID: key.UserID, StartTime: state.StartTime}
+			users[key.UserID] = user
+		}
+		user.PlatformIDs = append(user.PlatformIDs, key.PlatformID)
+		if state.StartTime < user.StartTime {
+			user.StartTime = state.StartTime
+		}
+		if state.SendTime >= sendTimes[key.UserID] {
+			sendTimes[key.UserID] = state.SendTime
+			user.Activity = state.Activity
+		}
+	}
+	res := &ConversationInputStates{ConversationID: conversationID, UserList: datautil.Values(users), NameList: []string{}}
+	sort.Slice(res.UserList, func(i, j int) bool {
+		return res.UserList[i].StartTime < res.UserList[j].StartTime
+	})
+	for i, user := range res.UserList {
+		datautil.Sort(user.PlatformIDs, true)
+		user.Nickname = e.getInputUserName(ctx, conversationID, user.UserID)
+		if i < inputStatesMaxNameCount {
+			res.NameList = append(res.NameList, user.Nickname)
+		}
+	}
+	if len(res.UserList) > inputStatesMaxNameCount {
+		res.OtherCount = len(res.UserList) - inputStatesMaxNameCount
+	}
+	return res
+}
+
+// getInputUserName looks the name up in the local database only, the input states are
+// refreshed every few seconds and must not wait for the server.
+func (e *typing) getInputUserName(ctx context.Context, conversationID string, userID string) string {
+	conversation, err := e.conv.db.GetConversation(ctx, conversationID)
+	if err == nil && conversation.GroupID != "" {
+		if member, err := e.conv.db.GetGroupMemberInfoByGroupIDUserID(ctx, conversation.GroupID, userID); err == nil && member.Nickname != "" {
+			return member.Nickname
+		}
+	}
+	if friend, err := e.conv.relation.Db().GetFriendInfoByFriendUserID(ctx, userID); err == nil {
+		if friend.Remark != "" {
+			return friend.Remark
+		}
+		return friend.Nickname
+	}
+	if conversation != nil && conversation.UserID == userID {
+		return conversation.ShowName
+	}
+	return userID
+}
+
 func (e *typing) GetInputStates(conversationID string, userID string) []int32 {
 	platformIDs := make([]int32, 0, 1)
 	for _, platformID := range e.platformIDs {
@@ -0,0 +1,37 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !js
+
+package conversation_msg
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+)
+
+func TestTypingDelayedStoppedOnClose(t *testing.T) {
+	c, _ := newTestConversation(t, "u1")
+	c.typing.delay(context.Background(), "si_u1_u2", constant.InputActivityTyping, time.Hour)
+	c.typing.delay(context.Background(), "sg_g1", constant.InputActivityTyping, time.Hour)
+	c.Close(context.Background())
+	c.typing.delayedLock.Lock()
+	defer c.typing.delayedLock.Unlock()
+	if len(c.typing.delayed) != 0 {
+		t.Fatalf("%d delayed activities after logout", len(c.typing.delayed))
+	}
+}
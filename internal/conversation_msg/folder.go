@@ -0,0 +1,294 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversation_msg
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+	userPb "github.com/openimsdk/protocol/user"
+	"github.com/openimsdk/protocol/wrapperspb"
+	"github.com/openimsdk/tools/log"
+	"github.com/openimsdk/tools/utils/datautil"
+)
+
+// conversationFolders caches the folders stored in user commands, and the unread counts last
+// reported so that the listener is only called when they change.
+type conversationFolders struct {
+	lock         sync.Mutex
+	loaded       bool
+	folders      []*sdk_struct.ConversationFolder
+	unreadCounts map[string]int32
+}
+
+func (f *conversationFolders) invalidate() {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.loaded = false
+}
+
+func (c *Conversation) getConversationFolders(ctx context.Context) ([]*sdk_struct.ConversationFolder, error) {
+	c.folders.lock.Lock()
+	defer c.folders.lock.Unlock()
+	if c.folders.loaded {
+		return c.folders.folders, nil
+	}
+	commands, err := c.db.ProcessUserCommandGetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	commands = datautil.Filter(commands, func(command *model_struct.LocalUserCommand) (*model_struct.LocalUserCommand, bool) {
+		return command, command.Type == constant.ConversationFolderUserCommandType
+	})
+	sort.SliceStable(commands, func(i, j int) bool {
+		return commands[i].CreateTime < commands[j].CreateTime
+	})
+	folders := make([]*sdk_struct.ConversationFolder, 0, len(commands))
+	for _, command := range commands {
+		var folder sdk_struct.ConversationFolder
+		if err := json.Unmarshal([]byte(command.Value), &folder); err != nil {
+			log.ZWarn(ctx, "invalid conversation folder", err, "uuid", command.Uuid, "value", command.Value)
+			continue
+		}
+		folder.FolderID = command.Uuid
+		folders = append(folders, &folder)
+	}
+	sort.SliceStable(folders, func(i, j int) bool {
+		return folders[i].Order < folders[j].Order
+	})
+	c.folders.folders = folders
+	c.folders.loaded = true
+	return folders, nil
+}
+
+func (c *Conversation) getConversationFolder(ctx context.Context, folderID string) (*sdk_struct.ConversationFolder, error) {
+	folders, err := c.getConversationFolders(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, folder := range folders {
+		if folder.FolderID == folderID {
+			return folder, nil
+		}
+	}
+	return nil, sdkerrs.ErrConversationFolderNotFound.WrapMsg("folderID " + folderID)
+}
+
+func checkConversationFolder(folder *sdk_struct.ConversationFolder) error {
+	if folder.Name == "" {
+		return sdkerrs.ErrArgs.WrapMsg("folder name is empty")
+	}
+	for _, filter := range []int32{folder.MutedFilter, folder.UnreadFilter} {
+		switch filter {
+		case constant.ConversationFolderFilterAny, constant.ConversationFolderFilterOnly, constant.ConversationFolderFilterExclude:
+		default:
+			return sdkerrs.ErrArgs.WrapMsg("unknown conversation folder filter", "filter", filter)
+		}
+	}
+	return nil
+}
+
+// createConversationFolder stores the folder as a user command, so that it is synced to the other devices.
+func (c *Conversation) createConversationFolder(ctx context.Context, folder *sdk_struct.ConversationFolder) (*sdk_struct.ConversationFolder, error) {
+	if err := checkConversationFolder(folder); err != nil {
+		return nil, err
+	}
+	folder.FolderID = utils.OperationIDGenerator()
+	err := c.user.ProcessUserCommandAdd(ctx, &userPb.ProcessUserCommandAddReq{Type: constant.ConversationFolderUserCommandType,
+		Uuid: folder.FolderID, Value: wrapperspb.String(utils.StructToJsonString(folder))})
+	c.folders.invalidate()
+	if err != nil {
+		return nil, err
+	}
+	return folder, nil
+}
+
+func (c *Conversation) updateConversationFolder(ctx context.Context, folder *sdk_struct.ConversationFolder) error {
+	if err := checkConversationFolder(folder); err != nil {
+		return err
+	}
+	if _, err := c.getConversationFolder(ctx, folder.FolderID); err != nil {
+		return err
+	}
+	err := c.user.ProcessUserCommandUpdate(ctx, &userPb.ProcessUserCommandUpdateReq{Type: constant.ConversationFolderUserCommandType,
+		Uuid: folder.FolderID, Value: wrapperspb.String(utils.StructToJsonString(folder))})
+	c.folders.invalidate()
+	return err
+}
+
+func (c *Conversation) deleteConversationFolder(ctx context.Context, folderID string) error {
+	if _, err := c.getConversationFolder(ctx, folderID); err != nil {
+		return err
+	}
+	err := c.user.ProcessUserCommandDelete(ctx, &userPb.ProcessUserCommandDeleteReq{Type: constant.ConversationFolderUserCommandType, Uuid: folderID})
+	c.folders.invalidate()
+	return err
+}
+
+func matchConversationFilter(filter int32, state bool) bool {
+	switch filter {
+	case constant.ConversationFolderFilterOnly:
+		return state
+	case constant.ConversationFolderFilterExclude:
+		return !state
+	default:
+		return true
+	}
+}
+
+func matchConversationFolder(folder *sdk_struct.ConversationFolder, conversation *model_struct.LocalConversation) bool {
+	if datautil.Contain(conversation.ConversationID, folder.ExcludeConversationIDs...) {
+		return false
+	}
+	if datautil.Contain(conversation.ConversationID, folder.IncludeConversationIDs...) {
+		return true
+	}
+	return matchConversationFolderRules(folder, conversation.ConversationType, conversation.RecvMsgOpt, unreadCountOf(conversation) > 0)
+}
+
+// matchConversationFolderRules ignores the conversations explicitly included or excluded.
+func matchConversationFolderRules(folder *sdk_struct.ConversationFolder, conversationType, recvMsgOpt int32, unread bool) bool {
+	if len(folder.SessionTypeList) == 0 && folder.MutedFilter == constant.ConversationFolderFilterAny &&
+		folder.UnreadFilter == constant.ConversationFolderFilterAny {
+		return false
+	}
+	if len(folder.SessionTypeList) > 0 && !datautil.Contain(conversationType, folder.SessionTypeList...) {
+		return false
+	}
+	return matchConversationFilter(folder.MutedFilter, recvMsgOpt != constant.ReceiveMessage) &&
+		matchConversationFilter(folder.UnreadFilter, unread)
+}
+
+func (c *Conversation) getConversationListSplitByFolder(ctx context.Context, folderID string, offset, count int) ([]*model_struct.LocalConversation, error) {
+	if offset < 0 || count <= 0 {
+		return nil, sdkerrs.ErrArgs.WrapMsg("invalid offset or count")
+	}
+	folder, err := c.getConversationFolder(ctx, folderID)
+	if err != nil {
+		return nil, err
+	}
+	conversations, err := c.db.GetAllConversationListDB(ctx)
+	if err != nil {
+		return nil, err
+	}
+	conversations = datautil.Filter(conversations, func(conversation *model_struct.LocalConversation) (*model_struct.LocalConversation, bool) {
+		return conversation, matchConversationFolder(folder, conversation)
+	})
+	if offset >= len(conversations) {
+		return []*model_struct.LocalConversation{}, nil
+	}
+	return conversations[offset:min(offset+count, len(conversations))], nil
+}
+
+func (c *Conversation) getConversationFolderUnreadCounts(ctx context.Context) ([]*sdk_struct.ConversationFolderUnreadCount, error) {
+	groups, err := c.db.GetConversationUnreadCountGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.conversationFolderUnreadCounts(ctx, groups)
+}
+
+// conversationFolderUnreadCounts counts the unread messages of every folder, the same way as the
+// total unread count, muted conversations are not counted. The folder rules are matched against the
+// unread count groups, only the conversations explicitly included or excluded are read one by one.
+func (c *Conversation) conversationFolderUnreadCounts(ctx context.Context, groups []*model_struct.ConversationUnreadCountGroup) ([]*sdk_struct.ConversationFolderUnreadCount, error) {
+	folders, err := c.getConversationFolders(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(folders) == 0 {
+		return []*sdk_struct.ConversationFolderUnreadCount{}, nil
+	}
+	var conversationIDs []string
+	for _, folder := range folders {
+		conversationIDs = append(conversationIDs, folder.IncludeConversationIDs...)
+		conversationIDs = append(conversationIDs, folder.ExcludeConversationIDs...)
+	}
+	var listed []*model_struct.LocalConversation
+	if len(conversationIDs) > 0 {
+		conversations, err := c.db.GetMultipleConversationDB(ctx, datautil.Distinct(conversationIDs))
+		if err != nil {
+			return nil, err
+		}
+		for _, conversation := range conversations {
+			if conversation.LatestMsgSendTime > 0 && conversation.RecvMsgOpt < constant.ReceiveNotNotifyMessage && unreadCountOf(conversation) > 0 {
+				listed = append(listed, conversation)
+			}
+		}
+	}
+	res := make([]*sdk_struct.ConversationFolderUnreadCount, 0, len(folders))
+	for _, folder := range folders {
+		unreadCount := sdk_struct.ConversationFolderUnreadCount{FolderID: folder.FolderID}
+		for _, group := range groups {
+			if group.RecvMsgOpt < constant.ReceiveNotNotifyMessage && matchConversationFolderRules(folder, group.ConversationType, group.RecvMsgOpt, true) {
+				unreadCount.UnreadCount += group.UnreadCount
+			}
+		}
+		// the groups counted the listed conversations by the rules only
+		for _, conversation := range listed {
+			inFolder := matchConversationFolder(folder, conversation)
+			if inFolder == matchConversationFolderRules(folder, conversation.ConversationType, conversation.RecvMsgOpt, true) {
+				continue
+			}
+			if inFolder {
+				unreadCount.UnreadCount += unreadCountOf(conversation)
+			} else {
+				unreadCount.UnreadCount -= unreadCountOf(conversation)
+			}
+		}
+		res = append(res, &unreadCount)
+	}
+	return res, nil
+}
+
+// folderUnreadCountChanged notifies the folder unread counts if any of them changed since the last call.
+func (c *Conversation) folderUnreadCountChanged(ctx context.Context, groups []*model_struct.ConversationUnreadCountGroup) {
+	unreadCounts, err := c.conversationFolderUnreadCounts(ctx, groups)
+	if err != nil {
+		log.ZWarn(ctx, "get conversation folder unread counts failed", err)
+		return
+	}
+	c.folders.lock.Lock()
+	changed := len(unreadCounts) != len(c.folders.unreadCounts)
+	last := make(map[string]int32, len(unreadCounts))
+	for _, v := range unreadCounts {
+		if count, ok := c.folders.unreadCounts[v.FolderID]; !ok || count != v.UnreadCount {
+			changed = true
+		}
+		last[v.FolderID] = v.UnreadCount
+	}
+	c.folders.unreadCounts = last
+	c.folders.lock.Unlock()
+	if changed {
+		c.ConversationListener().OnConversationFolderUnreadCountChanged(utils.StructToJsonString(unreadCounts))
+	}
+}
+
+func (c *Conversation) conversationFoldersChanged(ctx context.Context) {
+	c.folders.invalidate()
+	groups, err := c.db.GetConversationUnreadCountGroups(ctx)
+	if err != nil {
+		log.ZWarn(ctx, "get conversation unread count groups failed", err)
+		return
+	}
+	c.folderUnreadCountChanged(ctx, groups)
+}
@@ -0,0 +1,113 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !js
+
+package conversation_msg
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+func insertUnreadTestConversations(t *testing.T, c *Conversation) {
+	conversations := []*model_struct.LocalConversation{
+		{ConversationID: "si_u1_u2", ConversationType: constant.SingleChatType, UnreadCount: 3},
+		{ConversationID: "si_u1_u3", ConversationType: constant.SingleChatType, IsMarkedUnread: true},
+		{ConversationID: "si_u1_u4", ConversationType: constant.SingleChatType, UnreadCount: 4, RecvMsgOpt: constant.ReceiveNotNotifyMessage},
+		{ConversationID: "sg_g1", ConversationType: constant.ReadGroupChatType, UnreadCount: 5, GroupAtType: constant.AtMe},
+		{ConversationID: "sg_g2", ConversationType: constant.ReadGroupChatType, UnreadCount: 2, GroupAtType: constant.AtAllAtMe,
+			RecvMsgOpt: constant.ReceiveNotNotifyMessage},
+		{ConversationID: "sg_g3", ConversationType: constant.ReadGroupChatType, UnreadCount: 1, GroupAtType: constant.AtAll,
+			RecvMsgOpt: constant.NotReceiveMessage},
+		{ConversationID: "sg_g4", ConversationType: constant.ReadGroupChatType},
+		{ConversationID: "sn_n1", ConversationType: constant.NotificationChatType, UnreadCount: 7},
+		// hidden conversations are not counted
+		{ConversationID: "si_u1_u5", ConversationType: constant.SingleChatType, UnreadCount: 9},
+	}
+	for _, conversation := range conversations {
+		if conversation.ConversationID != "si_u1_u5" {
+			conversation.LatestMsgSendTime = 1
+		}
+		if err := c.db.InsertConversation(context.Background(), conversation); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestConversationFolderUnreadCounts(t *testing.T) {
+	ctx := context.Background()
+	c, listener := newTestConversation(t, "u1")
+	insertUnreadTestConversations(t, c)
+	folders := []*sdk_struct.ConversationFolder{
+		{FolderID: "groups", SessionTypeList: []int32{constant.ReadGroupChatType}, ExcludeConversationIDs: []string{"sg_g1"}},
+		{FolderID: "unread", UnreadFilter: constant.ConversationFolderFilterOnly, IncludeConversationIDs: []string{"sg_g4"}},
+		{FolderID: "muted", MutedFilter: constant.ConversationFolderFilterOnly, IncludeConversationIDs: []string{"si_u1_u2", "si_u1_u5"}},
+		{FolderID: "read", UnreadFilter: constant.ConversationFolderFilterExclude, IncludeConversationIDs: []string{"sn_n1"}},
+		{FolderID: "listed", IncludeConversationIDs: []string{"si_u1_u2", "si_u1_u3", "sg_g2"}, ExcludeConversationIDs: []string{"si_u1_u3"}},
+	}
+	c.folders.folders, c.folders.loaded = folders, true
+
+	// the counts match a scan of every conversation
+	conversations, err := c.db.GetAllConversationListDB(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := make(map[string]int32)
+	for _, folder := range folders {
+		for _, conversation := range conversations {
+			if conversation.RecvMsgOpt < constant.ReceiveNotNotifyMessage && matchConversationFolder(folder, conversation) {
+				want[folder.FolderID] += unreadCountOf(conversation)
+			}
+		}
+	}
+	unreadCounts, err := c.getConversationFolderUnreadCounts(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]int32)
+	for _, v := range unreadCounts {
+		got[v.FolderID] = v.UnreadCount
+	}
+	for _, folder := range folders {
+		if got[folder.FolderID] != want[folder.FolderID] {
+			t.Fatalf("folder %s unread count %d, want %d", folder.FolderID, got[folder.FolderID], want[folder.FolderID])
+		}
+	}
+	if got["groups"] != 1 || got["listed"] != 3 || got["read"] != 7 {
+		t.Fatalf("unread counts %v", got)
+	}
+
+	c.unreadCountsChanged(ctx)
+	if err := c.db.UpdateColumnsConversation(ctx, "sg_g3", map[string]any{"unread_count": 4}); err != nil {
+		t.Fatal(err)
+	}
+	c.unreadCountsChanged(ctx)
+	notified := listener.get("OnConversationFolderUnreadCountChanged")
+	if len(notified) != 2 {
+		t.Fatalf("folder unread count callbacks %v", notified)
+	}
+	var last []*sdk_struct.ConversationFolderUnreadCount
+	if err := json.Unmarshal([]byte(notified[1]), &last); err != nil {
+		t.Fatal(err)
+	}
+	if last[0].FolderID != "groups" || last[0].UnreadCount != 4 {
+		t.Fatalf("groups unread count %+v", last[0])
+	}
+}
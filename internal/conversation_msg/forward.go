@@ -0,0 +1,150 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversation_msg
+
+import (
+	"context"
+	"strings"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	sdk "github.com/openimsdk/openim-sdk-core/v3/pkg/sdk_params_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+	"github.com/openimsdk/tools/errs"
+	"github.com/openimsdk/tools/log"
+)
+
+const (
+	forwardMergeTitle       = "Chat history"
+	forwardMergeSummarySize = 4
+)
+
+// forwardMessages sends the messages to every target in order. Media are sent with the urls they
+// were uploaded to, and a failure is reported in the target results without stopping the others.
+func (c *Conversation) forwardMessages(ctx context.Context, messages []*sdk_struct.MsgStruct, targets []*sdk.ForwardTarget, mode int32) (*sdk.ForwardMessagesCallback, error) {
+	if len(messages) == 0 || len(targets) == 0 {
+		return nil, sdkerrs.ErrArgs.WrapMsg("messages and targets must not be empty")
+	}
+	if mode != constant.ForwardModeOneByOne && mode != constant.ForwardModeMerge {
+		return nil, sdkerrs.ErrArgs.WrapMsg("unknown forward mode", "mode", mode)
+	}
+	for _, msg := range messages {
+		if !isSentStatus(msg.Status) {
+			return nil, sdkerrs.ErrArgs.WrapMsg("only send success message can be forwarded", "clientMsgID", msg.ClientMsgID)
+		}
+		if !isMediaUploaded(msg) {
+			return nil, sdkerrs.ErrArgs.WrapMsg("message media is not uploaded", "clientMsgID", msg.ClientMsgID)
+		}
+	}
+	res := &sdk.ForwardMessagesCallback{}
+	for _, target := range targets {
+		result := &sdk.ForwardTargetResult{ForwardTarget: *target}
+		if mode == constant.ForwardModeMerge {
+			result.ResultList = append(result.ResultList, c.forwardMergerMessage(ctx, messages, target))
+		} else {
+			for _, msg := range messages {
+				result.ResultList = append(result.ResultList, c.forwardMessage(ctx, msg, target))
+			}
+		}
+		res.TargetList = append(res.TargetList, result)
+	}
+	return res, nil
+}
+
+func (c *Conversation) forwardMessage(ctx context.Context, msg *sdk_struct.MsgStruct, target *sdk.ForwardTarget) *sdk.ForwardMessageResult {
+	result := &sdk.ForwardMessageResult{SourceClientMsgID: msg.ClientMsgID}
+	// every target gets its own copy, the forward resets the ids of the message
+	var s sdk_struct.MsgStruct
+	if err := utils.JsonStringToStruct(utils.StructToJsonString(msg), &s); err != nil {
+		result.ErrCode, result.ErrMsg = errCodeAndMsg(err)
+		return result
+	}
+	if s.PollElem != nil {
+		// the forwarded poll starts without votes
+		s.PollElem.Result = nil
+	}
+	forward, err := c.CreateForwardMessage(ctx, &s)
+	if err != nil {
+		result.ErrCode, result.ErrMsg = errCodeAndMsg(err)
+		return result
+	}
+	c.sendForwardMessage(ctx, forward, target, result)
+	return result
+}
+
+func (c *Conversation) forwardMergerMessage(ctx context.Context, messages []*sdk_struct.MsgStruct, target *sdk.ForwardTarget) *sdk.ForwardMessageResult {
+	result := &sdk.ForwardMessageResult{}
+	summaries := make([]string, 0, forwardMergeSummarySize)
+	for _, msg := range messages {
+		if len(summaries) == forwardMergeSummarySize {
+			break
+		}
+		summaries = append(summaries, messageSummary(msg))
+	}
+	merger, err := c.CreateMergerMessage(ctx, messages, forwardMergeTitle, summaries)
+	if err != nil {
+		result.ErrCode, result.ErrMsg = errCodeAndMsg(err)
+		return result
+	}
+	c.sendForwardMessage(ctx, merger, target, result)
+	return result
+}
+
+func (c *Conversation) sendForwardMessage(ctx context.Context, s *sdk_struct.MsgStruct, target *sdk.ForwardTarget, result *sdk.ForwardMessageResult) {
+	msg, err := c.SendMessageNotOss(ctx, s, target.RecvID, target.GroupID, nil, false)
+	if err != nil {
+		log.ZWarn(ctx, "forward message failed", err, "recvID", target.RecvID, "groupID", target.GroupID, "clientMsgID", s.ClientMsgID)
+		result.ErrCode, result.ErrMsg = errCodeAndMsg(err)
+	}
+	if msg == nil {
+		msg = s
+	}
+	result.Message = msg
+}
+
+// errCodeAndMsg reports the error of one message in a batch operation.
+func errCodeAndMsg(err error) (int32, string) {
+	if code, ok := errs.Unwrap(err).(errs.CodeError); ok {
+		return int32(code.Code()), code.Msg()
+	}
+	return sdkerrs.UnknownCode, err.Error()
+}
+
+// isMediaUploaded reports whether the media of the message can be sent again by url.
+func isMediaUploaded(msg *sdk_struct.MsgStruct) bool {
+	switch msg.ContentType {
+	case constant.Picture:
+		return msg.PictureElem != nil && msg.PictureElem.SourcePicture != nil && msg.PictureElem.SourcePicture.Url != ""
+	case constant.Sound:
+		return msg.SoundElem != nil && msg.SoundElem.SourceURL != ""
+	case constant.Video:
+		return msg.VideoElem != nil && msg.VideoElem.VideoURL != ""
+	case constant.File:
+		return msg.FileElem != nil && msg.FileElem.SourceURL != ""
+	default:
+		return true
+	}
+}
+
+// messageSummary is the one line text of a message shown in a merger message.
+func messageSummary(msg *sdk_struct.MsgStruct) string {
+	view := messageTextOf(msg, nil)
+	text := view.Text
+	if text == "" && msg.ContentType == constant.Picture {
+		text = "[Picture]"
+	}
+	return view.Sender + ": " + strings.TrimSpace(text)
+}
@@ -0,0 +1,361 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversation_msg
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	sdk "github.com/openimsdk/openim-sdk-core/v3/pkg/sdk_params_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+	"github.com/openimsdk/tools/log"
+	"github.com/openimsdk/tools/utils/datautil"
+)
+
+const globalSearchDefaultCount = 20
+
+var globalSearchSections = []string{
+	constant.GlobalSearchSectionConversation,
+	constant.GlobalSearchSectionFriend,
+	constant.GlobalSearchSectionGroup,
+	constant.GlobalSearchSectionGroupMember,
+	constant.GlobalSearchSectionMessage,
+}
+
+// globalSearch runs the search of every requested section in parallel. Each section is ranked on its own,
+// and the sections are ordered by their best match. A section failing is reported in SectionErrors.
+func (c *Conversation) globalSearch(ctx context.Context, req *sdk.GlobalSearchParams) (*sdk.GlobalSearchCallback, error) {
+	if len(req.KeywordList) == 0 {
+		return nil, sdkerrs.ErrArgs.WrapMsg("keyword list is empty")
+	}
+	sections := datautil.Distinct(req.SectionList)
+	if len(sections) == 0 {
+		sections = globalSearchSections
+	}
+	offsets := make([]int, len(sections))
+	for i, section := range sections {
+		if !datautil.Contain(section, globalSearchSections...) {
+			return nil, sdkerrs.ErrArgs.WrapMsg("unknown search section " + section)
+		}
+		offset, err := parseGlobalSearchCursor(req.Cursors[section])
+		if err != nil {
+			return nil, err
+		}
+		offsets[i] = offset
+	}
+	count := req.Count
+	if count <= 0 {
+		count = globalSearchDefaultCount
+	}
+	var res sdk.GlobalSearchCallback
+	bestScores := make([]int, len(sections))
+	errList := make([]error, len(sections))
+	var wg sync.WaitGroup
+	for i, section := range sections {
+		i, section := i, section
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			switch section {
+			case constant.GlobalSearchSectionMessage:
+				res.Messages, bestScores[i], errList[i] = c.globalSearchMessages(ctx, req, offsets[i], count)
+			case constant.GlobalSearchSectionFriend:
+				res.Friends, bestScores[i], errList[i] = c.globalSearchFriends(ctx, req, offsets[i], count)
+			case constant.GlobalSearchSectionGroup:
+				res.Groups, bestScores[i], errList[i] = c.globalSearchGroups(ctx, req, offsets[i], count)
+			case constant.GlobalSearchSectionGroupMember:
+				res.GroupMembers, bestScores[i], errList[i] = c.globalSearchGroupMembers(ctx, req, offsets[i], count)
+			case constant.GlobalSearchSectionConversation:
+				res.Conversations, bestScores[i], errList[i] = c.globalSearchConversations(ctx, req, offsets[i], count)
+			}
+		}()
+	}
+	wg.Wait()
+	order := make([]int, 0, len(sections))
+	for i, section := range sections {
+		if err := errList[i]; err != nil {
+			log.ZWarn(ctx, "global search section failed", err, "section", section)
+			if res.SectionErrors == nil {
+				res.SectionErrors = make(map[string]string)
+			}
+			res.SectionErrors[section] = err.Error()
+			continue
+		}
+		if bestScores[i] > 0 {
+			order = append(order, i)
+		}
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return bestScores[order[i]] > bestScores[order[j]]
+	})
+	res.SectionList = make([]string, 0, len(order))
+	for _, i := range order {
+		res.SectionList = append(res.SectionList, sections[i])
+	}
+	return &res, nil
+}
+
+func (c *Conversation) globalSearchMessages(ctx context.Context, req *sdk.GlobalSearchParams, offset, count int) (*sdk.GlobalSearchMessages, int, error) {
+	contentTypes := SearchContentType
+	if len(req.MessageTypeList) > 0 {
+		contentTypes = datautil.Filter(req.MessageTypeList, func(contentType int) (int, bool) {
+			return contentType, datautil.Contain(contentType, SearchContentType...)
+		})
+		if len(contentTypes) == 0 {
+			return &sdk.GlobalSearchMessages{GlobalSearchPage: sdk.GlobalSearchPage{IsEnd: true}}, 0, nil
+		}
+	}
+	endTime := req.EndTime
+	if endTime == 0 {
+		endTime = time.Now().UnixMilli()
+	}
+	query := &sdk_struct.MessageSearchQuery{ContentTypeList: contentTypes, KeywordList: req.KeywordList, KeywordListMatchType: req.KeywordListMatchType,
+		SenderUserIDList: req.SenderUserIDList, ConversationTypeList: req.ConversationTypeList, StartTime: req.StartTime, EndTime: endTime}
+	list, total, err := c.db.SearchMessagePageByFullText(ctx, query, offset, count)
+	if err != nil {
+		log.ZWarn(ctx, "full text search failed, scan the chat logs", err)
+		return c.globalScanMessages(ctx, query, offset, count)
+	}
+	// messages all match the keywords, the most recent ones come first
+	page := sdk.GlobalSearchPage{TotalCount: int(total), IsEnd: int64(offset+count) >= total}
+	if !page.IsEnd {
+		page.NextCursor = strconv.Itoa(offset + count)
+	}
+	var best int
+	if total > 0 {
+		best = 1
+	}
+	return &sdk.GlobalSearchMessages{GlobalSearchPage: page, MessageList: c.globalSearchMessageItems(ctx, list)}, best, nil
+}
+
+// globalScanMessages is the message search without the full text index, the chat logs of every
+// conversation are scanned and the page is cut from all the matches.
+func (c *Conversation) globalScanMessages(ctx context.Context, query *sdk_struct.MessageSearchQuery, offset, count int) (*sdk.GlobalSearchMessages, int, error) {
+	list, err := c.searchMessageByContentTypeAndKeyword(ctx, query.ContentTypeList, query.KeywordList, query.KeywordListMatchType, query.StartTime, query.EndTime)
+	if err != nil {
+		return nil, 0, err
+	}
+	searchParam := &sdk.SearchLocalMessagesParams{KeywordList: query.KeywordList, KeywordListMatchType: query.KeywordListMatchType}
+	list = datautil.Filter(list, func(v *model_struct.LocalChatLog) (*model_struct.LocalChatLog, bool) {
+		return v, len(query.SenderUserIDList) == 0 || datautil.Contain(v.SendID, query.SenderUserIDList...)
+	})
+	items := c.globalSearchMessageItems(ctx, list)
+	items = datautil.Filter(items, func(item *sdk.GlobalSearchMessageItem) (*sdk.GlobalSearchMessageItem, bool) {
+		return item, (len(query.ConversationTypeList) == 0 || datautil.Contain(item.ConversationType, query.ConversationTypeList...)) &&
+			!c.filterMsg(item.Message, searchParam)
+	})
+	items, page, best := rankGlobalSearch(items, func(*sdk.GlobalSearchMessageItem) int { return 1 },
+		func(a, b *sdk.GlobalSearchMessageItem) bool { return a.Message.SendTime > b.Message.SendTime }, offset, count)
+	return &sdk.GlobalSearchMessages{GlobalSearchPage: page, MessageList: items}, best, nil
+}
+
+// globalSearchMessageItems converts the chat logs found, with the conversation each one belongs to.
+// The chat logs of conversations no longer in the list are dropped.
+func (c *Conversation) globalSearchMessageItems(ctx context.Context, list []*model_struct.LocalChatLog) []*sdk.GlobalSearchMessageItem {
+	conversations := make(map[string]*model_struct.LocalConversation)
+	items := make([]*sdk.GlobalSearchMessageItem, 0, len(list))
+	for _, v := range list {
+		msg, err := c.localChatLogToMsgStruct(v)
+		if err != nil {
+			log.ZWarn(ctx, "parse searched message failed", err, "clientMsgID", v.ClientMsgID)
+			continue
+		}
+		conversationID := utils.GetConversationIDByMsg(msg)
+		conversation, ok := conversations[conversationID]
+		if !ok {
+			conversation, err = c.db.GetConversation(ctx, conversationID)
+			if err != nil {
+				continue
+			}
+			conversations[conversationID] = conversation
+		}
+		items = append(items, &sdk.GlobalSearchMessageItem{
+			ConversationID:   conversationID,
+			ConversationType: conversation.ConversationType,
+			ShowName:         conversation.ShowName,
+			FaceURL:          conversation.FaceURL,
+			Message:          msg,
+		})
+	}
+	return items
+}
+
+func (c *Conversation) globalSearchFriends(ctx context.Context, req *sdk.GlobalSearchParams, offset, count int) (*sdk.GlobalSearchFriends, int, error) {
+	friends, err := searchEachKeyword(req, func(keyword string) ([]*sdk.SearchFriendItem, error) {
+		return c.relation.SearchFriends(ctx, &sdk.SearchFriendsParam{KeywordList: []string{keyword},
+			IsSearchUserID: true, IsSearchNickname: true, IsSearchRemark: true})
+	}, func(friend *sdk.SearchFriendItem) string { return friend.FriendUserID })
+	if err != nil {
+		return nil, 0, err
+	}
+	friends, page, best := rankGlobalSearch(friends, func(friend *sdk.SearchFriendItem) int {
+		return globalSearchScore(req, friend.Remark, friend.Nickname, friend.FriendUserID)
+	}, func(a, b *sdk.SearchFriendItem) bool { return a.CreateTime > b.CreateTime }, offset, count)
+	return &sdk.GlobalSearchFriends{GlobalSearchPage: page, FriendList: friends}, best, nil
+}
+
+func (c *Conversation) globalSearchGroups(ctx context.Context, req *sdk.GlobalSearchParams, offset, count int) (*sdk.GlobalSearchGroups, int, error) {
+	groups, err := searchEachKeyword(req, func(keyword string) ([]*model_struct.LocalGroup, error) {
+		return c.group.SearchGroups(ctx, sdk.SearchGroupsParam{KeywordList: []string{keyword}, IsSearchGroupID: true, IsSearchGroupName: true})
+	}, func(group *model_struct.LocalGroup) string { return group.GroupID })
+	if err != nil {
+		return nil, 0, err
+	}
+	groups, page, best := rankGlobalSearch(groups, func(group *model_struct.LocalGroup) int {
+		return globalSearchScore(req, group.GroupName, group.GroupID)
+	}, func(a, b *model_struct.LocalGroup) bool { return a.MemberCount > b.MemberCount }, offset, count)
+	return &sdk.GlobalSearchGroups{GlobalSearchPage: page, GroupList: groups}, best, nil
+}
+
+func (c *Conversation) globalSearchGroupMembers(ctx context.Context, req *sdk.GlobalSearchParams, offset, count int) (*sdk.GlobalSearchGroupMembers, int, error) {
+	members, err := searchEachKeyword(req, func(keyword string) ([]*model_struct.LocalGroupMember, error) {
+		return c.group.SearchGroupMembers(ctx, &sdk.SearchGroupMembersParam{KeywordList: []string{keyword},
+			IsSearchUserID: true, IsSearchMemberNickname: true, Count: -1})
+	}, func(member *model_struct.LocalGroupMember) string { return member.GroupID + "/" + member.UserID })
+	if err != nil {
+		return nil, 0, err
+	}
+	members, page, best := rankGlobalSearch(members, func(member *model_struct.LocalGroupMember) int {
+		return globalSearchScore(req, member.Nickname, member.UserID)
+	}, func(a, b *model_struct.LocalGroupMember) bool { return a.RoleLevel > b.RoleLevel }, offset, count)
+	return &sdk.GlobalSearchGroupMembers{GlobalSearchPage: page, GroupMemberList: members}, best, nil
+}
+
+func (c *Conversation) globalSearchConversations(ctx context.Context, req *sdk.GlobalSearchParams, offset, count int) (*sdk.GlobalSearchConversations, int, error) {
+	conversations, err := searchEachKeyword(req, func(keyword string) ([]*model_struct.LocalConversation, error) {
+		return c.db.SearchConversations(ctx, keyword)
+	}, func(conversation *model_struct.LocalConversation) string { return conversation.ConversationID })
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(req.ConversationTypeList) > 0 {
+		conversations = datautil.Filter(conversations, func(conversation *model_struct.LocalConversation) (*model_struct.LocalConversation, bool) {
+			return conversation, datautil.Contain(conversation.ConversationType, req.ConversationTypeList...)
+		})
+	}
+	conversations, page, best := rankGlobalSearch(conversations, func(conversation *model_struct.LocalConversation) int {
+		return globalSearchScore(req, conversation.ShowName)
+	}, func(a, b *model_struct.LocalConversation) bool { return a.LatestMsgSendTime > b.LatestMsgSendTime }, offset, count)
+	return &sdk.GlobalSearchConversations{GlobalSearchPage: page, ConversationList: conversations}, best, nil
+}
+
+// searchEachKeyword runs the search once per keyword and merges the results. When all keywords have to
+// match, the first keyword narrows the candidates and the ranking drops the rest.
+func searchEachKeyword[T any](req *sdk.GlobalSearchParams, search func(keyword string) ([]T, error), key func(T) string) ([]T, error) {
+	keywords := req.KeywordList
+	if req.KeywordListMatchType == constant.KeywordMatchAnd {
+		keywords = keywords[:1]
+	}
+	var result []T
+	seen := make(map[string]struct{})
+	for _, keyword := range keywords {
+		list, err := search(keyword)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range list {
+			if _, ok := seen[key(v)]; ok {
+				continue
+			}
+			seen[key(v)] = struct{}{}
+			result = append(result, v)
+		}
+	}
+	return result, nil
+}
+
+// rankGlobalSearch drops the items scored 0, sorts the rest by score and then by less, and returns
+// the page starting at offset together with the best score.
+func rankGlobalSearch[T any](list []T, score func(T) int, less func(a, b T) bool, offset, count int) ([]T, sdk.GlobalSearchPage, int) {
+	scores := make(map[int]int, len(list))
+	ranked := make([]int, 0, len(list))
+	for i, v := range list {
+		if s := score(v); s > 0 {
+			scores[i] = s
+			ranked = append(ranked, i)
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if scores[ranked[i]] != scores[ranked[j]] {
+			return scores[ranked[i]] > scores[ranked[j]]
+		}
+		return less(list[ranked[i]], list[ranked[j]])
+	})
+	page := sdk.GlobalSearchPage{TotalCount: len(ranked), IsEnd: true}
+	var best int
+	if len(ranked) > 0 {
+		best = scores[ranked[0]]
+	}
+	if offset >= len(ranked) {
+		return []T{}, page, best
+	}
+	end := offset + count
+	if end < len(ranked) {
+		page.IsEnd = false
+		page.NextCursor = strconv.Itoa(end)
+	} else {
+		end = len(ranked)
+	}
+	result := make([]T, 0, end-offset)
+	for _, i := range ranked[offset:end] {
+		result = append(result, list[i])
+	}
+	return result, page, best
+}
+
+// globalSearchScore scores how well the fields match the keywords, an exact match counts 3,
+// a prefix 2 and a substring 1 for every keyword. It returns 0 if the item does not match.
+func globalSearchScore(req *sdk.GlobalSearchParams, fields ...string) int {
+	var total int
+	for _, keyword := range req.KeywordList {
+		keyword = strings.ToLower(keyword)
+		var best int
+		for _, field := range fields {
+			field = strings.ToLower(field)
+			switch {
+			case field == keyword:
+				best = max(best, 3)
+			case strings.HasPrefix(field, keyword):
+				best = max(best, 2)
+			case strings.Contains(field, keyword):
+				best = max(best, 1)
+			}
+		}
+		if best == 0 && req.KeywordListMatchType == constant.KeywordMatchAnd {
+			return 0
+		}
+		total += best
+	}
+	return total
+}
+
+func parseGlobalSearchCursor(cursor string) (int, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	offset, err := strconv.Atoi(cursor)
+	if err != nil || offset < 0 {
+		return 0, sdkerrs.ErrArgs.WrapMsg("invalid search cursor " + cursor)
+	}
+	return offset, nil
+}
@@ -0,0 +1,146 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !js
+
+package conversation_msg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/db_interface"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	sdk "github.com/openimsdk/openim-sdk-core/v3/pkg/sdk_params_callback"
+)
+
+// newGlobalSearchConversation has "hello" said 4 times by u2 in a single chat and once by u3 in a group.
+func newGlobalSearchConversation(t *testing.T) *Conversation {
+	ctx := context.Background()
+	c, _ := newTestConversation(t, "u1")
+	conversations := []*model_struct.LocalConversation{
+		{ConversationID: "si_u1_u2", ConversationType: constant.SingleChatType, UserID: "u2", ShowName: "u2", LatestMsgSendTime: 1},
+		{ConversationID: "sg_g1", ConversationType: constant.ReadGroupChatType, GroupID: "g1", ShowName: "group", LatestMsgSendTime: 1},
+	}
+	for _, conversation := range conversations {
+		if err := c.db.InsertConversation(ctx, conversation); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var single []*model_struct.LocalChatLog
+	for i := 1; i <= 4; i++ {
+		msg := newTestTextMessage("si_u1_u2", fmt.Sprintf("s%d", i), "u2", int64(i), int64(1000*i))
+		msg.RecvID = "u1"
+		single = append(single, msg)
+	}
+	// not matching
+	single = append(single, newTestTextMessage("si_u1_u2", "other", "u2", 5, 6000))
+	single[4].RecvID = "u1"
+	single[4].Content = `{"content":"bye"}`
+	for _, msg := range single[:4] {
+		msg.Content = fmt.Sprintf(`{"content":"hello %s"}`, msg.ClientMsgID)
+	}
+	if err := c.db.BatchInsertMessageList(ctx, "si_u1_u2", single); err != nil {
+		t.Fatal(err)
+	}
+	group := newTestTextMessage("sg_g1", "g", "u3", 1, 2500)
+	group.RecvID = "g1"
+	group.SessionType = constant.ReadGroupChatType
+	group.Content = `{"content":"Hello group"}`
+	if err := c.db.BatchInsertMessageList(ctx, "sg_g1", []*model_struct.LocalChatLog{group}); err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestGlobalSearchMessages(t *testing.T) {
+	ctx := context.Background()
+	c := newGlobalSearchConversation(t)
+	search := func(req *sdk.GlobalSearchParams) *sdk.GlobalSearchMessages {
+		t.Helper()
+		req.KeywordList = []string{"hello"}
+		req.SectionList = []string{constant.GlobalSearchSectionMessage}
+		res, err := c.globalSearch(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res.SectionErrors) > 0 {
+			t.Fatalf("section errors %v", res.SectionErrors)
+		}
+		return res.Messages
+	}
+	ids := func(messages *sdk.GlobalSearchMessages) (res []string) {
+		for _, item := range messages.MessageList {
+			res = append(res, item.ConversationID+"/"+item.Message.ClientMsgID)
+		}
+		return res
+	}
+
+	var pages [][]string
+	var cursor string
+	for {
+		messages := search(&sdk.GlobalSearchParams{Count: 2, Cursors: map[string]string{constant.GlobalSearchSectionMessage: cursor}})
+		if messages.TotalCount != 5 {
+			t.Fatalf("total count %d", messages.TotalCount)
+		}
+		pages = append(pages, ids(messages))
+		if messages.IsEnd {
+			break
+		}
+		cursor = messages.NextCursor
+	}
+	want := "[[si_u1_u2/s4 si_u1_u2/s3] [sg_g1/g si_u1_u2/s2] [si_u1_u2/s1]]"
+	if got := fmt.Sprint(pages); got != want {
+		t.Fatalf("pages %s, want %s", got, want)
+	}
+
+	if got := fmt.Sprint(ids(search(&sdk.GlobalSearchParams{SenderUserIDList: []string{"u3"}}))); got != "[sg_g1/g]" {
+		t.Fatalf("messages of u3 %s", got)
+	}
+	messages := search(&sdk.GlobalSearchParams{ConversationTypeList: []int32{constant.SingleChatType}, Count: 10})
+	if messages.TotalCount != 4 || !messages.IsEnd {
+		t.Fatalf("single chat messages %v", ids(messages))
+	}
+	if item := messages.MessageList[0]; item.ShowName != "u2" || item.Message.TextElem == nil || item.Message.TextElem.Content != "hello s4" {
+		t.Fatalf("message item %+v", item)
+	}
+}
+
+type failingSearchDB struct {
+	db_interface.DataBase
+}
+
+func (failingSearchDB) SearchConversations(ctx context.Context, searchParam string) ([]*model_struct.LocalConversation, error) {
+	return nil, errors.New("search conversations failed")
+}
+
+func TestGlobalSearchSectionError(t *testing.T) {
+	ctx := context.Background()
+	c := newGlobalSearchConversation(t)
+	c.db = failingSearchDB{DataBase: c.db}
+	res, err := c.globalSearch(ctx, &sdk.GlobalSearchParams{KeywordList: []string{"hello"},
+		SectionList: []string{constant.GlobalSearchSectionConversation, constant.GlobalSearchSectionMessage}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := res.SectionErrors[constant.GlobalSearchSectionConversation]; !ok || len(res.SectionErrors) != 1 {
+		t.Fatalf("section errors %v", res.SectionErrors)
+	}
+	if res.Conversations != nil || res.Messages == nil || res.Messages.TotalCount != 5 {
+		t.Fatalf("partial result %+v", res)
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversation_msg
+
+import (
+	"context"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	sdk "github.com/openimsdk/openim-sdk-core/v3/pkg/sdk_params_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+	"github.com/openimsdk/protocol/sdkws"
+	"github.com/openimsdk/tools/errs"
+	"github.com/openimsdk/tools/log"
+	"github.com/openimsdk/tools/utils/datautil"
+)
+
+// doGroupReadDrawing adds the reader to the read info of the messages sent by the login user. The
+// tips of a whole conversation read carry no seqs, they cover the messages between the previous
+// read seq of the reader and the new one.
+func (c *Conversation) doGroupReadDrawing(ctx context.Context, conversation *model_struct.LocalConversation, tips *sdkws.MarkAsReadTips, readTime int64) error {
+	var hasReadSeq int64
+	readSeq, err := c.db.GetGroupMemberReadSeq(ctx, tips.ConversationID, tips.MarkAsReadUserID)
+	if err == nil {
+		hasReadSeq = readSeq.HasReadSeq
+	} else if !errs.ErrRecordNotFound.Is(err) {
+		return err
+	}
+	var messages []*model_struct.LocalChatLog
+	if len(tips.Seqs) > 0 {
+		messages, err = c.db.GetMessagesBySeqs(ctx, tips.ConversationID, tips.Seqs)
+	} else if tips.HasReadSeq > hasReadSeq {
+		messages, err = c.db.GetMessagesBySendIDSeqRange(ctx, tips.ConversationID, c.loginUserID, hasReadSeq, tips.HasReadSeq)
+	}
+	if err != nil {
+		return err
+	}
+	if tips.HasReadSeq > hasReadSeq {
+		if err := c.db.SetGroupMemberReadSeq(ctx, &model_struct.LocalGroupMemberReadSeq{ConversationID: tips.ConversationID,
+			UserID: tips.MarkAsReadUserID, HasReadSeq: tips.HasReadSeq}); err != nil {
+			log.ZWarn(ctx, "set group member read seq failed", err, "conversationID", tips.ConversationID, "userID", tips.MarkAsReadUserID)
+		}
+	}
+	var msgIDs []string
+	for _, message := range messages {
+		if message.SendID != c.loginUserID {
+			continue
+		}
+		attachInfo := sdk_struct.AttachedInfoElem{}
+		_ = utils.JsonStringToStruct(message.AttachedInfo, &attachInfo)
+		readInfo := &attachInfo.GroupHasReadInfo
+		if readInfo.GroupMemberCount > constant.GroupReadReceiptMaxMemberCount || datautil.Contain(tips.MarkAsReadUserID, readInfo.HasReadUserIDList...) {
+			continue
+		}
+		readInfo.HasReadUserIDList = append(readInfo.HasReadUserIDList, tips.MarkAsReadUserID)
+		readInfo.HasReadCount = int32(len(readInfo.HasReadUserIDList))
+		if err := c.db.UpdateColumnsMessage(ctx, tips.ConversationID, message.ClientMsgID, map[string]any{"attached_info": utils.StructToJsonString(attachInfo)}); err != nil {
+			log.ZWarn(ctx, "update group read info failed", err, "conversationID", tips.ConversationID, "clientMsgID", message.ClientMsgID)
+			continue
+		}
+		msgIDs = append(msgIDs, message.ClientMsgID)
+	}
+	if len(msgIDs) == 0 {
+		return nil
+	}
+	receipts := []*sdk_struct.MessageReceipt{{GroupID: conversation.GroupID, UserID: tips.MarkAsReadUserID, MsgIDList: msgIDs,
+		SessionType: conversation.ConversationType, ReadTime: readTime}}
+	c.msgListener().OnRecvGroupReadReceipt(utils.StructToJsonString(receipts))
+	return nil
+}
+
+// getMessageReadMembers splits the group members into those who have read a message sent by the
+// login user and those who have not. Members who joined after the message are left out.
+func (c *Conversation) getMessageReadMembers(ctx context.Context, conversationID, clientMsgID string) (*sdk.GetMessageReadMembersCallback, error) {
+	message, err := c.db.GetMessage(ctx, conversationID, clientMsgID)
+	if err != nil {
+		return nil, err
+	}
+	isGroup := message.SessionType == constant.ReadGroupChatType || message.SessionType == constant.WriteGroupChatType
+	if !isGroup || message.SendID != c.loginUserID {
+		return nil, sdkerrs.ErrArgs.WrapMsg("only the group messages sent by self have read members")
+	}
+	attachInfo := sdk_struct.AttachedInfoElem{}
+	_ = utils.JsonStringToStruct(message.AttachedInfo, &attachInfo)
+	members, err := c.db.GetGroupMemberListByGroupID(ctx, message.RecvID)
+	if err != nil {
+		return nil, err
+	}
+	hasRead := datautil.SliceSet(attachInfo.GroupHasReadInfo.HasReadUserIDList)
+	res := &sdk.GetMessageReadMembersCallback{
+		HasReadMemberList: []*model_struct.LocalGroupMember{},
+		UnreadMemberList:  []*model_struct.LocalGroupMember{},
+	}
+	for _, member := range members {
+		if member.UserID == c.loginUserID {
+			continue
+		}
+		if _, ok := hasRead[member.UserID]; ok {
+			res.HasReadMemberList = append(res.HasReadMemberList, member)
+		} else if member.JoinTime <= message.SendTime {
+			res.UnreadMemberList = append(res.UnreadMemberList, member)
+		}
+	}
+	res.HasReadCount = len(res.HasReadMemberList)
+	res.UnreadCount = len(res.UnreadMemberList)
+	return res, nil
+}
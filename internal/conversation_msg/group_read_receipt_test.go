@@ -0,0 +1,169 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !js
+
+package conversation_msg
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+	"github.com/openimsdk/protocol/sdkws"
+	"github.com/openimsdk/tools/utils/datautil"
+)
+
+func newTestGroupReadConversation(t *testing.T) (*Conversation, *testListener, *model_struct.LocalConversation) {
+	t.Helper()
+	ctx := context.Background()
+	c, listener := newTestConversation(t, "u1")
+	conversation := &model_struct.LocalConversation{ConversationID: "sg_g1", ConversationType: constant.ReadGroupChatType, GroupID: "g1"}
+	if err := c.db.InsertConversation(ctx, conversation); err != nil {
+		t.Fatal(err)
+	}
+	var messages []*model_struct.LocalChatLog
+	for _, m := range []struct {
+		clientMsgID string
+		sendID      string
+		memberCount int32
+	}{
+		{"m1", "u1", 4}, {"m2", "u1", 4}, {"m3", "u3", 4}, {"m4", "u1", 4}, {"m5", "u1", constant.GroupReadReceiptMaxMemberCount + 1}, {"m6", "u3", 4},
+	} {
+		seq := int64(len(messages) + 1)
+		msg := newTestTextMessage(conversation.ConversationID, m.clientMsgID, m.sendID, seq, seq*1000)
+		msg.RecvID = "g1"
+		msg.SessionType = constant.ReadGroupChatType
+		msg.AttachedInfo = utils.StructToJsonString(sdk_struct.AttachedInfoElem{GroupHasReadInfo: sdk_struct.GroupHasReadInfo{GroupMemberCount: m.memberCount}})
+		messages = append(messages, msg)
+	}
+	if err := c.db.BatchInsertMessageList(ctx, conversation.ConversationID, messages); err != nil {
+		t.Fatal(err)
+	}
+	return c, listener, conversation
+}
+
+func hasReadUserIDs(t *testing.T, c *Conversation, clientMsgID string) []string {
+	t.Helper()
+	msg, err := c.db.GetMessage(context.Background(), "sg_g1", clientMsgID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var attachInfo sdk_struct.AttachedInfoElem
+	_ = utils.JsonStringToStruct(msg.AttachedInfo, &attachInfo)
+	return attachInfo.GroupHasReadInfo.HasReadUserIDList
+}
+
+func TestGroupReadDrawingHasReadRange(t *testing.T) {
+	ctx := context.Background()
+	c, listener, conversation := newTestGroupReadConversation(t)
+	read := func(hasReadSeq int64, seqs ...int64) []string {
+		t.Helper()
+		listener.reset()
+		tips := &sdkws.MarkAsReadTips{ConversationID: conversation.ConversationID, MarkAsReadUserID: "u2", HasReadSeq: hasReadSeq, Seqs: seqs}
+		if err := c.doGroupReadDrawing(ctx, conversation, tips, 1); err != nil {
+			t.Fatal(err)
+		}
+		receipts := listener.get("OnRecvGroupReadReceipt")
+		if len(receipts) == 0 {
+			return nil
+		}
+		var list []*sdk_struct.MessageReceipt
+		if err := utils.JsonStringToStruct(receipts[0], &list); err != nil || len(list) != 1 {
+			t.Fatalf("receipts %v, err %v", receipts, err)
+		}
+		return list[0].MsgIDList
+	}
+
+	if got := read(2); !datautil.Equal(got, []string{"m1", "m2"}) {
+		t.Fatalf("first read receipt %v", got)
+	}
+	// the next read only covers the messages above the previous read seq, large groups are not tracked
+	if got := read(5); !datautil.Equal(got, []string{"m4"}) {
+		t.Fatalf("second read receipt %v", got)
+	}
+	if got := read(4); got != nil {
+		t.Fatalf("stale read receipt %v", got)
+	}
+	for clientMsgID, want := range map[string]int{"m1": 1, "m2": 1, "m3": 0, "m4": 1, "m5": 0, "m6": 0} {
+		if got := hasReadUserIDs(t, c, clientMsgID); len(got) != want {
+			t.Fatalf("%s has read %v, want %d readers", clientMsgID, got, want)
+		}
+	}
+	readSeq, err := c.db.GetGroupMemberReadSeq(ctx, conversation.ConversationID, "u2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if readSeq.HasReadSeq != 5 {
+		t.Fatalf("read seq %d, want 5", readSeq.HasReadSeq)
+	}
+}
+
+func TestGroupReadDrawingSeqs(t *testing.T) {
+	ctx := context.Background()
+	c, listener, conversation := newTestGroupReadConversation(t)
+	tips := &sdkws.MarkAsReadTips{ConversationID: conversation.ConversationID, MarkAsReadUserID: "u2", HasReadSeq: 4, Seqs: []int64{3, 4}}
+	if err := c.doGroupReadDrawing(ctx, conversation, tips, 1); err != nil {
+		t.Fatal(err)
+	}
+	if got := hasReadUserIDs(t, c, "m4"); !datautil.Equal(got, []string{"u2"}) {
+		t.Fatalf("m4 has read %v", got)
+	}
+	if got := hasReadUserIDs(t, c, "m1"); len(got) != 0 {
+		t.Fatalf("m1 outside the seqs has read %v", got)
+	}
+	if got := listener.get("OnRecvGroupReadReceipt"); len(got) != 1 {
+		t.Fatalf("read receipts %v", got)
+	}
+}
+
+func TestGetMessageReadMembers(t *testing.T) {
+	ctx := context.Background()
+	c, _, conversation := newTestGroupReadConversation(t)
+	for _, member := range []*model_struct.LocalGroupMember{
+		{GroupID: "g1", UserID: "u1"},
+		{GroupID: "g1", UserID: "u2"},
+		{GroupID: "g1", UserID: "u3"},
+		{GroupID: "g1", UserID: "u4", JoinTime: 10000},
+	} {
+		if err := c.db.InsertGroupMember(ctx, member); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tips := &sdkws.MarkAsReadTips{ConversationID: conversation.ConversationID, MarkAsReadUserID: "u2", HasReadSeq: 4}
+	if err := c.doGroupReadDrawing(ctx, conversation, tips, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := c.getMessageReadMembers(ctx, conversation.ConversationID, "m4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	userIDs := func(members []*model_struct.LocalGroupMember) []string {
+		return datautil.Slice(members, func(e *model_struct.LocalGroupMember) string { return e.UserID })
+	}
+	// the sender is left out, and so is u4 who joined after the message
+	if got := userIDs(res.HasReadMemberList); !datautil.Equal(got, []string{"u2"}) || res.HasReadCount != 1 {
+		t.Fatalf("has read members %v, count %d", got, res.HasReadCount)
+	}
+	if got := userIDs(res.UnreadMemberList); !datautil.Equal(got, []string{"u3"}) || res.UnreadCount != 1 {
+		t.Fatalf("unread members %v, count %d", got, res.UnreadCount)
+	}
+	if _, err := c.getMessageReadMembers(ctx, conversation.ConversationID, "m3"); err == nil {
+		t.Fatal("read members of a message sent by another user")
+	}
+}
@@ -0,0 +1,470 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversation_msg
+
+import (
+	"archive/zip"
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/common"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	sdk "github.com/openimsdk/openim-sdk-core/v3/pkg/sdk_params_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+	"github.com/openimsdk/tools/errs"
+	"github.com/openimsdk/tools/log"
+	"github.com/openimsdk/tools/utils/datautil"
+)
+
+// The archive is a zip file holding a manifest, one json lines file and one html page per
+// conversation, and the downloaded media when asked for.
+const (
+	historyArchiveVersion      = 1
+	historyArchiveManifestName = "manifest.json"
+	historyArchiveIndex        = "index.html"
+	historyArchiveMessageDir   = "messages/"
+	historyArchiveHTMLDir      = "html/"
+	historyArchiveMediaDir     = "media/"
+	historyArchivePageSize     = 500
+	historyArchiveImportBatch  = 200
+	historyArchiveMediaTimeout = 5 * time.Minute
+)
+
+var historyMediaClient = &http.Client{Timeout: historyArchiveMediaTimeout}
+
+type historyArchiveManifest struct {
+	Version       int                           `json:"version"`
+	ExportUserID  string                        `json:"exportUserID"`
+	ExportTime    int64                         `json:"exportTime"`
+	Conversations []*historyArchiveConversation `json:"conversations"`
+}
+
+type historyArchiveConversation struct {
+	ConversationID   string `json:"conversationID"`
+	ConversationType int32  `json:"conversationType"`
+	UserID           string `json:"userID,omitempty"`
+	GroupID          string `json:"groupID,omitempty"`
+	ShowName         string `json:"showName"`
+	FaceURL          string `json:"faceURL,omitempty"`
+	MessageCount     int    `json:"messageCount"`
+}
+
+// historyArchiveLine is one line of a conversation json lines file.
+type historyArchiveLine struct {
+	ConversationID string                `json:"conversationID"`
+	Message        *sdk_struct.MsgStruct `json:"message"`
+	// Media maps the media urls of the message to their path in the archive
+	Media map[string]string `json:"media,omitempty"`
+}
+
+func historyArchiveMessagePath(conversationID string) string {
+	return historyArchiveMessageDir + conversationID + ".jsonl"
+}
+
+func historyArchiveHTMLPath(conversationID string) string {
+	return historyArchiveHTMLDir + conversationID + ".html"
+}
+
+// exportConversationHistory writes the local messages of the conversations to an archive. The
+// archive is written next to the target and renamed once complete.
+func (c *Conversation) exportConversationHistory(ctx context.Context, req *sdk.ExportConversationHistoryParams) (*sdk.ExportConversationHistoryCallback, error) {
+	if req.ArchivePath == "" {
+		return nil, sdkerrs.ErrArgs.WrapMsg("archivePath is empty")
+	}
+	var (
+		conversations []*model_struct.LocalConversation
+		err           error
+	)
+	if len(req.ConversationIDList) == 0 {
+		conversations, err = c.db.GetAllConversations(ctx)
+	} else {
+		conversations, err = c.db.GetMultipleConversationDB(ctx, req.ConversationIDList)
+	}
+	if err != nil {
+		return nil, err
+	}
+	tmpPath := req.ArchivePath + ".tmp"
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return nil, errs.WrapMsg(err, "create archive failed", "path", tmpPath)
+	}
+	defer os.Remove(tmpPath)
+	res, err := c.writeHistoryArchive(ctx, file, conversations, req.DownloadMedia)
+	if closeErr := file.Close(); err == nil && closeErr != nil {
+		err = errs.Wrap(closeErr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmpPath, req.ArchivePath); err != nil {
+		return nil, errs.WrapMsg(err, "rename archive failed", "path", req.ArchivePath)
+	}
+	res.ArchivePath = req.ArchivePath
+	return res, nil
+}
+
+func (c *Conversation) writeHistoryArchive(ctx context.Context, w io.Writer, conversations []*model_struct.LocalConversation, downloadMedia bool) (*sdk.ExportConversationHistoryCallback, error) {
+	zw := zip.NewWriter(w)
+	// a media shared by several messages is stored once
+	media := make(map[string]string)
+	res := &sdk.ExportConversationHistoryCallback{}
+	manifest := &historyArchiveManifest{
+		Version:      historyArchiveVersion,
+		ExportUserID: c.loginUserID,
+		ExportTime:   time.Now().UnixMilli(),
+	}
+	for _, conversation := range conversations {
+		lines, err := c.getHistoryArchiveLines(ctx, conversation.ConversationID)
+		if err != nil {
+			return nil, err
+		}
+		if downloadMedia {
+			for _, line := range lines {
+				for _, mediaURL := range historyMediaURLs(line.Message) {
+					name, ok := media[mediaURL]
+					if !ok {
+						if name, err = downloadHistoryMedia(ctx, zw, mediaURL); err != nil {
+							log.ZWarn(ctx, "download history media failed", err, "url", mediaURL)
+							res.FailedMediaCount++
+							continue
+						}
+						media[mediaURL] = name
+						res.MediaCount++
+					}
+					if line.Media == nil {
+						line.Media = make(map[string]string)
+					}
+					line.Media[mediaURL] = name
+				}
+			}
+		}
+		if err := writeHistoryArchiveLines(zw, historyArchiveMessagePath(conversation.ConversationID), lines); err != nil {
+			return nil, err
+		}
+		item := &historyArchiveConversation{
+			ConversationID:   conversation.ConversationID,
+			ConversationType: conversation.ConversationType,
+			UserID:           conversation.UserID,
+			GroupID:          conversation.GroupID,
+			ShowName:         conversation.ShowName,
+			FaceURL:          conversation.FaceURL,
+			MessageCount:     len(lines),
+		}
+		if err := writeHistoryArchiveHTML(zw, historyArchiveHTMLPath(conversation.ConversationID), item, lines); err != nil {
+			return nil, err
+		}
+		manifest.Conversations = append(manifest.Conversations, item)
+		res.ConversationCount++
+		res.MessageCount += len(lines)
+	}
+	if err := writeHistoryArchiveIndex(zw, manifest); err != nil {
+		return nil, err
+	}
+	entry, err := zw.Create(historyArchiveManifestName)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	if err := json.NewEncoder(entry).Encode(manifest); err != nil {
+		return nil, errs.Wrap(err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return res, nil
+}
+
+// getHistoryArchiveLines reads all the messages of a conversation in send time order. Pages
+// overlap by one millisecond so messages sharing a send time are not lost between pages.
+func (c *Conversation) getHistoryArchiveLines(ctx context.Context, conversationID string) ([]*historyArchiveLine, error) {
+	var lines []*historyArchiveLine
+	seen := make(map[string]struct{})
+	list, err := c.db.GetMessageListNoTime(ctx, conversationID, historyArchivePageSize, true)
+	for {
+		if err != nil {
+			return nil, err
+		}
+		var added int
+		for _, v := range list {
+			if _, ok := seen[v.ClientMsgID]; ok {
+				continue
+			}
+			seen[v.ClientMsgID] = struct{}{}
+			added++
+			if v.Status == constant.MsgStatusHasDeleted {
+				continue
+			}
+			msg, err := c.localChatLogToMsgStruct(v)
+			if err != nil {
+				log.ZWarn(ctx, "parse message failed", err, "conversationID", conversationID, "clientMsgID", v.ClientMsgID)
+				continue
+			}
+			lines = append(lines, &historyArchiveLine{ConversationID: conversationID, Message: msg})
+		}
+		if len(list) < historyArchivePageSize {
+			return lines, nil
+		}
+		startTime := list[len(list)-1].SendTime
+		if added > 0 {
+			startTime--
+		}
+		list, err = c.db.GetMessageList(ctx, conversationID, historyArchivePageSize, startTime, true)
+	}
+}
+
+func writeHistoryArchiveLines(zw *zip.Writer, name string, lines []*historyArchiveLine) error {
+	entry, err := zw.Create(name)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	encoder := json.NewEncoder(entry)
+	for _, line := range lines {
+		if err := encoder.Encode(line); err != nil {
+			return errs.Wrap(err)
+		}
+	}
+	return nil
+}
+
+// historyMediaURLs returns the remote media referenced by a message.
+func historyMediaURLs(msg *sdk_struct.MsgStruct) []string {
+	var urls []string
+	add := func(u string) {
+		if u != "" && !datautil.Contain(u, urls...) {
+			urls = append(urls, u)
+		}
+	}
+	switch msg.ContentType {
+	case constant.Picture:
+		if msg.PictureElem != nil {
+			for _, picture := range []*sdk_struct.PictureBaseInfo{msg.PictureElem.SourcePicture, msg.PictureElem.SnapshotPicture} {
+				if picture != nil {
+					add(picture.Url)
+				}
+			}
+		}
+	case constant.Sound:
+		if msg.SoundElem != nil {
+			add(msg.SoundElem.SourceURL)
+		}
+	case constant.Video:
+		if msg.VideoElem != nil {
+			add(msg.VideoElem.VideoURL)
+			add(msg.VideoElem.SnapshotURL)
+		}
+	case constant.File:
+		if msg.FileElem != nil {
+			add(msg.FileElem.SourceURL)
+		}
+	}
+	return urls
+}
+
+// downloadHistoryMedia stores the media in the archive, named after its url.
+func downloadHistoryMedia(ctx context.Context, zw *zip.Writer, rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", errs.WrapMsg(err, "invalid media url", "url", rawURL)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", errs.New("unsupported media url", "url", rawURL).Wrap()
+	}
+	name := historyArchiveMediaDir + utils.Md5(rawURL) + path.Ext(u.Path)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return "", errs.Wrap(err)
+	}
+	resp, err := historyMediaClient.Do(req)
+	if err != nil {
+		return "", errs.Wrap(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", errs.New("download media failed", "url", rawURL, "status", resp.Status).Wrap()
+	}
+	entry, err := zw.Create(name)
+	if err != nil {
+		return "", errs.Wrap(err)
+	}
+	if _, err := io.Copy(entry, resp.Body); err != nil {
+		return "", errs.Wrap(err)
+	}
+	return name, nil
+}
+
+// importConversationHistory inserts the messages of an archive into the local database only.
+// Messages already stored are skipped, so importing the same archive twice is harmless.
+func (c *Conversation) importConversationHistory(ctx context.Context, archivePath string) (*sdk.ImportConversationHistoryCallback, error) {
+	zr, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return nil, errs.WrapMsg(err, "open archive failed", "path", archivePath)
+	}
+	defer zr.Close()
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+	var manifest historyArchiveManifest
+	if err := readHistoryArchiveJSON(files[historyArchiveManifestName], &manifest); err != nil {
+		return nil, err
+	}
+	if manifest.Version != historyArchiveVersion {
+		return nil, sdkerrs.ErrArgs.WrapMsg("unsupported archive version", "version", manifest.Version)
+	}
+	if manifest.ExportUserID != c.loginUserID {
+		return nil, sdkerrs.ErrArgs.WrapMsg("archive exported by another user", "exportUserID", manifest.ExportUserID)
+	}
+	res := &sdk.ImportConversationHistoryCallback{}
+	for _, conversation := range manifest.Conversations {
+		imported, skipped, err := c.importHistoryArchiveConversation(ctx, files[historyArchiveMessagePath(conversation.ConversationID)], conversation)
+		if err != nil {
+			return nil, err
+		}
+		res.ConversationCount++
+		res.MessageCount += imported
+		res.SkippedCount += skipped
+	}
+	c.doUpdateConversation(common.Cmd2Value{Ctx: ctx, Value: common.UpdateConNode{Action: constant.TotalUnreadMessageChanged}})
+	return res, nil
+}
+
+func (c *Conversation) importHistoryArchiveConversation(ctx context.Context, f *zip.File, conversation *historyArchiveConversation) (imported, skipped int, err error) {
+	if f == nil {
+		return 0, 0, sdkerrs.ErrArgs.WrapMsg("archive misses the conversation messages", "conversationID", conversation.ConversationID)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return 0, 0, errs.Wrap(err)
+	}
+	defer rc.Close()
+	var (
+		batch  []*sdk_struct.MsgStruct
+		latest *sdk_struct.MsgStruct
+	)
+	flush := func() error {
+		n, err := c.importHistoryMessages(ctx, conversation.ConversationID, batch)
+		if err != nil {
+			return err
+		}
+		imported += n
+		skipped += len(batch) - n
+		batch = batch[:0]
+		return nil
+	}
+	scanner := bufio.NewScanner(rc)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var line historyArchiveLine
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil || line.Message == nil || line.Message.ClientMsgID == "" {
+			log.ZWarn(ctx, "invalid archive line", err, "conversationID", conversation.ConversationID)
+			skipped++
+			continue
+		}
+		batch = append(batch, line.Message)
+		if latest == nil || line.Message.SendTime >= latest.SendTime {
+			latest = line.Message
+		}
+		if len(batch) >= historyArchiveImportBatch {
+			if err := flush(); err != nil {
+				return 0, 0, err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, 0, errs.Wrap(err)
+	}
+	if err := flush(); err != nil {
+		return 0, 0, err
+	}
+	if latest != nil {
+		lc := model_struct.LocalConversation{
+			ConversationID:    conversation.ConversationID,
+			ConversationType:  conversation.ConversationType,
+			UserID:            conversation.UserID,
+			GroupID:           conversation.GroupID,
+			ShowName:          conversation.ShowName,
+			FaceURL:           conversation.FaceURL,
+			LatestMsg:         utils.StructToJsonString(latest),
+			LatestMsgSendTime: latest.SendTime,
+		}
+		_ = common.TriggerCmdUpdateConversation(ctx, common.UpdateConNode{ConID: lc.ConversationID, Action: constant.AddConOrUpLatMsg, Args: lc}, c.GetCh())
+	}
+	return imported, skipped, nil
+}
+
+// importHistoryMessages inserts the messages missing locally. They are stored without seq, like
+// the messages inserted to local storage, and as read.
+func (c *Conversation) importHistoryMessages(ctx context.Context, conversationID string, msgs []*sdk_struct.MsgStruct) (int, error) {
+	if len(msgs) == 0 {
+		return 0, nil
+	}
+	clientMsgIDs := make([]string, 0, len(msgs))
+	for _, msg := range msgs {
+		clientMsgIDs = append(clientMsgIDs, msg.ClientMsgID)
+	}
+	// creates the message table of a conversation new on this device
+	if err := c.db.BatchInsertMessageList(ctx, conversationID, nil); err != nil {
+		return 0, err
+	}
+	exists, err := c.db.GetMessagesByClientMsgIDs(ctx, conversationID, clientMsgIDs)
+	if err != nil {
+		return 0, err
+	}
+	existMap := make(map[string]struct{}, len(exists))
+	for _, v := range exists {
+		existMap[v.ClientMsgID] = struct{}{}
+	}
+	var list []*model_struct.LocalChatLog
+	for _, msg := range msgs {
+		if _, ok := existMap[msg.ClientMsgID]; ok {
+			continue
+		}
+		existMap[msg.ClientMsgID] = struct{}{}
+		msg.Seq = 0
+		msg.IsRead = true
+		list = append(list, c.msgStructToLocalChatLog(msg))
+	}
+	if len(list) == 0 {
+		return 0, nil
+	}
+	if err := c.db.BatchInsertMessageList(ctx, conversationID, list); err != nil {
+		return 0, err
+	}
+	return len(list), nil
+}
+
+func readHistoryArchiveJSON(f *zip.File, v any) error {
+	if f == nil {
+		return sdkerrs.ErrArgs.WrapMsg("archive misses " + historyArchiveManifestName)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	defer rc.Close()
+	if err := json.NewDecoder(rc).Decode(v); err != nil {
+		return errs.WrapMsg(err, "invalid archive json", "name", f.Name)
+	}
+	return nil
+}
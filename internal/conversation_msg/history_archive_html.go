@@ -0,0 +1,117 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversation_msg
+
+import (
+	"archive/zip"
+	"html/template"
+	"time"
+
+	"github.com/openimsdk/tools/errs"
+)
+
+var historyArchiveTemplate = template.Must(template.New("history").Parse(`{{define "index"}}<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>Chat history</title></head>
+<body>
+<h1>Chat history</h1>
+<p>Exported at {{.ExportTime}}</p>
+<ul>
+{{range .Conversations}}<li><a href="{{.Link}}">{{.ShowName}}</a> ({{.MessageCount}} messages)</li>
+{{end}}</ul>
+</body></html>
+{{end}}{{define "conversation"}}<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>{{.ShowName}}</title>
+<style>
+body{font-family:sans-serif;max-width:800px;margin:auto}
+.msg{margin:8px 0}.meta{color:#888;font-size:12px}.content{white-space:pre-wrap}
+img{max-width:320px}
+</style></head>
+<body>
+<p><a href="../index.html">All conversations</a></p>
+<h1>{{.ShowName}}</h1>
+{{range .Messages}}<div class="msg">
+<div class="meta">{{.Sender}} · {{.SendTime}}</div>
+{{if .Image}}<img src="{{.Image}}">{{end}}{{if .Link}}<a href="{{.Link}}">{{.Text}}</a>{{else}}<div class="content">{{.Text}}</div>{{end}}
+</div>
+{{end}}</body></html>
+{{end}}`))
+
+type historyIndexView struct {
+	ExportTime    string
+	Conversations []historyIndexItem
+}
+
+type historyIndexItem struct {
+	ShowName     string
+	MessageCount int
+	Link         string
+}
+
+type historyConversationView struct {
+	ShowName string
+	Messages []historyMessageView
+}
+
+type historyMessageView struct {
+	Sender   string
+	SendTime string
+	Text     string
+	Image    string
+	Link     string
+}
+
+func formatHistoryTime(ms int64) string {
+	return time.UnixMilli(ms).Format("2006-01-02 15:04:05")
+}
+
+func writeHistoryArchiveIndex(zw *zip.Writer, manifest *historyArchiveManifest) error {
+	view := historyIndexView{ExportTime: formatHistoryTime(manifest.ExportTime)}
+	for _, conversation := range manifest.Conversations {
+		view.Conversations = append(view.Conversations, historyIndexItem{
+			ShowName:     conversation.ShowName,
+			MessageCount: conversation.MessageCount,
+			Link:         historyArchiveHTMLPath(conversation.ConversationID),
+		})
+	}
+	entry, err := zw.Create(historyArchiveIndex)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	return errs.Wrap(historyArchiveTemplate.ExecuteTemplate(entry, "index", view))
+}
+
+func writeHistoryArchiveHTML(zw *zip.Writer, name string, conversation *historyArchiveConversation, lines []*historyArchiveLine) error {
+	view := historyConversationView{ShowName: conversation.ShowName}
+	for _, line := range lines {
+		view.Messages = append(view.Messages, historyMessageViewOf(line))
+	}
+	entry, err := zw.Create(name)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	return errs.Wrap(historyArchiveTemplate.ExecuteTemplate(entry, "conversation", view))
+}
+
+// historyMessageViewOf renders a message as text, media point to the archive copy when downloaded.
+func historyMessageViewOf(line *historyArchiveLine) historyMessageView {
+	text := messageTextOf(line.Message, func(u string) string {
+		if name, ok := line.Media[u]; ok {
+			return "../" + name
+		}
+		return u
+	})
+	return historyMessageView{Sender: text.Sender, SendTime: formatHistoryTime(line.Message.SendTime), Text: text.Text,
+		Image: text.Image, Link: text.Link}
+}
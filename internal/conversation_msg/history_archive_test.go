@@ -0,0 +1,134 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !js
+
+package conversation_msg
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	sdk "github.com/openimsdk/openim-sdk-core/v3/pkg/sdk_params_callback"
+)
+
+func TestGetHistoryArchiveLines(t *testing.T) {
+	ctx := context.Background()
+	c, _ := newTestConversation(t, "u1")
+	conversationID := "si_u1_u2"
+	// the page boundary falls among messages sharing a send time
+	var list []*model_struct.LocalChatLog
+	for i := 0; i < historyArchivePageSize*2; i++ {
+		sendTime := int64(1000 + i)
+		if i >= historyArchivePageSize-5 && i < historyArchivePageSize+5 {
+			sendTime = 1000 + historyArchivePageSize
+		}
+		msg := newTestTextMessage(conversationID, fmt.Sprintf("m%04d", i), "u2", int64(i+1), sendTime)
+		if i == 10 {
+			msg.Status = constant.MsgStatusHasDeleted
+		}
+		list = append(list, msg)
+	}
+	if err := c.db.BatchInsertMessageList(ctx, conversationID, list); err != nil {
+		t.Fatal(err)
+	}
+	lines, err := c.getHistoryArchiveLines(ctx, conversationID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) != len(list)-1 {
+		t.Fatalf("got %d lines, want %d", len(lines), len(list)-1)
+	}
+	seen := make(map[string]struct{})
+	for i, line := range lines {
+		if _, ok := seen[line.Message.ClientMsgID]; ok {
+			t.Fatalf("duplicated message %s", line.Message.ClientMsgID)
+		}
+		seen[line.Message.ClientMsgID] = struct{}{}
+		if line.Message.ClientMsgID == "m0010" {
+			t.Fatal("deleted message exported")
+		}
+		if i > 0 && line.Message.SendTime < lines[i-1].Message.SendTime {
+			t.Fatalf("message %s out of order", line.Message.ClientMsgID)
+		}
+	}
+}
+
+func TestConversationHistoryRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	src, _ := newTestConversation(t, "u1")
+	conversationID := "si_u1_u2"
+	err := src.db.InsertConversation(ctx, &model_struct.LocalConversation{ConversationID: conversationID,
+		ConversationType: constant.SingleChatType, UserID: "u2", ShowName: "u2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	list := []*model_struct.LocalChatLog{
+		newTestTextMessage(conversationID, "m1", "u1", 1, 1000),
+		newTestTextMessage(conversationID, "m2", "u2", 2, 2000),
+		newTestTextMessage(conversationID, "m3", "u1", 3, 3000),
+	}
+	if err := src.db.BatchInsertMessageList(ctx, conversationID, list); err != nil {
+		t.Fatal(err)
+	}
+	archivePath := filepath.Join(t.TempDir(), "history.zip")
+	exported, err := src.exportConversationHistory(ctx, &sdk.ExportConversationHistoryParams{ArchivePath: archivePath})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exported.ConversationCount != 1 || exported.MessageCount != len(list) {
+		t.Fatalf("unexpected export %+v", exported)
+	}
+
+	other, _ := newTestConversation(t, "u3")
+	if _, err := other.importConversationHistory(ctx, archivePath); err == nil {
+		t.Fatal("archive of another user imported")
+	}
+
+	dst, _ := newTestConversation(t, "u1")
+	if err := dst.db.BatchInsertMessageList(ctx, conversationID, list[:1]); err != nil {
+		t.Fatal(err)
+	}
+	imported, err := dst.importConversationHistory(ctx, archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported.ConversationCount != 1 || imported.MessageCount != 2 || imported.SkippedCount != 1 {
+		t.Fatalf("unexpected import %+v", imported)
+	}
+	applyTestCommands(dst)
+	messages, err := dst.db.GetMessageListNoTime(ctx, conversationID, 10, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(messages) != len(list) {
+		t.Fatalf("got %d messages, want %d", len(messages), len(list))
+	}
+	for i, msg := range messages {
+		if msg.ClientMsgID != list[i].ClientMsgID || msg.Content != list[i].Content || msg.SendTime != list[i].SendTime {
+			t.Fatalf("message %d is %+v, want %+v", i, msg, list[i])
+		}
+	}
+	conversation, err := dst.db.GetConversation(ctx, conversationID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conversation.LatestMsgSendTime != 3000 {
+		t.Fatalf("latest message send time %d", conversation.LatestMsgSendTime)
+	}
+}
@@ -1,13 +1,17 @@
 package conversation_msg
 
 import (
+	"bytes"
+	"encoding/binary"
 	"image"
 	_ "image/gif"
-	_ "image/jpeg"
-	_ "image/png"
+	"image/jpeg"
+	"image/png"
+	"io"
 	"os"
 
 	_ "golang.org/x/image/bmp"
+	"golang.org/x/image/draw"
 	_ "golang.org/x/image/tiff"
 	_ "golang.org/x/image/webp"
 
@@ -16,19 +20,190 @@ import (
 )
 
 func getImageInfo(filePath string) (*sdk_struct.ImageInfo, error) {
-	file, err := os.Open(filePath)
+	data, err := os.ReadFile(filePath)
 	if err != nil {
 		return nil, errs.WrapMsg(err, "image file  open err")
 	}
-	defer file.Close()
-	info, err := file.Stat()
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
 	if err != nil {
-		return nil, err
+		return nil, errs.WrapMsg(err, "image file  decode err")
+	}
+	width, height := config.Width, config.Height
+	if format == "jpeg" && jpegOrientation(data) >= 5 {
+		width, height = height, width
 	}
-	img, format, err := image.Decode(file)
+	return &sdk_struct.ImageInfo{Width: int32(width), Height: int32(height), Type: "image/" + format, Size: int64(len(data))}, nil
+}
+
+const (
+	snapshotPictureMaxSize = 640
+	bigPictureMaxSize      = 1920
+	resizedJPEGQuality     = 85
+)
+
+// decodedImage is a picture decoded once to make all its resized copies.
+type decodedImage struct {
+	img         image.Image
+	format      string
+	orientation int // exif orientation, 1 when the pixels are stored upright
+}
+
+func decodeImage(path string) (*decodedImage, error) {
+	data, err := os.ReadFile(path)
 	if err != nil {
-		return nil, errs.WrapMsg(err, "image file  decode err")
+		return nil, errs.WrapMsg(err, "image file open err")
+	}
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, errs.WrapMsg(err, "image file decode err")
+	}
+	orientation := 1
+	if format == "jpeg" {
+		orientation = jpegOrientation(data)
+	}
+	return &decodedImage{img: img, format: format, orientation: orientation}, nil
+}
+
+// needsResize reports whether a copy fitting in maxSize x maxSize is needed. A gif is never resized,
+// it would lose its animation.
+func (d *decodedImage) needsResize(maxSize int) bool {
+	bounds := d.img.Bounds()
+	return d.format != "gif" && (bounds.Dx() > maxSize || bounds.Dy() > maxSize)
+}
+
+// resizeImage writes to w a copy of the image scaled down to fit in maxSize x maxSize and turned
+// upright, as png for png sources to keep the transparency and as jpeg otherwise.
+func resizeImage(src *decodedImage, w io.Writer, maxSize int) (*sdk_struct.ImageInfo, error) {
+	bounds := src.img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width > maxSize || height > maxSize {
+		if width >= height {
+			width, height = maxSize, max(1, height*maxSize/width)
+		} else {
+			width, height = max(1, width*maxSize/height), maxSize
+		}
+	}
+	scaled := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(scaled, scaled.Bounds(), src.img, bounds, draw.Src, nil)
+	dst := orientImage(scaled, src.orientation)
+	info := &sdk_struct.ImageInfo{Width: int32(dst.Bounds().Dx()), Height: int32(dst.Bounds().Dy())}
+	var err error
+	if src.format == "png" {
+		info.Type = "image/png"
+		err = png.Encode(w, dst)
+	} else {
+		info.Type = "image/jpeg"
+		err = jpeg.Encode(w, dst, &jpeg.Options{Quality: resizedJPEGQuality})
+	}
+	if err != nil {
+		return nil, errs.WrapMsg(err, "resized image encode err")
+	}
+	return info, nil
+}
+
+// orientImage turns the pixels upright for the exif orientation, 2 to 8 mirror and rotate them.
+func orientImage(src *image.NRGBA, orientation int) *image.NRGBA {
+	if orientation < 2 || orientation > 8 {
+		return src
+	}
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		for x := 0; x < dw; x++ {
+			var sx, sy int
+			switch orientation {
+			case 2: // mirrored
+				sx, sy = w-1-x, y
+			case 3: // rotated 180
+				sx, sy = w-1-x, h-1-y
+			case 4: // mirrored vertically
+				sx, sy = x, h-1-y
+			case 5: // transposed
+				sx, sy = y, x
+			case 6: // rotated 90 clockwise to be upright
+				sx, sy = y, h-1-x
+			case 7: // transversed
+				sx, sy = w-1-y, h-1-x
+			case 8: // rotated 90 counterclockwise to be upright
+				sx, sy = w-1-y, x
+			}
+			s, d := src.PixOffset(sx, sy), dst.PixOffset(x, y)
+			copy(dst.Pix[d:d+4], src.Pix[s:s+4])
+		}
+	}
+	return dst
+}
+
+// jpegOrientation reads the orientation from the exif segment of a jpeg, 1 when it has none.
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return 1
+		}
+		marker := data[i+1]
+		switch {
+		case marker == 0xFF: // fill byte
+			i++
+			continue
+		case marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7): // markers without a segment
+			i += 2
+			continue
+		case marker == 0xDA || marker == 0xD9: // the exif segment comes before the image data
+			return 1
+		}
+		size := int(binary.BigEndian.Uint16(data[i+2:]))
+		if size < 2 || i+2+size > len(data) {
+			return 1
+		}
+		if marker == 0xE1 {
+			if orientation := exifOrientation(data[i+4 : i+2+size]); orientation != 0 {
+				return orientation
+			}
+		}
+		i += 2 + size
+	}
+	return 1
+}
+
+// exifOrientation reads the orientation tag of the first image directory, 0 when it has none.
+func exifOrientation(segment []byte) int {
+	if len(segment) < 14 || string(segment[:6]) != "Exif\x00\x00" {
+		return 0
+	}
+	tiff := segment[6:]
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0
+	}
+	offset := int(order.Uint32(tiff[4:]))
+	if offset < 8 || offset+2 > len(tiff) {
+		return 0
+	}
+	count := int(order.Uint16(tiff[offset:]))
+	for i := 0; i < count; i++ {
+		entry := offset + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 0
+		}
+		if order.Uint16(tiff[entry:]) != 0x0112 {
+			continue
+		}
+		if orientation := int(order.Uint16(tiff[entry+8:])); orientation >= 1 && orientation <= 8 {
+			return orientation
+		}
+		return 0
 	}
-	size := img.Bounds().Max
-	return &sdk_struct.ImageInfo{Width: int32(size.X), Height: int32(size.Y), Type: "image/" + format, Size: info.Size()}, nil
+	return 0
 }
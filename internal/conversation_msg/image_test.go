@@ -0,0 +1,181 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !js
+
+package conversation_msg
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// exifSegment is an APP1 segment holding only the orientation tag.
+func exifSegment(order binary.ByteOrder, orientation uint16) []byte {
+	tiff := make([]byte, 8+2+12+4)
+	if order == binary.LittleEndian {
+		copy(tiff, "II")
+	} else {
+		copy(tiff, "MM")
+	}
+	order.PutUint16(tiff[2:], 42)
+	order.PutUint32(tiff[4:], 8)
+	order.PutUint16(tiff[8:], 1)
+	order.PutUint16(tiff[10:], 0x0112)
+	order.PutUint16(tiff[12:], 3)
+	order.PutUint32(tiff[14:], 1)
+	order.PutUint16(tiff[18:], orientation)
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	return append(segment, payload...)
+}
+
+// halfImage is red on its left half and blue on its right half.
+func halfImage(width, height int) image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if x < width/2 {
+				img.Set(x, y, color.NRGBA{R: 255, A: 255})
+			} else {
+				img.Set(x, y, color.NRGBA{B: 255, A: 255})
+			}
+		}
+	}
+	return img
+}
+
+func writeTestJPEG(t *testing.T, path string, img image.Image, exif []byte) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 100}); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	data = append(append(append([]byte{}, data[:2]...), exif...), data[2:]...)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func isRed(c color.Color) bool {
+	r, g, b, _ := c.RGBA()
+	return r > 0xC000 && g < 0x4000 && b < 0x4000
+}
+
+func TestJPEGOrientation(t *testing.T) {
+	for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		for orientation := uint16(1); orientation <= 8; orientation++ {
+			data := append([]byte{0xFF, 0xD8}, exifSegment(order, orientation)...)
+			data = append(data, 0xFF, 0xDA, 0, 2)
+			if got := jpegOrientation(data); got != int(orientation) {
+				t.Fatalf("%v orientation %d read as %d", order, orientation, got)
+			}
+		}
+	}
+	if got := jpegOrientation([]byte{0xFF, 0xD8, 0xFF, 0xDA, 0, 2}); got != 1 {
+		t.Fatalf("jpeg without exif has orientation %d", got)
+	}
+	if got := jpegOrientation([]byte("not a jpeg")); got != 1 {
+		t.Fatalf("not a jpeg has orientation %d", got)
+	}
+}
+
+func TestResizeImage(t *testing.T) {
+	dir := t.TempDir()
+
+	// stored sideways, the left half becomes the top once upright
+	rotated := filepath.Join(dir, "rotated.jpg")
+	writeTestJPEG(t, rotated, halfImage(400, 200), exifSegment(binary.LittleEndian, 6))
+	info, err := getImageInfo(rotated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Width != 200 || info.Height != 400 || info.Type != "image/jpeg" {
+		t.Fatalf("image info %+v", info)
+	}
+	img, err := decodeImage(rotated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.orientation != 6 || !img.needsResize(100) || img.needsResize(400) {
+		t.Fatalf("decoded orientation %d", img.orientation)
+	}
+	var buf bytes.Buffer
+	resized, err := resizeImage(img, &buf, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resized.Width != 50 || resized.Height != 100 || resized.Type != "image/jpeg" {
+		t.Fatalf("resized image info %+v", resized)
+	}
+	out, err := jpeg.Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Bounds().Dx() != 50 || out.Bounds().Dy() != 100 {
+		t.Fatalf("resized image bounds %v", out.Bounds())
+	}
+	if !isRed(out.At(25, 10)) || isRed(out.At(25, 90)) {
+		t.Fatal("resized image is not upright")
+	}
+
+	// png keeps its format, a gif is never resized
+	pngPath := filepath.Join(dir, "picture.png")
+	var pngData bytes.Buffer
+	if err := png.Encode(&pngData, halfImage(300, 150)); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(pngPath, pngData.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	img, err = decodeImage(pngPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf.Reset()
+	resized, err = resizeImage(img, &buf, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resized.Width != 100 || resized.Height != 50 || resized.Type != "image/png" {
+		t.Fatalf("resized png info %+v", resized)
+	}
+	if _, err := png.Decode(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	gifPath := filepath.Join(dir, "picture.gif")
+	var gifData bytes.Buffer
+	if err := gif.Encode(&gifData, halfImage(300, 150), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(gifPath, gifData.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if img, err = decodeImage(gifPath); err != nil {
+		t.Fatal(err)
+	}
+	if img.needsResize(100) {
+		t.Fatal("gif would be resized")
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversation_msg
+
+import (
+	"context"
+	"sync"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+	"github.com/openimsdk/tools/errs"
+	"github.com/openimsdk/tools/log"
+)
+
+// PreSendInterceptor is called before a message is stored and sent. It may modify msg, returning
+// an error rejects the message and the send fails with that error.
+type PreSendInterceptor func(ctx context.Context, msg *sdk_struct.MsgStruct) error
+
+// PostReceiveInterceptor is called for every new message from other users before it is stored
+// and delivered to the listeners, also for the history synced after a reinstall. It may modify msg,
+// returning an error drops the message.
+type PostReceiveInterceptor func(ctx context.Context, conversationID string, msg *sdk_struct.MsgStruct) error
+
+type interceptor[T any] struct {
+	name string
+	fn   T
+}
+
+// interceptors keeps the registered hooks in registration order.
+type interceptors struct {
+	lock        sync.RWMutex
+	preSend     []interceptor[PreSendInterceptor]
+	postReceive []interceptor[PostReceiveInterceptor]
+}
+
+// addInterceptor appends fn, an interceptor registered under the same name is replaced in place.
+func addInterceptor[T any](list []interceptor[T], name string, fn T) []interceptor[T] {
+	for i := range list {
+		if list[i].name == name {
+			res := append([]interceptor[T]{}, list...)
+			res[i].fn = fn
+			return res
+		}
+	}
+	return append(list[:len(list):len(list)], interceptor[T]{name: name, fn: fn})
+}
+
+func removeInterceptor[T any](list []interceptor[T], name string) []interceptor[T] {
+	res := make([]interceptor[T], 0, len(list))
+	for _, v := range list {
+		if v.name != name {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+// interceptError keeps the code chosen by the interceptor, other errors are reported as ErrMsgIntercepted.
+func interceptError(name string, err error) error {
+	if _, ok := errs.Unwrap(err).(errs.CodeError); ok {
+		return err
+	}
+	return sdkerrs.ErrMsgIntercepted.WrapMsg(err.Error(), "interceptor", name)
+}
+
+func (c *Conversation) AddPreSendInterceptor(name string, fn PreSendInterceptor) {
+	c.interceptors.lock.Lock()
+	defer c.interceptors.lock.Unlock()
+	c.interceptors.preSend = addInterceptor(c.interceptors.preSend, name, fn)
+}
+
+func (c *Conversation) AddPostReceiveInterceptor(name string, fn PostReceiveInterceptor) {
+	c.interceptors.lock.Lock()
+	defer c.interceptors.lock.Unlock()
+	c.interceptors.postReceive = addInterceptor(c.interceptors.postReceive, name, fn)
+}
+
+// RemoveInterceptor removes the pre-send and post-receive interceptors registered under name.
+func (c *Conversation) RemoveInterceptor(name string) {
+	c.interceptors.lock.Lock()
+	defer c.interceptors.lock.Unlock()
+	c.interceptors.preSend = removeInterceptor(c.interceptors.preSend, name)
+	c.interceptors.postReceive = removeInterceptor(c.interceptors.postReceive, name)
+}
+
+func (c *Conversation) preSend(ctx context.Context, msg *sdk_struct.MsgStruct) error {
+	c.interceptors.lock.RLock()
+	list := c.interceptors.preSend
+	c.interceptors.lock.RUnlock()
+	for _, v := range list {
+		if err := v.fn(ctx, msg); err != nil {
+			log.ZWarn(ctx, "message rejected by pre send interceptor", err, "interceptor", v.name, "clientMsgID", msg.ClientMsgID)
+			return interceptError(v.name, err)
+		}
+	}
+	return nil
+}
+
+func (c *Conversation) postReceive(ctx context.Context, conversationID string, msg *sdk_struct.MsgStruct) error {
+	c.interceptors.lock.RLock()
+	list := c.interceptors.postReceive
+	c.interceptors.lock.RUnlock()
+	for _, v := range list {
+		if err := v.fn(ctx, conversationID, msg); err != nil {
+			return interceptError(v.name, err)
+		}
+	}
+	return nil
+}
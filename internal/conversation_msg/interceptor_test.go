@@ -0,0 +1,122 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !js
+
+package conversation_msg
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/common"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+	"github.com/openimsdk/protocol/sdkws"
+)
+
+func TestPostReceiveOrder(t *testing.T) {
+	ctx := context.Background()
+	c, _ := newTestConversation(t, "u1")
+	var calls []string
+	add := func(name string) {
+		c.AddPostReceiveInterceptor(name, func(ctx context.Context, conversationID string, msg *sdk_struct.MsgStruct) error {
+			calls = append(calls, name)
+			return nil
+		})
+	}
+	add("a")
+	add("b")
+	add("c")
+	add("a") // replaced in place, keeps its position
+	c.RemoveInterceptor("b")
+	if err := c.postReceive(ctx, "si_u1_u2", &sdk_struct.MsgStruct{}); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(calls, ","); got != "a,c" {
+		t.Fatalf("interceptors called in order %s", got)
+	}
+
+	c.AddPostReceiveInterceptor("reject", func(ctx context.Context, conversationID string, msg *sdk_struct.MsgStruct) error {
+		return errors.New("rejected")
+	})
+	add("after")
+	calls = nil
+	err := c.postReceive(ctx, "si_u1_u2", &sdk_struct.MsgStruct{})
+	if !sdkerrs.ErrMsgIntercepted.Is(err) {
+		t.Fatalf("postReceive error %v", err)
+	}
+	if got := strings.Join(calls, ","); got != "a,c" {
+		t.Fatalf("interceptors after the rejection were called: %s", got)
+	}
+}
+
+func TestPostReceiveInReinstall(t *testing.T) {
+	ctx := context.Background()
+	c, _ := newTestConversation(t, "u1")
+	conversationID := "si_u1_u2"
+	if err := c.db.InsertConversation(ctx, &model_struct.LocalConversation{ConversationID: conversationID,
+		ConversationType: constant.SingleChatType, UserID: "u2"}); err != nil {
+		t.Fatal(err)
+	}
+	c.AddPostReceiveInterceptor("filter", func(ctx context.Context, conversationID string, msg *sdk_struct.MsgStruct) error {
+		switch msg.ClientMsgID {
+		case "drop":
+			return errors.New("dropped")
+		case "rewrite":
+			msg.TextElem.Content = "rewritten"
+		}
+		return nil
+	})
+	newMsg := func(clientMsgID, sendID string, seq int64) *sdkws.MsgData {
+		return &sdkws.MsgData{ClientMsgID: clientMsgID, ServerMsgID: clientMsgID, SendID: sendID, RecvID: "u1",
+			SessionType: constant.SingleChatType, ContentType: constant.Text, Seq: seq, SendTime: 1000 + seq,
+			Content: []byte(utils.StructToJsonString(sdk_struct.TextElem{Content: clientMsgID}))}
+	}
+	msgs := []*sdkws.MsgData{newMsg("keep", "u2", 1), newMsg("rewrite", "u2", 2), newMsg("drop", "u2", 3)}
+	c.doMsgSyncByReinstalled(common.Cmd2Value{Ctx: ctx, Value: sdk_struct.CmdMsgSyncInReinstall{
+		Msgs: map[string]*sdkws.PullMsgs{conversationID: {Msgs: msgs}}, Total: len(msgs)}})
+
+	for clientMsgID, want := range map[string]string{"keep": "keep", "rewrite": "rewritten"} {
+		msg, err := c.db.GetMessage(ctx, conversationID, clientMsgID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var elem sdk_struct.TextElem
+		_ = utils.JsonStringToStruct(msg.Content, &elem)
+		if msg.Status != constant.MsgStatusSendSuccess || elem.Content != want {
+			t.Fatalf("message %s status %d content %q", clientMsgID, msg.Status, elem.Content)
+		}
+	}
+	// the dropped message keeps its seq as filtered, and is not the latest message
+	dropped, err := c.db.GetMessage(ctx, conversationID, "drop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dropped.Status != constant.MsgStatusFiltered || dropped.Seq != 3 {
+		t.Fatalf("dropped message status %d seq %d", dropped.Status, dropped.Seq)
+	}
+	lc, err := c.db.GetConversation(ctx, conversationID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lc.LatestMsgSendTime != 1002 || !strings.Contains(lc.LatestMsg, "rewritten") {
+		t.Fatalf("latest message %d %s", lc.LatestMsgSendTime, lc.LatestMsg)
+	}
+}
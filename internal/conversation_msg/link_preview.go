@@ -0,0 +1,237 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversation_msg
+
+import (
+	"context"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/linkpreview"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+	"github.com/openimsdk/tools/errs"
+	"github.com/openimsdk/tools/log"
+	"github.com/openimsdk/tools/utils/datautil"
+)
+
+const (
+	// linkPreviewExpiration is how long a cached preview is used before it is fetched again.
+	linkPreviewExpiration = 7 * 24 * time.Hour
+	// maxLinkPreviewPrefetch limits the previews fetched in the background for one batch of new messages.
+	maxLinkPreviewPrefetch = 10
+)
+
+// mergeURLEntities adds a url entity for every link in text that is not already covered by one
+// of the entities passed in by the caller.
+func mergeURLEntities(text string, entities []*sdk_struct.MessageEntity) []*sdk_struct.MessageEntity {
+	for _, urlEntity := range linkpreview.ExtractURLEntities(text) {
+		overlap := false
+		for _, entity := range entities {
+			if urlEntity.Offset < entity.Offset+entity.Length && entity.Offset < urlEntity.Offset+urlEntity.Length {
+				overlap = true
+				break
+			}
+		}
+		if !overlap {
+			entities = append(entities, urlEntity)
+		}
+	}
+	return entities
+}
+
+// previewURL returns the link whose preview is shown for the message, the first one in the text.
+func previewURL(msg *sdk_struct.MsgStruct) string {
+	switch msg.ContentType {
+	case constant.Text:
+		if msg.TextElem == nil {
+			return ""
+		}
+		for _, entity := range msg.TextElem.MessageEntityList {
+			if entity.Type == constant.MessageEntityTypeURL && entity.Url != "" {
+				return entity.Url
+			}
+		}
+		if entities := linkpreview.ExtractURLEntities(msg.TextElem.Content); len(entities) > 0 {
+			return entities[0].Url
+		}
+	case constant.AdvancedText:
+		if msg.AdvancedTextElem == nil {
+			return ""
+		}
+		for _, entity := range msg.AdvancedTextElem.MessageEntityList {
+			if entity.Type == constant.MessageEntityTypeURL && entity.Url != "" {
+				return entity.Url
+			}
+		}
+		if entities := linkpreview.ExtractURLEntities(msg.AdvancedTextElem.Text); len(entities) > 0 {
+			return entities[0].Url
+		}
+	}
+	return ""
+}
+
+// embeddedLinkPreview returns the preview the sender put into the content of the message.
+func embeddedLinkPreview(msg *sdk_struct.MsgStruct) *sdk_struct.LinkPreview {
+	switch msg.ContentType {
+	case constant.Text:
+		if msg.TextElem != nil {
+			return msg.TextElem.LinkPreview
+		}
+	case constant.AdvancedText:
+		if msg.AdvancedTextElem != nil {
+			return msg.AdvancedTextElem.LinkPreview
+		}
+	}
+	return nil
+}
+
+// embedLinkPreview puts the preview of the link of a message being sent into its content, so that
+// every platform shows the card fetched by the sender instead of fetching the link again.
+func (c *Conversation) embedLinkPreview(ctx context.Context, s *sdk_struct.MsgStruct) {
+	if !c.EnableLinkPreview || embeddedLinkPreview(s) != nil {
+		return
+	}
+	url := previewURL(s)
+	if url == "" {
+		return
+	}
+	preview, err := c.getLinkPreview(ctx, url)
+	if err != nil {
+		log.ZDebug(ctx, "get link preview of the message failed", "url", url, "err", err)
+		return
+	}
+	if preview.Title == "" && preview.Description == "" && preview.ImageURL == "" {
+		return
+	}
+	switch s.ContentType {
+	case constant.Text:
+		s.TextElem.LinkPreview = preview
+	case constant.AdvancedText:
+		s.AdvancedTextElem.LinkPreview = preview
+	}
+	s.LinkPreview = preview
+}
+
+// attachLinkPreviews sets the previews on the messages, the one embedded by the sender or else the
+// cached one.
+func (c *Conversation) attachLinkPreviews(ctx context.Context, msgs []*sdk_struct.MsgStruct) {
+	msgURLs := make(map[*sdk_struct.MsgStruct]string)
+	for _, msg := range msgs {
+		if preview := embeddedLinkPreview(msg); preview != nil {
+			msg.LinkPreview = preview
+			continue
+		}
+		if url := previewURL(msg); url != "" {
+			msgURLs[msg] = url
+		}
+	}
+	if len(msgURLs) == 0 {
+		return
+	}
+	urls := datautil.Distinct(datautil.Values(msgURLs))
+	previews, err := c.db.GetLinkPreviewList(ctx, urls)
+	if err != nil {
+		log.ZWarn(ctx, "GetLinkPreviewList failed", err, "urls", urls)
+		return
+	}
+	previewMap := datautil.SliceToMap(previews, func(e *model_struct.LocalLinkPreview) string { return e.URL })
+	for msg, url := range msgURLs {
+		if preview, ok := previewMap[url]; ok {
+			msg.LinkPreview = localLinkPreviewToSdk(preview)
+		}
+	}
+}
+
+// prefetchLinkPreviews fetches in the background the missing previews of the new messages sent by
+// the login user, so that they are cached by the time the messages are read again. The links sent
+// by others are only fetched when the message is opened, not to tell the sender when it arrived.
+func (c *Conversation) prefetchLinkPreviews(ctx context.Context, msgs []*sdk_struct.MsgStruct) {
+	if !c.EnableLinkPreview {
+		return
+	}
+	var urls []string
+	for _, msg := range msgs {
+		if msg.SendID == c.loginUserID && msg.LinkPreview == nil {
+			if url := previewURL(msg); url != "" && !datautil.Contain(url, urls...) {
+				urls = append(urls, url)
+			}
+		}
+	}
+	if len(urls) == 0 {
+		return
+	}
+	if len(urls) > maxLinkPreviewPrefetch {
+		urls = urls[:maxLinkPreviewPrefetch]
+	}
+	go func() {
+		for _, url := range urls {
+			if _, err := c.fetchLinkPreview(ctx, url); err != nil {
+				log.ZDebug(ctx, "prefetch link preview failed", "url", url, "err", err)
+			}
+		}
+	}()
+}
+
+func (c *Conversation) fetchLinkPreview(ctx context.Context, url string) (*sdk_struct.LinkPreview, error) {
+	preview, err := linkpreview.Fetch(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.db.InsertLinkPreview(ctx, &model_struct.LocalLinkPreview{
+		URL:         url,
+		Title:       preview.Title,
+		Description: preview.Description,
+		ImageURL:    preview.ImageURL,
+		SiteName:    preview.SiteName,
+		UpdateTime:  utils.GetCurrentTimestampByMill(),
+	}); err != nil {
+		return nil, err
+	}
+	return preview, nil
+}
+
+func (c *Conversation) getLinkPreview(ctx context.Context, url string) (*sdk_struct.LinkPreview, error) {
+	cached, err := c.db.GetLinkPreview(ctx, url)
+	if err != nil && !errs.ErrRecordNotFound.Is(err) {
+		return nil, err
+	}
+	if err == nil && (!c.EnableLinkPreview || time.Since(time.UnixMilli(cached.UpdateTime)) < linkPreviewExpiration) {
+		return localLinkPreviewToSdk(cached), nil
+	}
+	if !c.EnableLinkPreview {
+		return nil, errs.WrapMsg(err, "link preview is not cached and fetching is disabled", "url", url)
+	}
+	preview, fetchErr := c.fetchLinkPreview(ctx, url)
+	if fetchErr != nil {
+		if err == nil {
+			log.ZWarn(ctx, "refresh link preview failed, use the cached one", fetchErr, "url", url)
+			return localLinkPreviewToSdk(cached), nil
+		}
+		return nil, fetchErr
+	}
+	return preview, nil
+}
+
+func localLinkPreviewToSdk(preview *model_struct.LocalLinkPreview) *sdk_struct.LinkPreview {
+	return &sdk_struct.LinkPreview{
+		URL:         preview.URL,
+		Title:       preview.Title,
+		Description: preview.Description,
+		ImageURL:    preview.ImageURL,
+		SiteName:    preview.SiteName,
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !js
+
+package conversation_msg
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openimsdk/openim-sdk-core/v3/internal/user"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+func TestEmbedLinkPreview(t *testing.T) {
+	ctx := context.Background()
+	c, _ := newTestConversation(t, "u1")
+	c.user = user.NewUser(c.db, "u1", nil)
+	c.EnableLinkPreview = true
+	if err := c.db.InsertLoginUser(ctx, &model_struct.LocalUser{UserID: "u1", Nickname: "me"}); err != nil {
+		t.Fatal(err)
+	}
+	cache := func(title string) {
+		t.Helper()
+		if err := c.db.InsertLinkPreview(ctx, &model_struct.LocalLinkPreview{URL: "https://example.com/a", Title: title,
+			UpdateTime: utils.GetCurrentTimestampByMill()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cache("Example")
+
+	s, err := c.CreateTextMessage(ctx, "see https://example.com/a now")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entities := s.TextElem.MessageEntityList; len(entities) != 1 || entities[0].Type != constant.MessageEntityTypeURL ||
+		entities[0].Url != "https://example.com/a" || entities[0].Offset != 4 {
+		t.Fatalf("url entities %s", utils.StructToJsonString(entities))
+	}
+	c.embedLinkPreview(ctx, s)
+	if s.TextElem.LinkPreview == nil || s.TextElem.LinkPreview.Title != "Example" || s.LinkPreview != s.TextElem.LinkPreview {
+		t.Fatalf("embedded preview %+v", s.TextElem.LinkPreview)
+	}
+
+	// the receivers show the card of the sender, not the one they cached
+	cache("Stale")
+	msg, err := c.localChatLogToMsgStruct(c.msgStructToLocalChatLog(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.attachLinkPreviews(ctx, []*sdk_struct.MsgStruct{msg})
+	if msg.LinkPreview == nil || msg.LinkPreview.Title != "Example" {
+		t.Fatalf("preview of the received message %+v", msg.LinkPreview)
+	}
+
+	// messages sent without a preview fall back to the cache
+	plain, err := c.CreateTextMessage(ctx, "https://example.com/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.EnableLinkPreview = false
+	c.embedLinkPreview(ctx, plain)
+	if plain.TextElem.LinkPreview != nil {
+		t.Fatal("preview embedded with link previews disabled")
+	}
+	msg, err = c.localChatLogToMsgStruct(c.msgStructToLocalChatLog(plain))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.attachLinkPreviews(ctx, []*sdk_struct.MsgStruct{msg})
+	if msg.LinkPreview == nil || msg.LinkPreview.Title != "Stale" {
+		t.Fatalf("cached preview %+v", msg.LinkPreview)
+	}
+}
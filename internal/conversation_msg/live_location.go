@@ -0,0 +1,356 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversation_msg
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jinzhu/copier"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+	"github.com/openimsdk/protocol/sdkws"
+	"github.com/openimsdk/tools/errs"
+	"github.com/openimsdk/tools/log"
+	"github.com/openimsdk/tools/utils/datautil"
+	"github.com/patrickmn/go-cache"
+)
+
+const (
+	liveLocationMinDuration  = time.Minute                  // shortest sharing session
+	liveLocationMaxDuration  = time.Hour * 8                // longest sharing session
+	liveLocationMinInterval  = time.Second * 3              // minimum interval between two updates sent
+	liveLocationStaleTimeout = time.Minute * 2              // a position not updated for this long is dropped
+	liveLocationMsgTimeout   = time.Second * 5              // message sending timeout
+	liveLocationCleanup      = liveLocationMinInterval * 10 // expired positions cleanup interval
+	liveLocationHeartbeat    = liveLocationStaleTimeout / 2 // interval of the updates sent when the position does not change
+)
+
+// LiveLocation is the last known position of a user sharing their location in a conversation.
+type LiveLocation struct {
+	ConversationID string  `json:"conversationID"`
+	UserID         string  `json:"userID"`
+	SessionID      string  `json:"sessionID"`
+	Longitude      float64 `json:"longitude"`
+	Latitude       float64 `json:"latitude"`
+	Accuracy       float64 `json:"accuracy"`
+	StartTime      int64   `json:"startTime"`
+	UpdateTime     int64   `json:"updateTime"`
+	ExpireTime     int64   `json:"expireTime"`
+}
+
+type LiveLocationChangedData struct {
+	ConversationID   string          `json:"conversationID"`
+	LiveLocationList []*LiveLocation `json:"liveLocationList"`
+}
+
+type liveLocationKey struct {
+	ConversationID string `json:"cid,omitempty"`
+	UserID         string `json:"uid,omitempty"`
+}
+
+// liveLocationSession is a sharing session started by the login user.
+type liveLocationSession struct {
+	conversation *model_struct.LocalConversation
+	location     *LiveLocation
+	lastSendTime time.Time
+	// timer sends the last position late when it was throttled, or again when it has not changed
+	timer *time.Timer
+}
+
+func newLiveLocation(c *Conversation) *liveLocation {
+	l := &liveLocation{
+		conv:      c,
+		sessions:  make(map[string]*liveLocationSession),
+		positions: cache.New(liveLocationStaleTimeout, liveLocationCleanup),
+	}
+	l.positions.OnEvicted(func(key string, val interface{}) {
+		var data liveLocationKey
+		if err := json.Unmarshal([]byte(key), &data); err != nil {
+			return
+		}
+		if data.UserID == c.loginUserID {
+			l.lock.Lock()
+			if session, ok := l.sessions[data.ConversationID]; ok && session.location == val.(*LiveLocation) {
+				session.timer.Stop()
+				delete(l.sessions, data.ConversationID)
+			}
+			l.lock.Unlock()
+		}
+		l.changes(data.ConversationID)
+	})
+	return l
+}
+
+// liveLocation keeps the positions of everyone sharing their location, the login user included.
+// The positions expire with their session, or earlier when the sharer stops sending updates.
+type liveLocation struct {
+	lock     sync.Mutex
+	sessions map[string]*liveLocationSession
+
+	positions *cache.Cache
+
+	conv *Conversation
+}
+
+func (l *liveLocation) getKey(conversationID string, userID string) string {
+	data, err := json.Marshal(liveLocationKey{ConversationID: conversationID, UserID: userID})
+	if err != nil {
+		panic(err)
+	}
+	return string(data)
+}
+
+func checkCoordinate(longitude, latitude float64) error {
+	if longitude < -180 || longitude > 180 || latitude < -90 || latitude > 90 {
+		return errs.ErrArgs.WrapMsg("invalid coordinate", "longitude", longitude, "latitude", latitude)
+	}
+	return nil
+}
+
+// StartLiveLocation starts sharing the location for duration seconds, a session already running in
+// the conversation is replaced.
+func (l *liveLocation) StartLiveLocation(ctx context.Context, conversationID string, duration int64, longitude, latitude, accuracy float64) (*LiveLocation, error) {
+	d := time.Duration(duration) * time.Second
+	if d < liveLocationMinDuration || d > liveLocationMaxDuration {
+		return nil, errs.ErrArgs.WrapMsg("invalid live location duration", "duration", duration)
+	}
+	if err := checkCoordinate(longitude, latitude); err != nil {
+		return nil, err
+	}
+	conversation, err := l.conv.db.GetConversation(ctx, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	location := &LiveLocation{
+		ConversationID: conversationID,
+		UserID:         l.conv.loginUserID,
+		SessionID:      utils.OperationIDGenerator(),
+		Longitude:      longitude,
+		Latitude:       latitude,
+		Accuracy:       accuracy,
+		StartTime:      now.UnixMilli(),
+		UpdateTime:     now.UnixMilli(),
+		ExpireTime:     now.Add(d).UnixMilli(),
+	}
+	if err := l.sendMsg(ctx, conversation, location, false); err != nil {
+		return nil, err
+	}
+	session := &liveLocationSession{conversation: conversation, location: location, lastSendTime: now}
+	ctx = context.WithoutCancel(ctx)
+	session.timer = time.AfterFunc(liveLocationHeartbeat, func() { l.resend(ctx, conversationID, session) })
+	l.lock.Lock()
+	if old, ok := l.sessions[conversationID]; ok {
+		old.timer.Stop()
+	}
+	l.sessions[conversationID] = session
+	l.lock.Unlock()
+	l.positions.Set(l.getKey(conversationID, location.UserID), location, d)
+	l.changes(conversationID)
+	return location, nil
+}
+
+// UpdateLiveLocation records the new position, it is sent at most once per liveLocationMinInterval,
+// a position coming sooner is shown locally and sent when the interval has passed.
+func (l *liveLocation) UpdateLiveLocation(ctx context.Context, conversationID string, longitude, latitude, accuracy float64) error {
+	if err := checkCoordinate(longitude, latitude); err != nil {
+		return err
+	}
+	now := time.Now()
+	l.lock.Lock()
+	session, ok := l.sessions[conversationID]
+	if !ok || session.location.ExpireTime <= now.UnixMilli() {
+		l.lock.Unlock()
+		return errs.ErrArgs.WrapMsg("live location is not shared in the conversation", "conversationID", conversationID)
+	}
+	location := *session.location
+	location.Longitude, location.Latitude, location.Accuracy, location.UpdateTime = longitude, latitude, accuracy, now.UnixMilli()
+	session.location = &location
+	send := now.Sub(session.lastSendTime) >= liveLocationMinInterval
+	if send {
+		session.lastSendTime = now
+		session.timer.Reset(liveLocationHeartbeat)
+	} else {
+		session.timer.Reset(liveLocationMinInterval - now.Sub(session.lastSendTime))
+	}
+	l.lock.Unlock()
+	l.positions.Set(l.getKey(conversationID, location.UserID), &location, time.UnixMilli(location.ExpireTime).Sub(now))
+	l.changes(conversationID)
+	if !send {
+		log.ZDebug(ctx, "live location update delayed", "conversationID", conversationID)
+		return nil
+	}
+	return l.sendMsg(ctx, session.conversation, &location, false)
+}
+
+func (l *liveLocation) StopLiveLocation(ctx context.Context, conversationID string) error {
+	l.lock.Lock()
+	session, ok := l.sessions[conversationID]
+	delete(l.sessions, conversationID)
+	l.lock.Unlock()
+	if !ok {
+		return nil
+	}
+	session.timer.Stop()
+	l.positions.Delete(l.getKey(conversationID, l.conv.loginUserID))
+	return l.sendMsg(ctx, session.conversation, session.location, true)
+}
+
+// stopAll stops the sessions of the login user, on logout.
+func (l *liveLocation) stopAll(ctx context.Context) {
+	l.lock.Lock()
+	conversationIDs := datautil.Keys(l.sessions)
+	l.lock.Unlock()
+	for _, conversationID := range conversationIDs {
+		if err := l.StopLiveLocation(ctx, conversationID); err != nil {
+			log.ZWarn(ctx, "stop live location failed", err, "conversationID", conversationID)
+		}
+	}
+}
+
+// resend sends the last position of the session, late when it was throttled or again when it has
+// not changed, so that the peers do not drop it as stale.
+func (l *liveLocation) resend(ctx context.Context, conversationID string, session *liveLocationSession) {
+	now := time.Now()
+	l.lock.Lock()
+	if l.sessions[conversationID] != session || session.location.ExpireTime <= now.UnixMilli() {
+		l.lock.Unlock()
+		return
+	}
+	location := session.location
+	session.lastSendTime = now
+	session.timer.Reset(liveLocationHeartbeat)
+	l.lock.Unlock()
+	if err := l.sendMsg(ctx, session.conversation, location, false); err != nil {
+		log.ZWarn(ctx, "resend live location failed", err, "conversationID", conversationID)
+	}
+}
+
+func (l *liveLocation) sendMsg(ctx context.Context, conversation *model_struct.LocalConversation, location *LiveLocation, stopped bool) error {
+	ctx, cancel := context.WithTimeout(ctx, liveLocationMsgTimeout)
+	defer cancel()
+	s := sdk_struct.MsgStruct{}
+	err := l.conv.initBasicInfo(ctx, &s, constant.UserMsgType, constant.LiveLocation)
+	if err != nil {
+		return err
+	}
+	s.RecvID = conversation.UserID
+	s.GroupID = conversation.GroupID
+	s.SessionType = conversation.ConversationType
+	s.Content = utils.StructToJsonString(sdk_struct.LiveLocationElem{
+		SessionID:  location.SessionID,
+		Longitude:  location.Longitude,
+		Latitude:   location.Latitude,
+		Accuracy:   location.Accuracy,
+		ExpireTime: location.ExpireTime,
+		Stopped:    stopped,
+	})
+	options := make(map[string]bool, 7)
+	utils.SetSwitchFromOptions(options, constant.IsHistory, false)
+	utils.SetSwitchFromOptions(options, constant.IsPersistent, false)
+	utils.SetSwitchFromOptions(options, constant.IsSenderSync, false)
+	utils.SetSwitchFromOptions(options, constant.IsConversationUpdate, false)
+	utils.SetSwitchFromOptions(options, constant.IsSenderConversationUpdate, false)
+	utils.SetSwitchFromOptions(options, constant.IsUnreadCount, false)
+	utils.SetSwitchFromOptions(options, constant.IsOfflinePush, false)
+	var wsMsgData sdkws.MsgData
+	copier.Copy(&wsMsgData, s)
+	wsMsgData.Content = []byte(s.Content)
+	wsMsgData.CreateTime = s.CreateTime
+	wsMsgData.Options = options
+	var sendMsgResp sdkws.UserSendMsgResp
+	err = l.conv.LongConnMgr.SendReqWaitResp(ctx, &wsMsgData, constant.SendMsg, &sendMsgResp)
+	if err != nil {
+		log.ZError(ctx, "live location msg to server failed", err, "message", s)
+		return err
+	}
+	return nil
+}
+
+func (l *liveLocation) onNewMsg(ctx context.Context, msg *sdkws.MsgData) {
+	var elem sdk_struct.LiveLocationElem
+	if err := json.Unmarshal(msg.Content, &elem); err != nil {
+		log.ZError(ctx, "live location onNewMsg Unmarshal failed", err, "message", msg)
+		return
+	}
+	if msg.SendID == l.conv.loginUserID {
+		return
+	}
+	var sourceID string
+	if msg.GroupID == "" {
+		sourceID = msg.SendID
+	} else {
+		sourceID = msg.GroupID
+	}
+	conversationID := l.conv.getConversationIDBySessionType(sourceID, int(msg.SessionType))
+	key := l.getKey(conversationID, msg.SendID)
+	now := time.Now().UnixMilli()
+	if elem.Stopped || elem.ExpireTime <= now {
+		if v, ok := l.positions.Get(key); ok && v.(*LiveLocation).SessionID == elem.SessionID {
+			l.positions.Delete(key)
+		}
+		return
+	}
+	location := &LiveLocation{
+		ConversationID: conversationID,
+		UserID:         msg.SendID,
+		SessionID:      elem.SessionID,
+		Longitude:      elem.Longitude,
+		Latitude:       elem.Latitude,
+		Accuracy:       elem.Accuracy,
+		StartTime:      msg.SendTime,
+		UpdateTime:     msg.SendTime,
+		ExpireTime:     elem.ExpireTime,
+	}
+	if v, ok := l.positions.Get(key); ok {
+		last := v.(*LiveLocation)
+		if last.SessionID == elem.SessionID {
+			if last.UpdateTime >= msg.SendTime {
+				return
+			}
+			location.StartTime = last.StartTime
+		}
+	}
+	d := min(time.Duration(elem.ExpireTime-now)*time.Millisecond, liveLocationStaleTimeout)
+	l.positions.Set(key, location, d)
+	l.changes(conversationID)
+}
+
+func (l *liveLocation) changes(conversationID string) {
+	data := LiveLocationChangedData{ConversationID: conversationID, LiveLocationList: l.GetLiveLocations(conversationID)}
+	l.conv.ConversationListener().OnConversationLiveLocationChanged(utils.StructToJsonString(data))
+}
+
+// GetLiveLocations returns the positions shared in the conversation, in the order the sharing started.
+func (l *liveLocation) GetLiveLocations(conversationID string) []*LiveLocation {
+	locations := make([]*LiveLocation, 0)
+	for k, item := range l.positions.Items() {
+		var key liveLocationKey
+		if err := json.Unmarshal([]byte(k), &key); err != nil || key.ConversationID != conversationID {
+			continue
+		}
+		locations = append(locations, item.Object.(*LiveLocation))
+	}
+	sort.Slice(locations, func(i, j int) bool {
+		return locations[i].StartTime < locations[j].StartTime
+	})
+	return locations
+}
@@ -0,0 +1,135 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversation_msg
+
+import (
+	"context"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/common"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	userPb "github.com/openimsdk/protocol/user"
+	"github.com/openimsdk/tools/log"
+	"github.com/openimsdk/tools/utils/datautil"
+)
+
+// unreadCountOf is the unread count of the conversation, a conversation marked as unread
+// without unread messages counts as one.
+func unreadCountOf(conversation *model_struct.LocalConversation) int32 {
+	if conversation.UnreadCount == 0 && conversation.IsMarkedUnread {
+		return 1
+	}
+	return conversation.UnreadCount
+}
+
+// markConversationAsUnread flags the conversation as unread. The flag is kept as a user command,
+// so it follows the user to the other devices.
+func (c *Conversation) markConversationAsUnread(ctx context.Context, conversationID string) error {
+	lc, err := c.db.GetConversation(ctx, conversationID)
+	if err != nil {
+		return err
+	}
+	if lc.IsMarkedUnread {
+		return nil
+	}
+	if err := c.user.ProcessUserCommandAdd(ctx, &userPb.ProcessUserCommandAddReq{Type: constant.MarkedUnreadUserCommandType,
+		Uuid: conversationID}); err != nil {
+		return err
+	}
+	_, err = c.applyMarkedUnread(ctx, conversationID, true)
+	return err
+}
+
+// applyMarkedUnread stores the flag locally, the command sync may have done it already.
+func (c *Conversation) applyMarkedUnread(ctx context.Context, conversationID string, marked bool) (bool, error) {
+	lc, err := c.db.GetConversation(ctx, conversationID)
+	if err != nil {
+		return false, err
+	}
+	if lc.IsMarkedUnread == marked {
+		return false, nil
+	}
+	if err := c.db.UpdateColumnsConversation(ctx, conversationID, map[string]any{"is_marked_unread": marked}); err != nil {
+		return false, err
+	}
+	c.markedUnreadChanged(ctx, conversationID)
+	return true, nil
+}
+
+// clearConversationMarkedUnread drops the flag once the conversation is read.
+func (c *Conversation) clearConversationMarkedUnread(ctx context.Context, conversationID string) (bool, error) {
+	lc, err := c.db.GetConversation(ctx, conversationID)
+	if err != nil {
+		return false, err
+	}
+	if !lc.IsMarkedUnread {
+		return false, nil
+	}
+	if err := c.user.ProcessUserCommandDelete(ctx, &userPb.ProcessUserCommandDeleteReq{Type: constant.MarkedUnreadUserCommandType,
+		Uuid: conversationID}); err != nil {
+		return false, err
+	}
+	return c.applyMarkedUnread(ctx, conversationID, false)
+}
+
+// markConversationAsReadAndClearFlag reads the conversation and drops the unread flag. A conversation
+// only marked as unread has nothing to read on the server, which is not an error here.
+func (c *Conversation) markConversationAsReadAndClearFlag(ctx context.Context, conversationID string) error {
+	readErr := c.markConversationMessageAsRead(ctx, conversationID)
+	if readErr != nil && !sdkerrs.ErrUnreadCount.Is(readErr) {
+		return readErr
+	}
+	cleared, err := c.clearConversationMarkedUnread(ctx, conversationID)
+	if err != nil {
+		log.ZWarn(ctx, "clear conversation marked unread failed", err, "conversationID", conversationID)
+		return readErr
+	}
+	if cleared {
+		return nil
+	}
+	return readErr
+}
+
+// markedUnreadSynced applies the unread flags synced from the other devices.
+func (c *Conversation) markedUnreadSynced(ctx context.Context) {
+	commands, err := c.db.ProcessUserCommandGetAll(ctx)
+	if err != nil {
+		log.ZWarn(ctx, "get user commands failed", err)
+		return
+	}
+	var marked []string
+	for _, command := range commands {
+		if command.Type == constant.MarkedUnreadUserCommandType {
+			marked = append(marked, command.Uuid)
+		}
+	}
+	localMarked, err := c.db.GetMarkedUnreadConversationIDList(ctx)
+	if err != nil {
+		log.ZWarn(ctx, "get marked unread conversations failed", err)
+		return
+	}
+	for _, conversationID := range datautil.Single(marked, localMarked) {
+		_, err := c.applyMarkedUnread(ctx, conversationID, datautil.Contain(conversationID, marked...))
+		if err != nil {
+			log.ZWarn(ctx, "apply marked unread failed", err, "conversationID", conversationID)
+		}
+	}
+}
+
+func (c *Conversation) markedUnreadChanged(ctx context.Context, conversationID string) {
+	c.doUpdateConversation(common.Cmd2Value{Ctx: ctx, Value: common.UpdateConNode{Action: constant.TotalUnreadMessageChanged}})
+	c.doUpdateConversation(common.Cmd2Value{Ctx: ctx, Value: common.UpdateConNode{ConID: conversationID, Action: constant.ConChange, Args: []string{conversationID}}})
+}
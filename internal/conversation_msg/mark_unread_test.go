@@ -0,0 +1,114 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !js
+
+package conversation_msg
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+)
+
+func TestMarkedUnreadCount(t *testing.T) {
+	ctx := context.Background()
+	c, listener := newTestConversation(t, "u1")
+	conversationID := "si_u1_u2"
+	if err := c.db.InsertConversation(ctx, &model_struct.LocalConversation{ConversationID: conversationID,
+		ConversationType: constant.SingleChatType, UserID: "u2", LatestMsgSendTime: 1}); err != nil {
+		t.Fatal(err)
+	}
+	total := func(want int32) {
+		t.Helper()
+		count, err := c.db.GetTotalUnreadMsgCountDB(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != want {
+			t.Fatalf("total unread count %d, want %d", count, want)
+		}
+	}
+
+	changed, err := c.applyMarkedUnread(ctx, conversationID, true)
+	if err != nil || !changed {
+		t.Fatalf("mark unread changed %v, err %v", changed, err)
+	}
+	total(1)
+	if got := listener.get("OnTotalUnreadMessageCountChanged"); len(got) != 1 || got[0] != "1" {
+		t.Fatalf("total unread callbacks %v", got)
+	}
+	if changed, _ := c.applyMarkedUnread(ctx, conversationID, true); changed {
+		t.Fatal("marking a marked conversation again changed it")
+	}
+
+	// real messages replace the mark instead of adding to it
+	if err := c.db.UpdateColumnsConversation(ctx, conversationID, map[string]any{"unread_count": 3}); err != nil {
+		t.Fatal(err)
+	}
+	total(3)
+	if err := c.db.UpdateColumnsConversation(ctx, conversationID, map[string]any{"unread_count": 0}); err != nil {
+		t.Fatal(err)
+	}
+	total(1)
+	lc, err := c.db.GetConversation(ctx, conversationID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lc.UnreadCount != 0 || !lc.IsMarkedUnread || unreadCountOf(lc) != 1 {
+		t.Fatalf("conversation unread count %d, marked %v", lc.UnreadCount, lc.IsMarkedUnread)
+	}
+
+	if _, err := c.applyMarkedUnread(ctx, conversationID, false); err != nil {
+		t.Fatal(err)
+	}
+	total(0)
+}
+
+func TestMarkedUnreadSynced(t *testing.T) {
+	ctx := context.Background()
+	c, _ := newTestConversation(t, "u1")
+	for _, conversationID := range []string{"si_u1_u2", "si_u1_u3"} {
+		if err := c.db.InsertConversation(ctx, &model_struct.LocalConversation{ConversationID: conversationID,
+			ConversationType: constant.SingleChatType, LatestMsgSendTime: 1}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := c.applyMarkedUnread(ctx, "si_u1_u3", true); err != nil {
+		t.Fatal(err)
+	}
+	command := &model_struct.LocalUserCommand{UserID: "u1", Type: constant.MarkedUnreadUserCommandType, Uuid: "si_u1_u2"}
+	if err := c.db.ProcessUserCommandAdd(ctx, command); err != nil {
+		t.Fatal(err)
+	}
+	// marked on another device, and the local mark was cleared on another device
+	c.markedUnreadSynced(ctx)
+	marked, err := c.db.GetMarkedUnreadConversationIDList(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(marked) != 1 || marked[0] != "si_u1_u2" {
+		t.Fatalf("marked conversations %v", marked)
+	}
+
+	if err := c.db.ProcessUserCommandDelete(ctx, command); err != nil {
+		t.Fatal(err)
+	}
+	c.markedUnreadSynced(ctx)
+	if marked, _ := c.db.GetMarkedUnreadConversationIDList(ctx); len(marked) != 0 {
+		t.Fatalf("marked conversations %v after the command was deleted", marked)
+	}
+}
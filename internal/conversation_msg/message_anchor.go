@@ -0,0 +1,131 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversation_msg
+
+import (
+	"context"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	sdk "github.com/openimsdk/openim-sdk-core/v3/pkg/sdk_params_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+	"github.com/openimsdk/tools/log"
+)
+
+// getMessageListAroundAnchor returns the anchor message together with up to BeforeCount older and
+// AfterCount newer messages, pulling the missing seqs from the server on both sides.
+func (c *Conversation) getMessageListAroundAnchor(ctx context.Context, req sdk.GetMessageListAroundAnchorParams) (*sdk.GetMessageListAroundAnchorCallback, error) {
+	if req.ConversationID == "" || (req.ClientMsgID == "" && req.Seq <= 0) {
+		return nil, sdkerrs.ErrArgs.WrapMsg("conversationID and clientMsgID or seq are required")
+	}
+	if req.BeforeCount < 0 || req.AfterCount < 0 {
+		return nil, sdkerrs.ErrArgs.WrapMsg("count can't be negative", "beforeCount", req.BeforeCount, "afterCount", req.AfterCount)
+	}
+	if _, err := c.db.GetConversation(ctx, req.ConversationID); err != nil {
+		return nil, err
+	}
+	anchor, err := c.getAnchorMessage(ctx, req.ConversationID, req.ClientMsgID, req.Seq)
+	if err != nil {
+		return nil, err
+	}
+	result := &sdk.GetMessageListAroundAnchorCallback{AnchorIndex: -1, LastMinSeq: anchor.Seq}
+	var before []*sdk_struct.MsgStruct
+	if req.BeforeCount > 0 {
+		// LastMinSeq makes the older page check its continuity with the anchor.
+		older, err := c.getAdvancedHistoryMessageList(ctx, sdk.GetAdvancedHistoryMessageListParams{
+			ConversationID:   req.ConversationID,
+			StartClientMsgID: anchor.ClientMsgID,
+			LastMinSeq:       anchor.Seq,
+			Count:            req.BeforeCount,
+		}, false)
+		if err != nil {
+			return nil, err
+		}
+		before = older.MessageList
+		result.LastMinSeq = older.LastMinSeq
+		result.IsStart = older.IsEnd
+		result.ErrCode, result.ErrMsg = older.ErrCode, older.ErrMsg
+	} else {
+		result.IsStart = anchor.Seq == 1
+	}
+	var after []*model_struct.LocalChatLog
+	if req.AfterCount > 0 {
+		var newer sdk.GetAdvancedHistoryMessageListCallback
+		after, err = c.getNewerMessageList(ctx, req.ConversationID, anchor, req.AfterCount, &newer)
+		if err != nil {
+			return nil, err
+		}
+		// Newer messages are kept in sync by the message syncer, so a short page means the latest one is reached.
+		result.IsEnd = len(after) < req.AfterCount
+		if newer.ErrCode != 0 {
+			result.ErrCode, result.ErrMsg = newer.ErrCode, newer.ErrMsg
+		}
+	}
+	messageList := make([]*sdk_struct.MsgStruct, 0, len(before)+len(after)+1)
+	messageList = append(messageList, before...)
+	if anchorMsg := c.historyMessageListOf(ctx, []*model_struct.LocalChatLog{anchor}); len(anchorMsg) > 0 {
+		result.AnchorIndex = len(messageList)
+		messageList = append(messageList, anchorMsg...)
+	}
+	messageList = append(messageList, c.historyMessageListOf(ctx, after)...)
+	c.attachLinkPreviews(ctx, messageList[len(before):])
+	result.MessageList = messageList
+	result.StartClientMsgID, result.EndClientMsgID = anchor.ClientMsgID, anchor.ClientMsgID
+	if len(messageList) > 0 {
+		result.StartClientMsgID = messageList[0].ClientMsgID
+		result.EndClientMsgID = messageList[len(messageList)-1].ClientMsgID
+	}
+	return result, nil
+}
+
+// getAnchorMessage loads the anchor locally, pulling it from the server first when only its seq is known.
+func (c *Conversation) getAnchorMessage(ctx context.Context, conversationID, clientMsgID string, seq int64) (*model_struct.LocalChatLog, error) {
+	if clientMsgID != "" {
+		return c.db.GetMessage(ctx, conversationID, clientMsgID)
+	}
+	if anchor, err := c.db.GetMessageBySeq(ctx, conversationID, seq); err == nil {
+		return anchor, nil
+	}
+	if err := c.pullMessagesBySeqs(ctx, conversationID, []int64{seq}); err != nil {
+		return nil, err
+	}
+	return c.db.GetMessageBySeq(ctx, conversationID, seq)
+}
+
+// getNewerMessageList returns up to count messages sent after the anchor in ascending order,
+// filling the gaps inside the page and between the anchor and the page from the server.
+func (c *Conversation) getNewerMessageList(ctx context.Context, conversationID string, anchor *model_struct.LocalChatLog, count int,
+	messageListCallback *sdk.GetAdvancedHistoryMessageListCallback) ([]*model_struct.LocalChatLog, error) {
+	list, err := c.db.GetMessageList(ctx, conversationID, count, anchor.SendTime, true)
+	if err != nil {
+		return nil, err
+	}
+	c.messageBlocksInternalContinuityCheck(ctx, conversationID, false, true, count, anchor.SendTime, &list, messageListCallback)
+	if anchor.Seq == 0 {
+		return list, nil
+	}
+	_, minSeq, _ := c.getMaxAndMinHaveSeqList(list)
+	if minSeq > anchor.Seq+1 {
+		endSeq := min(minSeq-1, anchor.Seq+constant.PullMsgNumForReadDiffusion)
+		seqList := make([]int64, 0, endSeq-anchor.Seq)
+		for seq := anchor.Seq + 1; seq <= endSeq; seq++ {
+			seqList = append(seqList, seq)
+		}
+		log.ZDebug(ctx, "getNewerMessageList", "conversationID", conversationID, "anchorSeq", anchor.Seq, "seqList", seqList)
+		c.pullMessageAndReGetHistoryMessages(ctx, conversationID, seqList, false, true, count, anchor.SendTime, &list, messageListCallback)
+	}
+	return list, nil
+}
@@ -0,0 +1,75 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !js
+
+package conversation_msg
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	sdk "github.com/openimsdk/openim-sdk-core/v3/pkg/sdk_params_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+func TestHistoryMessageList(t *testing.T) {
+	ctx := context.Background()
+	c, _ := newTestConversation(t, "u1")
+	if err := c.db.InsertConversation(ctx, &model_struct.LocalConversation{ConversationID: "sg_g1",
+		ConversationType: constant.ReadGroupChatType, GroupID: "g1", LatestMsgSendTime: 1}); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now().UnixMilli()
+	burn := utils.StructToJsonString(sdk_struct.AttachedInfoElem{IsPrivateChat: true, BurnDuration: 60})
+	var messages []*model_struct.LocalChatLog
+	for i, sendTime := range []int64{now - 5000, now - 4000, now - 3000, now - 2000} {
+		msg := newTestTextMessage("sg_g1", fmt.Sprintf("m%d", i+1), "u2", int64(i+1), sendTime)
+		msg.RecvID, msg.SessionType = "g1", constant.ReadGroupChatType
+		messages = append(messages, msg)
+	}
+	// private chat messages are not dropped for their send time alone
+	messages[0].SendTime, messages[0].AttachedInfo = now-120*1000, burn
+	messages[2].AttachedInfo = burn
+	messages[3].Status = constant.MsgStatusHasDeleted
+	if err := c.db.BatchInsertMessageList(ctx, "sg_g1", messages); err != nil {
+		t.Fatal(err)
+	}
+
+	ids := func(list []*sdk_struct.MsgStruct) string {
+		var res []string
+		for _, msg := range list {
+			if msg.GroupID != "g1" || msg.RecvID != "u1" {
+				t.Fatalf("message %s group %s recv %s", msg.ClientMsgID, msg.GroupID, msg.RecvID)
+			}
+			res = append(res, msg.ClientMsgID)
+		}
+		return fmt.Sprint(res)
+	}
+	if got := ids(c.historyMessageListOf(ctx, messages)); got != "[m1 m2 m3]" {
+		t.Fatalf("history messages %s", got)
+	}
+	res, err := c.getAdvancedHistoryMessageList(ctx, sdk.GetAdvancedHistoryMessageListParams{ConversationID: "sg_g1", Count: 10}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ids(res.MessageList); got != "[m1 m2 m3]" || !res.IsEnd {
+		t.Fatalf("advanced history messages %s, end %v", got, res.IsEnd)
+	}
+}
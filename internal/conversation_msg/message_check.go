@@ -218,6 +218,31 @@ func errHandle(seqList []int64, list *[]*model_struct.LocalChatLog, err error, m
 	}
 	*list = result
 }
+
+// pullMessagesBySeqs pulls the messages of the given seqs that are missing locally and stores them.
+func (c *Conversation) pullMessagesBySeqs(ctx context.Context, conversationID string, seqs []int64) error {
+	existedSeqList, err := c.db.GetAlreadyExistSeqList(ctx, conversationID, seqs)
+	if err != nil {
+		return err
+	}
+	newSeqList := utils.DifferenceSubset(seqs, existedSeqList)
+	if len(newSeqList) == 0 {
+		return nil
+	}
+	var getSeqMessageResp msg.GetSeqMessageResp
+	getSeqMessageReq := msg.GetSeqMessageReq{
+		UserID:        c.loginUserID,
+		Conversations: []*msg.ConversationSeqs{{ConversationID: conversationID, Seqs: newSeqList}},
+	}
+	if err := c.SendReqWaitResp(ctx, &getSeqMessageReq, constant.PullMsgBySeqList, &getSeqMessageResp); err != nil {
+		return err
+	}
+	if len(getSeqMessageResp.Msgs) > 0 {
+		c.pullMessageIntoTable(ctx, getSeqMessageResp.Msgs)
+	}
+	return nil
+}
+
 func (c *Conversation) pullMessageIntoTable(ctx context.Context, pullMsgData map[string]*sdkws.PullMsgs) {
 	insertMsg := make(map[string][]*model_struct.LocalChatLog, 20)
 	updateMsg := make(map[string][]*model_struct.LocalChatLog, 30)
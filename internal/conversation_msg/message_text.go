@@ -0,0 +1,127 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversation_msg
+
+import (
+	"unicode/utf8"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+// messageText is the plain text form of a message, used wherever a message is shown or matched as text.
+type messageText struct {
+	Sender string
+	Text   string
+	Image  string
+	Link   string
+}
+
+// messageTextOf renders a message as text. media maps the urls of the media, nil keeps them.
+func messageTextOf(msg *sdk_struct.MsgStruct, media func(u string) string) messageText {
+	view := messageText{Sender: msg.SenderNickname}
+	if view.Sender == "" {
+		view.Sender = msg.SendID
+	}
+	if media == nil {
+		media = func(u string) string { return u }
+	}
+	switch msg.ContentType {
+	case constant.Text:
+		if msg.TextElem != nil {
+			view.Text = msg.TextElem.Content
+		}
+	case constant.AtText:
+		if msg.AtTextElem != nil {
+			view.Text = msg.AtTextElem.Text
+		}
+	case constant.Quote:
+		if msg.QuoteElem != nil {
+			view.Text = msg.QuoteElem.Text
+		}
+	case constant.AdvancedText:
+		if msg.AdvancedTextElem != nil {
+			view.Text = msg.AdvancedTextElem.Text
+		}
+	case constant.Picture:
+		if msg.PictureElem != nil && msg.PictureElem.SourcePicture != nil {
+			view.Image = media(msg.PictureElem.SourcePicture.Url)
+		}
+	case constant.Sound:
+		view.Text = "[Voice]"
+		if msg.SoundElem != nil && msg.SoundElem.SourceURL != "" {
+			view.Link = media(msg.SoundElem.SourceURL)
+		}
+	case constant.Video:
+		view.Text = "[Video]"
+		if msg.VideoElem != nil {
+			if msg.VideoElem.SnapshotURL != "" {
+				view.Image = media(msg.VideoElem.SnapshotURL)
+			}
+			if msg.VideoElem.VideoURL != "" {
+				view.Link = media(msg.VideoElem.VideoURL)
+			}
+		}
+	case constant.File:
+		view.Text = "[File]"
+		if msg.FileElem != nil {
+			view.Text = "[File] " + msg.FileElem.FileName
+			if msg.FileElem.SourceURL != "" {
+				view.Link = media(msg.FileElem.SourceURL)
+			}
+		}
+	case constant.Location:
+		view.Text = "[Location]"
+		if msg.LocationElem != nil && msg.LocationElem.Description != "" {
+			view.Text = "[Location] " + msg.LocationElem.Description
+		}
+	case constant.Card:
+		view.Text = "[Card]"
+		if msg.CardElem != nil {
+			view.Text = "[Card] " + msg.CardElem.Nickname
+		}
+	case constant.Merger:
+		view.Text = "[Chat history]"
+		if msg.MergeElem != nil && msg.MergeElem.Title != "" {
+			view.Text = "[Chat history] " + msg.MergeElem.Title
+		}
+	case constant.Face:
+		view.Text = "[Sticker]"
+	case constant.Poll:
+		view.Text = "[Poll]"
+		if msg.PollElem != nil {
+			view.Text = "[Poll] " + msg.PollElem.Question
+		}
+	case constant.Custom:
+		view.Text = "[Custom message]"
+		if msg.CustomElem != nil && msg.CustomElem.Description != "" {
+			view.Text = msg.CustomElem.Description
+		}
+	default:
+		view.Text = "[Notification]"
+		if msg.NotificationElem != nil && msg.NotificationElem.Detail != "" {
+			view.Text = msg.NotificationElem.Detail
+		}
+	}
+	return view
+}
+
+// truncateText keeps the first maxLength characters of text, marking the cut with an ellipsis.
+func truncateText(text string, maxLength int) string {
+	if utf8.RuneCountInString(text) <= maxLength {
+		return text
+	}
+	return string([]rune(text)[:maxLength]) + "…"
+}
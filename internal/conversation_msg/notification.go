@@ -62,6 +62,18 @@ func (c *Conversation) Work(c2v common.Cmd2Value) {
 		c.syncFlag(c2v)
 	case constant.CmdMsgSyncInReinstall:
 		c.doMsgSyncByReinstalled(c2v)
+	case constant.CmdSensitiveWordsChanged:
+		c.sensitiveWordsChanged(c2v.Ctx)
+	case constant.CmdConversationFoldersChanged:
+		c.conversationFoldersChanged(c2v.Ctx)
+	case constant.CmdConversationTimerExpired:
+		c.expireConversationTimers(c2v.Ctx)
+	case constant.CmdConversationDraftsChanged:
+		c.conversationDraftsChanged(c2v.Ctx)
+	case constant.CmdPinnedMessagesChanged:
+		c.pinnedMessagesSynced(c2v.Ctx)
+	case constant.CmdMarkedUnreadChanged:
+		c.markedUnreadSynced(c2v.Ctx)
 	}
 }
 
@@ -126,9 +138,12 @@ func (c *Conversation) doNotificationManager(c2v common.Cmd2Value) {
 	for conversationID, msgs := range allMsg {
 		log.ZDebug(ctx, "notification handling", "conversationID", conversationID, "msgs", msgs)
 
-		// First, process all the notifications
+		// First, process all the notifications, the revokes of a conversation are applied together
+		var revokeMsgs []*sdkws.MsgData
 		for _, msg := range msgs.Msgs {
-			if msg.ContentType > constant.FriendNotificationBegin && msg.ContentType < constant.FriendNotificationEnd {
+			if msg.ContentType == constant.RevokeNotification {
+				revokeMsgs = append(revokeMsgs, msg)
+			} else if msg.ContentType > constant.FriendNotificationBegin && msg.ContentType < constant.FriendNotificationEnd {
 				c.relation.DoNotification(ctx, msg)
 			} else if msg.ContentType > constant.UserNotificationBegin && msg.ContentType < constant.UserNotificationEnd {
 				c.user.DoNotification(ctx, msg)
@@ -138,6 +153,13 @@ func (c *Conversation) doNotificationManager(c2v common.Cmd2Value) {
 				c.DoNotification(ctx, msg)
 			}
 		}
+		if len(revokeMsgs) > 0 {
+			go func(conversationID string, msgs []*sdkws.MsgData) {
+				if err := c.doRevokeMsgs(ctx, conversationID, msgs); err != nil {
+					log.ZWarn(ctx, "doRevokeMsgs failed", err, "conversationID", conversationID)
+				}
+			}(conversationID, revokeMsgs)
+		}
 
 		// After all notifications are processed, update the sequence number
 		if len(msgs.Msgs) != 0 {
@@ -154,6 +176,26 @@ func (c *Conversation) doNotificationManager(c2v common.Cmd2Value) {
 
 }
 
+// doConversationMsgNotification applies the notifications that are sent as messages inside a
+// conversation, after the messages have been stored locally.
+func (c *Conversation) doConversationMsgNotification(ctx context.Context, allMsg map[string]*sdkws.PullMsgs) {
+	for conversationID, msgs := range allMsg {
+		for _, msg := range msgs.Msgs {
+			if msg.Status == constant.MsgStatusHasDeleted {
+				continue
+			}
+			switch msg.ContentType {
+			case constant.PinnedMessageNotification:
+				c.doPinnedMessageNotification(ctx, conversationID, msg)
+			case constant.PollVoteNotification:
+				c.doPollVoteNotification(ctx, conversationID, msg)
+			case constant.PollResultNotification:
+				c.doPollResultNotification(ctx, conversationID, msg)
+			}
+		}
+	}
+}
+
 func (c *Conversation) DoNotification(ctx context.Context, msg *sdkws.MsgData) {
 	go func() {
 		if err := c.doNotification(ctx, msg); err != nil {
@@ -209,7 +251,9 @@ func (c *Conversation) doUpdateConversation(c2v common.Cmd2Value) {
 					oc.LatestMsgSendTime = lc.LatestMsgSendTime
 					oc.LatestMsg = lc.LatestMsg
 					list = append(list, oc)
-					c.ConversationListener().OnConversationChanged(utils.StructToJsonString(list))
+					if !c.delta.add(list, false) {
+						c.ConversationListener().OnConversationChanged(utils.StructToJsonString(list))
+					}
 				}
 			}
 		} else {
@@ -219,7 +263,9 @@ func (c *Conversation) doUpdateConversation(c2v common.Cmd2Value) {
 				log.ZWarn(ctx, "insert new conversation err", err4)
 			} else {
 				list = append(list, &lc)
-				c.ConversationListener().OnNewConversation(utils.StructToJsonString(list))
+				if !c.delta.add(list, true) {
+					c.ConversationListener().OnNewConversation(utils.StructToJsonString(list))
+				}
 			}
 		}
 
@@ -230,6 +276,7 @@ func (c *Conversation) doUpdateConversation(c2v common.Cmd2Value) {
 		} else {
 			c.ConversationListener().OnTotalUnreadMessageCountChanged(totalUnreadCount)
 		}
+		c.unreadCountsChanged(ctx)
 	case constant.UpdateConFaceUrlAndNickName:
 		var lc model_struct.LocalConversation
 		st := node.Args.(common.SourceIDAndSessionType)
@@ -295,8 +342,11 @@ func (c *Conversation) doUpdateConversation(c2v common.Cmd2Value) {
 					newCList = append(newCList, v)
 				}
 			}
-			c.ConversationListener().OnConversationChanged(utils.StructToJsonStringDefault(newCList))
+			if !c.delta.add(newCList, false) {
+				c.ConversationListener().OnConversationChanged(utils.StructToJsonStringDefault(newCList))
+			}
 		}
+		c.unreadCountsChanged(ctx)
 	case constant.NewCon:
 		cidList := node.Args.([]string)
 		cLists, err := c.db.GetMultipleConversationDB(ctx, cidList)
@@ -305,17 +355,23 @@ func (c *Conversation) doUpdateConversation(c2v common.Cmd2Value) {
 		} else {
 			if cLists != nil {
 				log.ZDebug(ctx, "getMultipleConversationModel success", "cLists", cLists)
-				c.ConversationListener().OnNewConversation(utils.StructToJsonString(cLists))
+				if !c.delta.add(cLists, true) {
+					c.ConversationListener().OnNewConversation(utils.StructToJsonString(cLists))
+				}
 			}
 		}
 	case constant.ConChangeDirect:
 		cidList := node.Args.(string)
-		c.ConversationListener().OnConversationChanged(cidList)
+		if !c.delta.addJSON(cidList, false) {
+			c.ConversationListener().OnConversationChanged(cidList)
+		}
 
 	case constant.NewConDirect:
 		cidList := node.Args.(string)
 		log.ZDebug(ctx, "NewConversation", "cidList", cidList)
-		c.ConversationListener().OnNewConversation(cidList)
+		if !c.delta.addJSON(cidList, true) {
+			c.ConversationListener().OnNewConversation(cidList)
+		}
 
 	case constant.ConversationLatestMsgHasRead:
 		hasReadMsgList := node.Args.(map[string][]string)
@@ -349,7 +405,9 @@ func (c *Conversation) doUpdateConversation(c2v common.Cmd2Value) {
 		}
 		if result != nil {
 			log.ZDebug(ctx, "getMultipleConversationModel success", "result", result)
-			c.ConversationListener().OnNewConversation(utils.StructToJsonString(result))
+			if !c.delta.add(result, false) {
+				c.ConversationListener().OnNewConversation(utils.StructToJsonString(result))
+			}
 		}
 	case constant.SyncConversation:
 
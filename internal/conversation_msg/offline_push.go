@@ -0,0 +1,229 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversation_msg
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+	"github.com/openimsdk/protocol/sdkws"
+)
+
+// offlinePushTextMaxLength is the number of characters of the message kept in the push description
+const offlinePushTextMaxLength = 100
+
+var defaultOfflinePushTemplate = sdk_struct.OfflinePushTemplate{
+	Title:      "{sender}",
+	GroupTitle: "{group}",
+	GroupDesc:  "{sender}: {desc}",
+	Desc: map[string]string{
+		constant.OfflinePushDescText:     "{text}",
+		constant.OfflinePushDescPicture:  "[Picture]",
+		constant.OfflinePushDescVoice:    "[Voice]",
+		constant.OfflinePushDescVideo:    "[Video]",
+		constant.OfflinePushDescFile:     "[File] {text}",
+		constant.OfflinePushDescLocation: "[Location] {text}",
+		constant.OfflinePushDescMention:  "[Mention] {text}",
+		constant.OfflinePushDescCard:     "[Contact Card] {text}",
+		constant.OfflinePushDescMerger:   "[Chat History] {text}",
+		constant.OfflinePushDescFace:     "[Sticker]",
+		constant.OfflinePushDescPoll:     "[Poll] {text}",
+		constant.OfflinePushDescCustom:   "[Custom Message]",
+		constant.OfflinePushDescDefault:  "[New Message]",
+		constant.OfflinePushDescHidden:   "You have a new message",
+	},
+}
+
+// CustomPushFormatter returns the push description of a custom message, an empty string leaves it
+// to the next formatter and finally to the custom template.
+type CustomPushFormatter func(ctx context.Context, msg *sdk_struct.MsgStruct) string
+
+type offlinePush struct {
+	enabled    bool
+	lock       sync.RWMutex
+	template   sdk_struct.OfflinePushTemplate
+	formatters []interceptor[CustomPushFormatter]
+}
+
+// resolveOfflinePushTemplate picks the template of the language, or of its base language, and
+// completes it with the built-in one.
+func resolveOfflinePushTemplate(language string, templates map[string]*sdk_struct.OfflinePushTemplate) sdk_struct.OfflinePushTemplate {
+	res := defaultOfflinePushTemplate
+	res.Desc = make(map[string]string, len(defaultOfflinePushTemplate.Desc))
+	for k, v := range defaultOfflinePushTemplate.Desc {
+		res.Desc[k] = v
+	}
+	template, ok := templates[language]
+	if !ok {
+		base, _, _ := strings.Cut(language, "-")
+		template, ok = templates[base]
+	}
+	if !ok || template == nil {
+		return res
+	}
+	if template.Title != "" {
+		res.Title = template.Title
+	}
+	if template.GroupTitle != "" {
+		res.GroupTitle = template.GroupTitle
+	}
+	if template.GroupDesc != "" {
+		res.GroupDesc = template.GroupDesc
+	}
+	for k, v := range template.Desc {
+		if v != "" {
+			res.Desc[k] = v
+		}
+	}
+	return res
+}
+
+func (c *Conversation) RegisterCustomPushFormatter(name string, fn CustomPushFormatter) {
+	c.offlinePush.lock.Lock()
+	defer c.offlinePush.lock.Unlock()
+	c.offlinePush.formatters = addInterceptor(c.offlinePush.formatters, name, fn)
+}
+
+func (c *Conversation) UnregisterCustomPushFormatter(name string) {
+	c.offlinePush.lock.Lock()
+	defer c.offlinePush.lock.Unlock()
+	c.offlinePush.formatters = removeInterceptor(c.offlinePush.formatters, name)
+}
+
+func truncatePushText(text string) string {
+	return truncateText(strings.TrimSpace(text), offlinePushTextMaxLength)
+}
+
+// offlinePushDesc returns the template key and the text of the message.
+func (c *Conversation) offlinePushDesc(ctx context.Context, s *sdk_struct.MsgStruct) (string, string) {
+	switch s.ContentType {
+	case constant.Text:
+		if s.TextElem != nil {
+			return constant.OfflinePushDescText, s.TextElem.Content
+		}
+	case constant.AdvancedText:
+		if s.AdvancedTextElem != nil {
+			return constant.OfflinePushDescText, s.AdvancedTextElem.Text
+		}
+	case constant.Quote:
+		if s.QuoteElem != nil {
+			return constant.OfflinePushDescText, s.QuoteElem.Text
+		}
+	case constant.AtText:
+		if s.AtTextElem != nil {
+			return constant.OfflinePushDescMention, s.AtTextElem.Text
+		}
+	case constant.Picture:
+		return constant.OfflinePushDescPicture, ""
+	case constant.Sound:
+		return constant.OfflinePushDescVoice, ""
+	case constant.Video:
+		return constant.OfflinePushDescVideo, ""
+	case constant.File:
+		if s.FileElem != nil {
+			return constant.OfflinePushDescFile, s.FileElem.FileName
+		}
+	case constant.Location:
+		if s.LocationElem != nil {
+			return constant.OfflinePushDescLocation, s.LocationElem.Description
+		}
+	case constant.Card:
+		if s.CardElem != nil {
+			return constant.OfflinePushDescCard, s.CardElem.Nickname
+		}
+	case constant.Merger:
+		if s.MergeElem != nil {
+			return constant.OfflinePushDescMerger, s.MergeElem.Title
+		}
+	case constant.Face:
+		return constant.OfflinePushDescFace, ""
+	case constant.Poll:
+		if s.PollElem != nil {
+			return constant.OfflinePushDescPoll, s.PollElem.Question
+		}
+	case constant.Custom:
+		c.offlinePush.lock.RLock()
+		formatters := c.offlinePush.formatters
+		c.offlinePush.lock.RUnlock()
+		for _, v := range formatters {
+			if desc := v.fn(ctx, s); desc != "" {
+				return "", desc
+			}
+		}
+		return constant.OfflinePushDescCustom, ""
+	}
+	return constant.OfflinePushDescDefault, ""
+}
+
+// fillOfflinePushInfo generates the title and the description of the offline push from the
+// localised template, unless the caller has set them or no template is configured. The content of
+// private chats and of encrypted messages is never put in the push.
+func (c *Conversation) fillOfflinePushInfo(ctx context.Context, s *sdk_struct.MsgStruct, lc *model_struct.LocalConversation,
+	p *sdkws.OfflinePushInfo) *sdkws.OfflinePushInfo {
+	if !c.offlinePush.enabled || (p != nil && (p.Title != "" || p.Desc != "")) {
+		return p
+	}
+	res := &sdkws.OfflinePushInfo{}
+	if p != nil {
+		res.Ex, res.IOSPushSound, res.IOSBadgeCount, res.SignalInfo = p.Ex, p.IOSPushSound, p.IOSBadgeCount, p.SignalInfo
+	}
+	c.offlinePush.lock.RLock()
+	template := c.offlinePush.template
+	c.offlinePush.lock.RUnlock()
+	var key, text string
+	hidden := lc.IsPrivateChat || (s.AttachedInfoElem != nil && (s.AttachedInfoElem.IsPrivateChat || s.AttachedInfoElem.IsEncryption))
+	if hidden {
+		key = constant.OfflinePushDescHidden
+	} else {
+		key, text = c.offlinePushDesc(ctx, s)
+	}
+	// the text of a custom formatter is the whole desc, it is truncated the same way
+	text = truncatePushText(text)
+	desc := text
+	if key != "" {
+		desc = template.Desc[key]
+	}
+	sender := s.SenderNickname
+	if sender == "" {
+		sender = s.SendID
+	}
+	isGroup := lc.ConversationType == constant.ReadGroupChatType || lc.ConversationType == constant.WriteGroupChatType
+	if res.Ex == "" {
+		localization := sdk_struct.OfflinePushLocalization{DescKey: key, IsGroup: isGroup, Sender: sender, Text: text}
+		if key == "" {
+			// the text of a custom formatter is not localised
+			localization.DescKey, localization.Text = constant.OfflinePushDescText, desc
+		}
+		if isGroup {
+			localization.Group = lc.ShowName
+		}
+		res.Ex = utils.StructToJsonString(localization)
+	}
+	replacer := strings.NewReplacer("{sender}", sender, "{group}", lc.ShowName, "{text}", text)
+	desc = strings.TrimSpace(replacer.Replace(desc))
+	if isGroup {
+		res.Title = replacer.Replace(template.GroupTitle)
+		res.Desc = strings.NewReplacer("{sender}", sender, "{desc}", desc).Replace(template.GroupDesc)
+	} else {
+		res.Title = replacer.Replace(template.Title)
+		res.Desc = desc
+	}
+	return res
+}
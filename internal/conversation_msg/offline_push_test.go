@@ -0,0 +1,51 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !js
+
+package conversation_msg
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+func TestOfflinePushCustomFormatterTruncated(t *testing.T) {
+	c, _ := newTestConversation(t, "u1")
+	c.offlinePush.enabled = true
+	c.offlinePush.template = resolveOfflinePushTemplate("en", nil)
+	c.RegisterCustomPushFormatter("long", func(ctx context.Context, msg *sdk_struct.MsgStruct) string {
+		return strings.Repeat("好", offlinePushTextMaxLength*2)
+	})
+	lc := &model_struct.LocalConversation{ConversationID: "si_u1_u2", ConversationType: constant.SingleChatType, ShowName: "u2"}
+	msg := &sdk_struct.MsgStruct{SendID: "u1", SenderNickname: "me", ContentType: constant.Custom}
+	res := c.fillOfflinePushInfo(context.Background(), msg, lc, nil)
+	if got := utf8.RuneCountInString(res.Desc); got != offlinePushTextMaxLength+1 || !strings.HasSuffix(res.Desc, "…") {
+		t.Fatalf("desc of %d runes: %s", got, res.Desc)
+	}
+	var localization sdk_struct.OfflinePushLocalization
+	if err := json.Unmarshal([]byte(res.Ex), &localization); err != nil {
+		t.Fatal(err)
+	}
+	if localization.Text != res.Desc {
+		t.Fatalf("localization text %s", localization.Text)
+	}
+}
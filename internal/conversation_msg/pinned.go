@@ -0,0 +1,286 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversation_msg
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/jinzhu/copier"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+	"github.com/openimsdk/protocol/sdkws"
+	userPb "github.com/openimsdk/protocol/user"
+	"github.com/openimsdk/protocol/wrapperspb"
+	"github.com/openimsdk/tools/errs"
+	"github.com/openimsdk/tools/log"
+	"github.com/openimsdk/tools/utils/datautil"
+)
+
+// pinnedMessageMinRoleLevel is the lowest group role that may pin or unpin messages.
+const pinnedMessageMinRoleLevel = constant.GroupAdmin
+
+type PinnedMessagesChangedData struct {
+	ConversationID    string                             `json:"conversationID"`
+	PinnedMessageList []*model_struct.LocalPinnedMessage `json:"pinnedMessageList"`
+}
+
+func (c *Conversation) pinMessage(ctx context.Context, conversationID, clientMsgID string, isPinned bool) error {
+	conversation, err := c.db.GetConversation(ctx, conversationID)
+	if err != nil {
+		return err
+	}
+	message, err := c.db.GetMessage(ctx, conversationID, clientMsgID)
+	if err != nil {
+		return err
+	}
+	if !isSentStatus(message.Status) || message.Seq == 0 {
+		return sdkerrs.ErrMsgHasNoSeq.WrapMsg("only send success message can be pinned", "clientMsgID", clientMsgID)
+	}
+	if err := c.checkPinnedMessagePermission(ctx, conversation.ConversationType, conversation.GroupID, c.loginUserID); err != nil {
+		return err
+	}
+	_, err = c.db.GetPinnedMessage(ctx, conversationID, clientMsgID)
+	if err != nil && !errs.ErrRecordNotFound.Is(err) {
+		return err
+	}
+	if (err == nil) == isPinned {
+		log.ZDebug(ctx, "pinned state not changed", "conversationID", conversationID, "clientMsgID", clientMsgID, "isPinned", isPinned)
+		return nil
+	}
+	tips := &sdk_struct.PinnedMessageTips{
+		ConversationID: conversationID,
+		ClientMsgID:    clientMsgID,
+		Seq:            message.Seq,
+		OpUserID:       c.loginUserID,
+		IsPinned:       isPinned,
+		OpTime:         utils.GetCurrentTimestampByMill(),
+	}
+	if err := c.sendPinnedMessageNotification(ctx, conversation, tips); err != nil {
+		return err
+	}
+	if err := c.updatePinnedMessage(ctx, tips); err != nil {
+		return err
+	}
+	c.processPinnedMessageCommand(ctx, tips)
+	return nil
+}
+
+// checkPinnedMessagePermission checks that userID may pin or unpin messages in the conversation.
+func (c *Conversation) checkPinnedMessagePermission(ctx context.Context, conversationType int32, groupID, userID string) error {
+	switch conversationType {
+	case constant.SingleChatType:
+		return nil
+	case constant.ReadGroupChatType, constant.WriteGroupChatType:
+		member, err := c.db.GetGroupMemberInfoByGroupIDUserID(ctx, groupID, userID)
+		if err != nil {
+			return err
+		}
+		if member.RoleLevel < pinnedMessageMinRoleLevel {
+			return sdkerrs.ErrNoPermission.WrapMsg("pin message", "groupID", groupID, "userID", userID, "roleLevel", member.RoleLevel)
+		}
+		return nil
+	default:
+		return sdkerrs.ErrNotSupportType.WrapMsg("pinned messages are not supported in this conversation", "conversationType", conversationType)
+	}
+}
+
+// processPinnedMessageCommand records the pin of the operator in its user commands, which keeps
+// the pin for the devices that never sync the notification. Only the operator's client writes it.
+func (c *Conversation) processPinnedMessageCommand(ctx context.Context, tips *sdk_struct.PinnedMessageTips) {
+	if tips.IsPinned {
+		pinned := &model_struct.LocalPinnedMessage{
+			ConversationID: tips.ConversationID,
+			ClientMsgID:    tips.ClientMsgID,
+			Seq:            tips.Seq,
+			PinnedUserID:   tips.OpUserID,
+			PinnedTime:     tips.OpTime,
+		}
+		if err := c.user.ProcessUserCommandAdd(ctx, &userPb.ProcessUserCommandAddReq{Type: constant.PinnedMessageUserCommandType,
+			Uuid: tips.ClientMsgID, Value: wrapperspb.String(utils.StructToJsonString(pinned))}); err != nil {
+			log.ZWarn(ctx, "add pinned message user command failed", err, "tips", tips)
+		}
+		return
+	}
+	if err := c.user.ProcessUserCommandDelete(ctx, &userPb.ProcessUserCommandDeleteReq{Type: constant.PinnedMessageUserCommandType,
+		Uuid: tips.ClientMsgID}); err != nil {
+		log.ZWarn(ctx, "delete pinned message user command failed", err, "tips", tips)
+	}
+}
+
+// sendPinnedMessageNotification sends the pin change into the conversation, so that the other
+// members and the other devices of the login user apply it when the message arrives or is synced.
+func (c *Conversation) sendPinnedMessageNotification(ctx context.Context, conversation *model_struct.LocalConversation, tips *sdk_struct.PinnedMessageTips) error {
+	s := sdk_struct.MsgStruct{}
+	err := c.initBasicInfo(ctx, &s, constant.UserMsgType, constant.PinnedMessageNotification)
+	if err != nil {
+		return err
+	}
+	s.RecvID = conversation.UserID
+	s.GroupID = conversation.GroupID
+	s.SessionType = conversation.ConversationType
+	s.Content = utils.StructToJsonString(sdk_struct.NotificationElem{Detail: utils.StructToJsonString(tips)})
+	options := make(map[string]bool, 4)
+	utils.SetSwitchFromOptions(options, constant.IsConversationUpdate, false)
+	utils.SetSwitchFromOptions(options, constant.IsSenderConversationUpdate, false)
+	utils.SetSwitchFromOptions(options, constant.IsUnreadCount, false)
+	utils.SetSwitchFromOptions(options, constant.IsOfflinePush, false)
+	var wsMsgData sdkws.MsgData
+	copier.Copy(&wsMsgData, s)
+	wsMsgData.Content = []byte(s.Content)
+	wsMsgData.CreateTime = s.CreateTime
+	wsMsgData.Options = options
+	var sendMsgResp sdkws.UserSendMsgResp
+	err = c.LongConnMgr.SendReqWaitResp(ctx, &wsMsgData, constant.SendMsg, &sendMsgResp)
+	if err != nil {
+		log.ZError(ctx, "pinned message notification to server failed", err, "tips", tips)
+		return err
+	}
+	return nil
+}
+
+func (c *Conversation) doPinnedMessageNotification(ctx context.Context, conversationID string, msg *sdkws.MsgData) {
+	var tips sdk_struct.PinnedMessageTips
+	if err := utils.UnmarshalNotificationElem(msg.Content, &tips); err != nil {
+		log.ZWarn(ctx, "unmarshal pinned message tips failed", err, "msg", msg)
+		return
+	}
+	tips.ConversationID = conversationID
+	tips.OpUserID = msg.SendID
+	if err := c.checkPinnedMessagePermission(ctx, msg.SessionType, msg.GroupID, tips.OpUserID); err != nil {
+		log.ZWarn(ctx, "pinned message notification from a user without permission", err, "tips", &tips)
+		return
+	}
+	if err := c.updatePinnedMessage(ctx, &tips); err != nil {
+		log.ZWarn(ctx, "updatePinnedMessage failed", err, "tips", &tips)
+	}
+}
+
+func (c *Conversation) updatePinnedMessage(ctx context.Context, tips *sdk_struct.PinnedMessageTips) error {
+	_, err := c.db.GetPinnedMessage(ctx, tips.ConversationID, tips.ClientMsgID)
+	if err != nil && !errs.ErrRecordNotFound.Is(err) {
+		return err
+	}
+	exist := err == nil
+	pinned := &model_struct.LocalPinnedMessage{
+		ConversationID: tips.ConversationID,
+		ClientMsgID:    tips.ClientMsgID,
+		Seq:            tips.Seq,
+		PinnedUserID:   tips.OpUserID,
+		PinnedTime:     tips.OpTime,
+	}
+	if tips.IsPinned {
+		if err := c.db.InsertPinnedMessage(ctx, pinned); err != nil {
+			return err
+		}
+		if exist {
+			return nil
+		}
+	} else {
+		if !exist {
+			return nil
+		}
+		if err := c.db.DeletePinnedMessage(ctx, tips.ConversationID, tips.ClientMsgID); err != nil {
+			return err
+		}
+	}
+	return c.pinnedMessagesChanged(ctx, tips.ConversationID)
+}
+
+// pinnedMessagesSynced restores the pins recorded in the user commands that are missing locally,
+// which happens on a new device when the pin notifications are older than the synced history.
+func (c *Conversation) pinnedMessagesSynced(ctx context.Context) {
+	commands, err := c.db.ProcessUserCommandGetAll(ctx)
+	if err != nil {
+		log.ZWarn(ctx, "get user commands failed", err)
+		return
+	}
+	changed := make(map[string]struct{})
+	for _, command := range commands {
+		if command.Type != constant.PinnedMessageUserCommandType {
+			continue
+		}
+		var pinned model_struct.LocalPinnedMessage
+		if err := json.Unmarshal([]byte(command.Value), &pinned); err != nil || pinned.ConversationID == "" {
+			log.ZWarn(ctx, "invalid pinned message", err, "uuid", command.Uuid, "value", command.Value)
+			continue
+		}
+		pinned.ClientMsgID = command.Uuid
+		if _, err := c.db.GetPinnedMessage(ctx, pinned.ConversationID, pinned.ClientMsgID); err == nil {
+			continue
+		} else if !errs.ErrRecordNotFound.Is(err) {
+			log.ZWarn(ctx, "get pinned message failed", err, "pinned", &pinned)
+			continue
+		}
+		if err := c.db.InsertPinnedMessage(ctx, &pinned); err != nil {
+			log.ZWarn(ctx, "insert pinned message failed", err, "pinned", &pinned)
+			continue
+		}
+		changed[pinned.ConversationID] = struct{}{}
+	}
+	for conversationID := range changed {
+		if err := c.pinnedMessagesChanged(ctx, conversationID); err != nil {
+			log.ZWarn(ctx, "pinnedMessagesChanged failed", err, "conversationID", conversationID)
+		}
+	}
+}
+
+func (c *Conversation) pinnedMessagesChanged(ctx context.Context, conversationID string) error {
+	pinnedMessages, err := c.db.GetPinnedMessageList(ctx, conversationID)
+	if err != nil {
+		return err
+	}
+	data := PinnedMessagesChangedData{ConversationID: conversationID, PinnedMessageList: pinnedMessages}
+	c.ConversationListener().OnConversationPinnedMessagesChanged(utils.StructToJsonString(data))
+	return nil
+}
+
+func (c *Conversation) getPinnedMessageList(ctx context.Context, conversationID string) ([]*sdk_struct.MsgStruct, error) {
+	pinnedMessages, err := c.db.GetPinnedMessageList(ctx, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	if len(pinnedMessages) == 0 {
+		return []*sdk_struct.MsgStruct{}, nil
+	}
+	// pinned messages may be older than the local history, pull them before reading.
+	seqs := datautil.Slice(pinnedMessages, func(e *model_struct.LocalPinnedMessage) int64 { return e.Seq })
+	if err := c.pullMessagesBySeqs(ctx, conversationID, seqs); err != nil {
+		log.ZWarn(ctx, "pull pinned messages failed", err, "conversationID", conversationID, "seqs", seqs)
+	}
+	clientMsgIDs := datautil.Slice(pinnedMessages, func(e *model_struct.LocalPinnedMessage) string { return e.ClientMsgID })
+	localMessages, err := c.db.GetMessagesByClientMsgIDs(ctx, conversationID, clientMsgIDs)
+	if err != nil {
+		return nil, err
+	}
+	localMessageMap := datautil.SliceToMap(localMessages, func(e *model_struct.LocalChatLog) string { return e.ClientMsgID })
+	messageList := make([]*sdk_struct.MsgStruct, 0, len(pinnedMessages))
+	for _, pinned := range pinnedMessages {
+		localMessage, ok := localMessageMap[pinned.ClientMsgID]
+		if !ok || isRemovedStatus(localMessage.Status) {
+			continue
+		}
+		msg, err := c.localChatLogToMsgStruct(localMessage)
+		if err != nil {
+			log.ZWarn(ctx, "parsing pinned message failed", err, "message", localMessage)
+			continue
+		}
+		messageList = append(messageList, msg)
+	}
+	return messageList, nil
+}
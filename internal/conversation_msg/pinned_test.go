@@ -0,0 +1,66 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !js
+
+package conversation_msg
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+	"github.com/openimsdk/protocol/sdkws"
+)
+
+func TestPinnedMessageNotificationRoleLevel(t *testing.T) {
+	ctx := context.Background()
+	c, _ := newTestConversation(t, "u1")
+	conversationID := "sg_g1"
+	for _, member := range []*model_struct.LocalGroupMember{
+		{GroupID: "g1", UserID: "admin", RoleLevel: constant.GroupAdmin},
+		{GroupID: "g1", UserID: "member", RoleLevel: constant.GroupOrdinaryUsers},
+	} {
+		if err := c.db.InsertGroupMember(ctx, member); err != nil {
+			t.Fatal(err)
+		}
+	}
+	notify := func(sendID, clientMsgID string) {
+		tips := sdk_struct.PinnedMessageTips{ClientMsgID: clientMsgID, Seq: 1, IsPinned: true, OpTime: 1}
+		// receivers only update the local table, the nil user module fails the test otherwise
+		c.doPinnedMessageNotification(ctx, conversationID, &sdkws.MsgData{SendID: sendID, GroupID: "g1",
+			SessionType: constant.ReadGroupChatType, ContentType: constant.PinnedMessageNotification,
+			Content: []byte(utils.StructToJsonString(sdk_struct.NotificationElem{Detail: utils.StructToJsonString(tips)}))})
+	}
+
+	notify("member", "m1")
+	if _, err := c.db.GetPinnedMessage(ctx, conversationID, "m1"); err == nil {
+		t.Fatal("pin of an ordinary member was applied")
+	}
+	notify("stranger", "m2")
+	if _, err := c.db.GetPinnedMessage(ctx, conversationID, "m2"); err == nil {
+		t.Fatal("pin of a user outside the group was applied")
+	}
+	notify("admin", "m3")
+	pinned, err := c.db.GetPinnedMessage(ctx, conversationID, "m3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pinned.PinnedUserID != "admin" {
+		t.Fatalf("pinned by %s, want admin", pinned.PinnedUserID)
+	}
+}
@@ -0,0 +1,312 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversation_msg
+
+import (
+	"context"
+
+	"github.com/jinzhu/copier"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+	"github.com/openimsdk/protocol/sdkws"
+	"github.com/openimsdk/tools/errs"
+	"github.com/openimsdk/tools/log"
+	"github.com/openimsdk/tools/utils/datautil"
+)
+
+func (c *Conversation) getPollElem(ctx context.Context, conversationID, clientMsgID string) (*model_struct.LocalChatLog, *sdk_struct.PollElem, error) {
+	message, err := c.db.GetMessage(ctx, conversationID, clientMsgID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if message.ContentType != constant.Poll {
+		return nil, nil, sdkerrs.ErrArgs.WrapMsg("message is not a poll", "clientMsgID", clientMsgID, "contentType", message.ContentType)
+	}
+	var elem sdk_struct.PollElem
+	if err := utils.JsonStringToStruct(message.Content, &elem); err != nil {
+		return nil, nil, err
+	}
+	return message, &elem, nil
+}
+
+// votePoll replaces the login user's choice on a poll, an empty optionIDList retracts the vote.
+func (c *Conversation) votePoll(ctx context.Context, conversationID, clientMsgID string, optionIDList []string) error {
+	conversation, err := c.db.GetConversation(ctx, conversationID)
+	if err != nil {
+		return err
+	}
+	message, elem, err := c.getPollElem(ctx, conversationID, clientMsgID)
+	if err != nil {
+		return err
+	}
+	if !isSentStatus(message.Status) || message.Seq == 0 {
+		return sdkerrs.ErrMsgHasNoSeq.WrapMsg("only send success poll can be voted", "clientMsgID", clientMsgID)
+	}
+	now := utils.GetCurrentTimestampByMill()
+	if elem.Deadline != 0 && now > elem.Deadline {
+		return sdkerrs.ErrPollClosed.WrapMsg("poll deadline has passed", "clientMsgID", clientMsgID, "deadline", elem.Deadline)
+	}
+	if datautil.Duplicate(optionIDList) {
+		return sdkerrs.ErrArgs.WrapMsg("duplicate option", "optionIDList", optionIDList)
+	}
+	if !elem.IsMultiple && len(optionIDList) > 1 {
+		return sdkerrs.ErrArgs.WrapMsg("poll does not allow multiple choices", "optionIDList", optionIDList)
+	}
+	optionIDs := datautil.Slice(elem.OptionList, func(e *sdk_struct.PollOption) string { return e.OptionID })
+	for _, optionID := range optionIDList {
+		if !datautil.Contain(optionID, optionIDs...) {
+			return sdkerrs.ErrArgs.WrapMsg("option not found", "optionID", optionID)
+		}
+	}
+	tips := &sdk_struct.PollVoteTips{
+		ConversationID: conversationID,
+		ClientMsgID:    clientMsgID,
+		Seq:            message.Seq,
+		OptionIDList:   optionIDList,
+		VoteTime:       now,
+	}
+	switch {
+	case !elem.IsAnonymous:
+		err = c.sendPollNotification(ctx, conversation.UserID, conversation.GroupID, conversation.ConversationType, constant.PollVoteNotification, tips)
+	case message.SendID != c.loginUserID:
+		// The votes of an anonymous poll only go to its creator, who sends back the counts.
+		err = c.sendPollNotification(ctx, message.SendID, "", constant.SingleChatType, constant.PollVoteNotification, tips)
+	}
+	if err != nil {
+		return err
+	}
+	tips.VoterID = c.loginUserID
+	if err := c.applyPollVote(ctx, tips); err != nil {
+		return err
+	}
+	if elem.IsAnonymous && message.SendID == c.loginUserID {
+		return c.sendPollResult(ctx, conversation, clientMsgID)
+	}
+	return nil
+}
+
+// sendPollNotification sends the poll tips to the online devices only: neither the votes nor the
+// counts are kept as history messages, count as unread or update the conversation.
+func (c *Conversation) sendPollNotification(ctx context.Context, recvID, groupID string, sessionType int32, contentType int32, tips any) error {
+	s := sdk_struct.MsgStruct{}
+	err := c.initBasicInfo(ctx, &s, constant.UserMsgType, contentType)
+	if err != nil {
+		return err
+	}
+	s.RecvID = recvID
+	s.GroupID = groupID
+	s.SessionType = sessionType
+	s.Content = utils.StructToJsonString(sdk_struct.NotificationElem{Detail: utils.StructToJsonString(tips)})
+	options := make(map[string]bool, 6)
+	utils.SetSwitchFromOptions(options, constant.IsHistory, false)
+	utils.SetSwitchFromOptions(options, constant.IsPersistent, false)
+	utils.SetSwitchFromOptions(options, constant.IsUnreadCount, false)
+	utils.SetSwitchFromOptions(options, constant.IsOfflinePush, false)
+	utils.SetSwitchFromOptions(options, constant.IsConversationUpdate, false)
+	utils.SetSwitchFromOptions(options, constant.IsSenderConversationUpdate, false)
+	var wsMsgData sdkws.MsgData
+	copier.Copy(&wsMsgData, s)
+	wsMsgData.Content = []byte(s.Content)
+	wsMsgData.CreateTime = s.CreateTime
+	wsMsgData.Options = options
+	var sendMsgResp sdkws.UserSendMsgResp
+	err = c.LongConnMgr.SendReqWaitResp(ctx, &wsMsgData, constant.SendMsg, &sendMsgResp)
+	if err != nil {
+		log.ZError(ctx, "poll notification to server failed", err, "contentType", contentType, "tips", tips)
+		return err
+	}
+	return nil
+}
+
+// sendPollResult sends the counts of an anonymous poll to its conversation, without the voters.
+func (c *Conversation) sendPollResult(ctx context.Context, conversation *model_struct.LocalConversation, clientMsgID string) error {
+	_, elem, err := c.getPollElem(ctx, conversation.ConversationID, clientMsgID)
+	if err != nil {
+		return err
+	}
+	result := &sdk_struct.PollResult{}
+	if elem.Result != nil {
+		result.VoterCount = elem.Result.VoterCount
+		result.OptionResultList = elem.Result.OptionResultList
+	}
+	return c.sendPollNotification(ctx, conversation.UserID, conversation.GroupID, conversation.ConversationType, constant.PollResultNotification,
+		&sdk_struct.PollResultTips{ConversationID: conversation.ConversationID, ClientMsgID: clientMsgID, Result: result})
+}
+
+func (c *Conversation) doPollVoteNotification(ctx context.Context, conversationID string, msg *sdkws.MsgData) {
+	var tips sdk_struct.PollVoteTips
+	if err := utils.UnmarshalNotificationElem(msg.Content, &tips); err != nil {
+		log.ZWarn(ctx, "unmarshal poll vote tips failed", err, "msg", msg)
+		return
+	}
+	tips.VoterID = msg.SendID
+	if tips.ConversationID == "" || tips.ConversationID == conversationID {
+		tips.ConversationID = conversationID
+		if err := c.applyPollVote(ctx, &tips); err != nil {
+			log.ZWarn(ctx, "applyPollVote failed", err, "tips", &tips)
+		}
+		return
+	}
+	// A vote of an anonymous poll sent to its creator, only the creator and the voter apply it.
+	message, elem, err := c.getPollElem(ctx, tips.ConversationID, tips.ClientMsgID)
+	if err != nil {
+		log.ZWarn(ctx, "get anonymous poll failed", err, "tips", &tips)
+		return
+	}
+	if !elem.IsAnonymous || (message.SendID != c.loginUserID && msg.SendID != c.loginUserID) {
+		log.ZWarn(ctx, "unexpected anonymous poll vote", nil, "tips", &tips, "sendID", msg.SendID)
+		return
+	}
+	if err := c.applyPollVote(ctx, &tips); err != nil {
+		log.ZWarn(ctx, "applyPollVote failed", err, "tips", &tips)
+		return
+	}
+	if message.SendID != c.loginUserID {
+		return
+	}
+	conversation, err := c.db.GetConversation(ctx, tips.ConversationID)
+	if err != nil {
+		log.ZWarn(ctx, "get poll conversation failed", err, "conversationID", tips.ConversationID)
+		return
+	}
+	if err := c.sendPollResult(ctx, conversation, tips.ClientMsgID); err != nil {
+		log.ZWarn(ctx, "sendPollResult failed", err, "tips", &tips)
+	}
+}
+
+// doPollResultNotification applies the counts of an anonymous poll sent by its creator.
+func (c *Conversation) doPollResultNotification(ctx context.Context, conversationID string, msg *sdkws.MsgData) {
+	var tips sdk_struct.PollResultTips
+	if err := utils.UnmarshalNotificationElem(msg.Content, &tips); err != nil || tips.Result == nil {
+		log.ZWarn(ctx, "unmarshal poll result tips failed", err, "msg", msg)
+		return
+	}
+	message, elem, err := c.getPollElem(ctx, conversationID, tips.ClientMsgID)
+	if err != nil {
+		log.ZDebug(ctx, "poll message not found locally", "conversationID", conversationID, "clientMsgID", tips.ClientMsgID, "err", err)
+		return
+	}
+	if !elem.IsAnonymous || message.SendID != msg.SendID || message.SendID == c.loginUserID {
+		return
+	}
+	result := &sdk_struct.PollResult{VoterCount: tips.Result.VoterCount, OptionResultList: tips.Result.OptionResultList}
+	if elem.Result != nil {
+		result.SelfOptionIDList = elem.Result.SelfOptionIDList
+	}
+	if err := c.savePollResult(ctx, conversationID, tips.ClientMsgID, elem, result); err != nil {
+		log.ZWarn(ctx, "savePollResult failed", err, "tips", &tips)
+	}
+}
+
+// applyPollVote stores the voter's latest choice. A retracted vote is kept with an empty option
+// list so that a stale vote delivered later can not bring it back.
+func (c *Conversation) applyPollVote(ctx context.Context, tips *sdk_struct.PollVoteTips) error {
+	vote, err := c.db.GetPollVote(ctx, tips.ConversationID, tips.ClientMsgID, tips.VoterID)
+	if err != nil && !errs.ErrRecordNotFound.Is(err) {
+		return err
+	}
+	if err == nil && vote.VoteTime >= tips.VoteTime {
+		return nil
+	}
+	if err := c.db.InsertPollVote(ctx, &model_struct.LocalPollVote{
+		ConversationID: tips.ConversationID,
+		ClientMsgID:    tips.ClientMsgID,
+		VoterID:        tips.VoterID,
+		OptionIDList:   tips.OptionIDList,
+		VoteTime:       tips.VoteTime,
+	}); err != nil {
+		return err
+	}
+	return c.refreshPollResult(ctx, tips.ConversationID, tips.ClientMsgID)
+}
+
+// refreshPollResult recomputes the poll result from the stored votes.
+func (c *Conversation) refreshPollResult(ctx context.Context, conversationID, clientMsgID string) error {
+	message, elem, err := c.getPollElem(ctx, conversationID, clientMsgID)
+	if err != nil {
+		if errs.ErrRecordNotFound.Is(err) {
+			log.ZDebug(ctx, "poll message not found locally", "conversationID", conversationID, "clientMsgID", clientMsgID)
+			return nil
+		}
+		return err
+	}
+	votes, err := c.db.GetPollVoteList(ctx, conversationID, clientMsgID)
+	if err != nil {
+		return err
+	}
+	result := c.pollResult(elem, votes)
+	if elem.IsAnonymous && message.SendID != c.loginUserID {
+		// Only the creator receives the votes of an anonymous poll, the others keep the counts it sent.
+		self := result.SelfOptionIDList
+		result = &sdk_struct.PollResult{SelfOptionIDList: self}
+		if elem.Result != nil {
+			result.VoterCount = elem.Result.VoterCount
+			result.OptionResultList = elem.Result.OptionResultList
+		}
+	}
+	return c.savePollResult(ctx, conversationID, clientMsgID, elem, result)
+}
+
+// savePollResult writes the result back into the poll message, so that history reads carry it.
+func (c *Conversation) savePollResult(ctx context.Context, conversationID, clientMsgID string, elem *sdk_struct.PollElem, result *sdk_struct.PollResult) error {
+	elem.Result = result
+	if err := c.db.UpdateColumnsMessage(ctx, conversationID, clientMsgID, map[string]any{"content": utils.StructToJsonString(elem)}); err != nil {
+		return err
+	}
+	c.msgListener().OnPollResultChanged(utils.StructToJsonString(sdk_struct.PollResultChanged{
+		ConversationID: conversationID,
+		ClientMsgID:    clientMsgID,
+		Result:         elem.Result,
+	}))
+	return nil
+}
+
+func (c *Conversation) pollResult(elem *sdk_struct.PollElem, votes []*model_struct.LocalPollVote) *sdk_struct.PollResult {
+	optionResults := make(map[string]*sdk_struct.PollOptionResult, len(elem.OptionList))
+	result := &sdk_struct.PollResult{OptionResultList: make([]*sdk_struct.PollOptionResult, 0, len(elem.OptionList))}
+	for _, option := range elem.OptionList {
+		optionResult := &sdk_struct.PollOptionResult{OptionID: option.OptionID}
+		optionResults[option.OptionID] = optionResult
+		result.OptionResultList = append(result.OptionResultList, optionResult)
+	}
+	for _, vote := range votes {
+		if elem.Deadline != 0 && vote.VoteTime > elem.Deadline {
+			continue
+		}
+		var counted bool
+		for _, optionID := range vote.OptionIDList {
+			optionResult, ok := optionResults[optionID]
+			if !ok {
+				continue
+			}
+			counted = true
+			optionResult.VoteCount++
+			if !elem.IsAnonymous {
+				optionResult.VoterIDList = append(optionResult.VoterIDList, vote.VoterID)
+			}
+		}
+		if !counted {
+			continue
+		}
+		result.VoterCount++
+		if vote.VoterID == c.loginUserID {
+			result.SelfOptionIDList = vote.OptionIDList
+		}
+	}
+	return result
+}
@@ -239,6 +239,10 @@ func (c *Conversation) doReadDrawing(ctx context.Context, msg *sdkws.MsgData) er
 
 	}
 	if tips.MarkAsReadUserID != c.loginUserID {
+		switch conversation.ConversationType {
+		case constant.ReadGroupChatType, constant.WriteGroupChatType:
+			return c.doGroupReadDrawing(ctx, conversation, tips, msg.SendTime)
+		}
 		if len(tips.Seqs) == 0 {
 			return errs.New("tips Seqs is empty").Wrap()
 		}
@@ -42,103 +42,163 @@ func (c *Conversation) doRevokeMsg(ctx context.Context, msg *sdkws.MsgData) erro
 	return c.revokeMessage(ctx, &tips)
 }
 
-func (c *Conversation) revokeMessage(ctx context.Context, tips *sdkws.RevokeMsgTips) error {
-	revokedMsg, err := c.db.GetMessageBySeq(ctx, tips.ConversationID, tips.Seq)
-	if err != nil {
-		log.ZError(ctx, "GetMessageBySeq failed", err, "tips", &tips)
-		return errs.Wrap(err)
+// doRevokeMsgs applies the revoke notifications of a conversation received together, so that a
+// moderator revoking many messages results in one update of the conversation and the quotes.
+func (c *Conversation) doRevokeMsgs(ctx context.Context, conversationID string, msgs []*sdkws.MsgData) error {
+	tipsList := make([]*sdkws.RevokeMsgTips, 0, len(msgs))
+	for _, msg := range msgs {
+		var tips sdkws.RevokeMsgTips
+		if err := utils.UnmarshalNotificationElem(msg.Content, &tips); err != nil {
+			log.ZWarn(ctx, "unmarshal failed", err, "msg", msg)
+			continue
+		}
+		tipsList = append(tipsList, &tips)
 	}
+	log.ZDebug(ctx, "do revokeMessages", "conversationID", conversationID, "len", len(tipsList))
+	return c.revokeMessages(ctx, conversationID, tipsList)
+}
+
+func (c *Conversation) revokeMessage(ctx context.Context, tips *sdkws.RevokeMsgTips) error {
+	return c.revokeMessages(ctx, tips.ConversationID, []*sdkws.RevokeMsgTips{tips})
+}
+
+type revokerKey struct {
+	userID        string
+	isAdminRevoke bool
+}
+
+type revokerInfo struct {
+	role     int32
+	nickname string
+}
 
-	var revokerRole int32
-	var revokerNickname string
+func (c *Conversation) getRevokerInfo(ctx context.Context, conversation *model_struct.LocalConversation, tips *sdkws.RevokeMsgTips) (*revokerInfo, error) {
+	var revoker revokerInfo
 	if tips.IsAdminRevoke || tips.SesstionType == constant.SingleChatType {
 		_, userName, err := c.getUserNameAndFaceURL(ctx, tips.RevokerUserID)
 		if err != nil {
-			log.ZError(ctx, "GetUserNameAndFaceURL failed", err, "tips", &tips)
-			return errs.Wrap(err)
+			log.ZError(ctx, "GetUserNameAndFaceURL failed", err, "tips", tips)
+			return nil, errs.Wrap(err)
 		} else {
 			log.ZDebug(ctx, "revoker user name", "userName", userName)
 		}
-
-		revokerNickname = userName
+		revoker.nickname = userName
 	} else if tips.SesstionType == constant.ReadGroupChatType {
-		conversation, err := c.db.GetConversation(ctx, tips.ConversationID)
-		if err != nil {
-			log.ZError(ctx, "GetConversation failed", err, "conversationID", tips.ConversationID)
-			return errs.Wrap(err)
-		}
-
 		groupMember, err := c.db.GetGroupMemberInfoByGroupIDUserID(ctx, conversation.GroupID, tips.RevokerUserID)
 		if err != nil {
-			log.ZError(ctx, "GetGroupMemberInfoByGroupIDUserID failed", err, "tips", &tips)
-			return errs.Wrap(err)
+			log.ZError(ctx, "GetGroupMemberInfoByGroupIDUserID failed", err, "tips", tips)
+			return nil, errs.Wrap(err)
 		} else {
 			log.ZDebug(ctx, "revoker member name", "groupMember", groupMember)
-			revokerRole = groupMember.RoleLevel
-			revokerNickname = groupMember.Nickname
+			revoker.role = groupMember.RoleLevel
+			revoker.nickname = groupMember.Nickname
 		}
 	}
-	m := sdk_struct.MessageRevoked{
-		RevokerID:                   tips.RevokerUserID,
-		RevokerRole:                 revokerRole,
-		ClientMsgID:                 revokedMsg.ClientMsgID,
-		RevokerNickname:             revokerNickname,
-		RevokeTime:                  tips.RevokeTime,
-		SourceMessageSendTime:       revokedMsg.SendTime,
-		SourceMessageSendID:         revokedMsg.SendID,
-		SourceMessageSenderNickname: revokedMsg.SenderNickname,
-		SessionType:                 tips.SesstionType,
-		Seq:                         tips.Seq,
-		Ex:                          revokedMsg.Ex,
-		IsAdminRevoke:               tips.IsAdminRevoke,
-	}
-	// log.ZDebug(ctx, "callback revokeMessage", "m", m)
-	var n sdk_struct.NotificationElem
-	n.Detail = utils.StructToJsonString(m)
-	if err := c.db.UpdateMessageBySeq(ctx, tips.ConversationID, &model_struct.LocalChatLog{Seq: tips.Seq,
-		Content: utils.StructToJsonString(n), ContentType: constant.RevokeNotification}); err != nil {
-		log.ZError(ctx, "UpdateMessageBySeq failed", err, "tips", &tips)
-		return errs.Wrap(err)
+	return &revoker, nil
+}
+
+// revokeMessages replaces the revoked messages of a conversation by revoke notifications, then
+// refreshes the latest message, notifies the listener and updates the quotes once for all of them.
+func (c *Conversation) revokeMessages(ctx context.Context, conversationID string, tipsList []*sdkws.RevokeMsgTips) error {
+	if len(tipsList) == 0 {
+		return nil
 	}
-	conversation, err := c.db.GetConversation(ctx, tips.ConversationID)
+	conversation, err := c.db.GetConversation(ctx, conversationID)
 	if err != nil {
-		log.ZError(ctx, "GetConversation failed", err, "tips", &tips)
+		log.ZError(ctx, "GetConversation failed", err, "conversationID", conversationID)
 		return errs.Wrap(err)
 	}
+	revokers := make(map[revokerKey]*revokerInfo)
+	revokedList := make([]*sdk_struct.MessageRevoked, 0, len(tipsList))
+	var maxSeq int64
+	for _, tips := range tipsList {
+		revokedMsg, err := c.db.GetMessageBySeq(ctx, conversationID, tips.Seq)
+		if err != nil {
+			log.ZError(ctx, "GetMessageBySeq failed", err, "tips", tips)
+			if len(tipsList) == 1 {
+				return errs.Wrap(err)
+			}
+			continue
+		}
+		key := revokerKey{userID: tips.RevokerUserID, isAdminRevoke: tips.IsAdminRevoke}
+		revoker, ok := revokers[key]
+		if !ok {
+			revoker, err = c.getRevokerInfo(ctx, conversation, tips)
+			if err != nil {
+				if len(tipsList) == 1 {
+					return err
+				}
+				continue
+			}
+			revokers[key] = revoker
+		}
+		m := sdk_struct.MessageRevoked{
+			RevokerID:                   tips.RevokerUserID,
+			RevokerRole:                 revoker.role,
+			ClientMsgID:                 revokedMsg.ClientMsgID,
+			RevokerNickname:             revoker.nickname,
+			RevokeTime:                  tips.RevokeTime,
+			SourceMessageSendTime:       revokedMsg.SendTime,
+			SourceMessageSendID:         revokedMsg.SendID,
+			SourceMessageSenderNickname: revokedMsg.SenderNickname,
+			SessionType:                 tips.SesstionType,
+			Seq:                         tips.Seq,
+			Ex:                          revokedMsg.Ex,
+			IsAdminRevoke:               tips.IsAdminRevoke,
+		}
+		var n sdk_struct.NotificationElem
+		n.Detail = utils.StructToJsonString(m)
+		if err := c.db.UpdateMessageBySeq(ctx, conversationID, &model_struct.LocalChatLog{Seq: tips.Seq,
+			Content: utils.StructToJsonString(n), ContentType: constant.RevokeNotification}); err != nil {
+			log.ZError(ctx, "UpdateMessageBySeq failed", err, "tips", tips)
+			if len(tipsList) == 1 {
+				return errs.Wrap(err)
+			}
+			continue
+		}
+		revokedList = append(revokedList, &m)
+		maxSeq = max(maxSeq, tips.Seq)
+	}
+	if len(revokedList) == 0 {
+		return nil
+	}
 	var latestMsg sdk_struct.MsgStruct
 	utils.JsonStringToStruct(conversation.LatestMsg, &latestMsg)
-	log.ZDebug(ctx, "latestMsg", "latestMsg", &latestMsg, "seq", tips.Seq)
-	if latestMsg.Seq <= tips.Seq {
+	log.ZDebug(ctx, "latestMsg", "latestMsg", &latestMsg, "seq", maxSeq)
+	if latestMsg.Seq <= maxSeq {
 		var newLatesetMsg sdk_struct.MsgStruct
-		msgs, err := c.db.GetMessageListNoTime(ctx, tips.ConversationID, 1, false)
+		msgs, err := c.db.GetMessageListNoTime(ctx, conversationID, 1, false)
 		if err != nil || len(msgs) == 0 {
-			log.ZError(ctx, "GetMessageListNoTime failed", err, "tips", &tips)
+			log.ZError(ctx, "GetMessageListNoTime failed", err, "conversationID", conversationID)
 			return errs.Wrap(err)
 		}
-		log.ZDebug(ctx, "latestMsg is revoked", "seq", tips.Seq, "msg", msgs[0])
+		log.ZDebug(ctx, "latestMsg is revoked", "seq", maxSeq, "msg", msgs[0])
 		copier.Copy(&newLatesetMsg, msgs[0])
 		err = c.msgConvert(&newLatesetMsg)
 		if err != nil {
 			log.ZError(ctx, "parsing data error", err, latestMsg)
 		} else {
 			log.ZDebug(ctx, "revoke update conversatoin", "msg", utils.StructToJsonString(newLatesetMsg))
-			if err := c.db.UpdateColumnsConversation(ctx, tips.ConversationID, map[string]interface{}{"latest_msg": utils.StructToJsonString(newLatesetMsg),
+			if err := c.db.UpdateColumnsConversation(ctx, conversationID, map[string]interface{}{"latest_msg": utils.StructToJsonString(newLatesetMsg),
 				"latest_msg_send_time": newLatesetMsg.SendTime}); err != nil {
 				log.ZError(ctx, "UpdateColumnsConversation failed", err, "newLatesetMsg", newLatesetMsg)
 			} else {
-				c.doUpdateConversation(common.Cmd2Value{Value: common.UpdateConNode{Action: constant.ConChange, Args: []string{tips.ConversationID}}})
+				c.doUpdateConversation(common.Cmd2Value{Value: common.UpdateConNode{Action: constant.ConChange, Args: []string{conversationID}}})
 			}
 		}
 	}
-	c.msgListener().OnNewRecvMessageRevoked(utils.StructToJsonString(m))
-	msgList, err := c.db.SearchAllMessageByContentType(ctx, conversation.ConversationID, constant.Quote)
+	c.notifyMessagesRevoked(revokedList)
+	msgList, err := c.db.SearchAllMessageByContentType(ctx, conversationID, constant.Quote)
 	if err != nil {
-		log.ZError(ctx, "SearchAllMessageByContentType failed", err, "tips", &tips)
+		log.ZError(ctx, "SearchAllMessageByContentType failed", err, "conversationID", conversationID)
 		return errs.Wrap(err)
 	}
-
+	revokedMap := make(map[string]*sdk_struct.MessageRevoked, len(revokedList))
+	for _, m := range revokedList {
+		revokedMap[m.ClientMsgID] = m
+	}
 	for _, v := range msgList {
-		err = c.quoteMsgRevokeHandle(ctx, tips.ConversationID, v, m)
+		err = c.quoteMsgRevokeHandle(ctx, conversationID, v, revokedMap)
 		if err != nil {
 			log.ZError(ctx, "quote Msg Revoke Handle failed.", err, "chat Log content", v)
 		}
@@ -146,7 +206,20 @@ func (c *Conversation) revokeMessage(ctx context.Context, tips *sdkws.RevokeMsgT
 	return errs.Wrap(err)
 }
 
-func (c *Conversation) quoteMsgRevokeHandle(ctx context.Context, conversationID string, v *model_struct.LocalChatLog, revokedMsg sdk_struct.MessageRevoked) error {
+// notifyMessagesRevoked reports the revoked messages in one call to the batch listener when one
+// is set, and one by one to the message listener otherwise.
+func (c *Conversation) notifyMessagesRevoked(revokedList []*sdk_struct.MessageRevoked) {
+	if c.batchMsgListener() != nil {
+		c.batchMsgListener().OnRecvMessagesRevoked(utils.StructToJsonString(revokedList))
+		return
+	}
+	for _, m := range revokedList {
+		c.msgListener().OnNewRecvMessageRevoked(utils.StructToJsonString(m))
+	}
+}
+
+// quoteMsgRevokeHandle replaces the quoted message by its revoke notification, when it is one of the revoked messages.
+func (c *Conversation) quoteMsgRevokeHandle(ctx context.Context, conversationID string, v *model_struct.LocalChatLog, revokedMap map[string]*sdk_struct.MessageRevoked) error {
 	s := sdk_struct.QuoteElem{}
 	if v.Content == "" {
 		return errs.New("Chat Log Content not found")
@@ -159,8 +232,9 @@ func (c *Conversation) quoteMsgRevokeHandle(ctx context.Context, conversationID
 	if s.QuoteMessage == nil {
 		return errs.New("QuoteMessage is nil").Wrap()
 	}
-	if s.QuoteMessage.ClientMsgID != revokedMsg.ClientMsgID {
-		return errs.New("quoteMessage ClientMsgID is not revokedMsg ClientMsgID").Wrap()
+	revokedMsg, ok := revokedMap[s.QuoteMessage.ClientMsgID]
+	if !ok {
+		return nil
 	}
 
 	s.QuoteMessage.Content = utils.StructToJsonString(revokedMsg)
@@ -173,16 +247,23 @@ func (c *Conversation) quoteMsgRevokeHandle(ctx context.Context, conversationID
 	return nil
 }
 
-func (c *Conversation) revokeOneMessage(ctx context.Context, conversationID, clientMsgID string) error {
-	conversation, err := c.db.GetConversation(ctx, conversationID)
+func (c *Conversation) isGroupAdmin(ctx context.Context, groupID string) (bool, error) {
+	groupAdmins, err := c.db.GetGroupMemberOwnerAndAdminDB(ctx, groupID)
 	if err != nil {
-		return err
+		return false, err
 	}
-	message, err := c.db.GetMessage(ctx, conversationID, clientMsgID)
-	if err != nil {
-		return err
+	for _, member := range groupAdmins {
+		if member.UserID == c.loginUserID {
+			return true, nil
+		}
 	}
-	if message.Status != constant.MsgStatusSendSuccess {
+	return false, nil
+}
+
+// checkRevokeMessage checks the login user may revoke the message, isAdmin tells whether the login
+// user administers the group of the conversation.
+func (c *Conversation) checkRevokeMessage(conversation *model_struct.LocalConversation, message *model_struct.LocalChatLog, isAdmin bool) error {
+	if !isSentStatus(message.Status) {
 		return errors.New("only send success message can be revoked")
 	}
 	switch conversation.ConversationType {
@@ -191,23 +272,31 @@ func (c *Conversation) revokeOneMessage(ctx context.Context, conversationID, cli
 			return errors.New("only send by yourself message can be revoked")
 		}
 	case constant.ReadGroupChatType:
-		if message.SendID != c.loginUserID {
-			groupAdmins, err := c.db.GetGroupMemberOwnerAndAdminDB(ctx, conversation.GroupID)
-			if err != nil {
-				return err
-			}
-			var isAdmin bool
-			for _, member := range groupAdmins {
-				if member.UserID == c.loginUserID {
-					isAdmin = true
-					break
-				}
-			}
-			if !isAdmin {
-				return errors.New("only group admin can revoke message")
-			}
+		if message.SendID != c.loginUserID && !isAdmin {
+			return errors.New("only group admin can revoke message")
+		}
+	}
+	return nil
+}
+
+func (c *Conversation) revokeOneMessage(ctx context.Context, conversationID, clientMsgID string) error {
+	conversation, err := c.db.GetConversation(ctx, conversationID)
+	if err != nil {
+		return err
+	}
+	message, err := c.db.GetMessage(ctx, conversationID, clientMsgID)
+	if err != nil {
+		return err
+	}
+	var isAdmin bool
+	if conversation.ConversationType == constant.ReadGroupChatType && message.SendID != c.loginUserID {
+		if isAdmin, err = c.isGroupAdmin(ctx, conversation.GroupID); err != nil {
+			return err
 		}
 	}
+	if err := c.checkRevokeMessage(conversation, message, isAdmin); err != nil {
+		return err
+	}
 
 	err = c.revokeMessageFromServer(ctx, conversationID, message.Seq)
 	if err != nil {
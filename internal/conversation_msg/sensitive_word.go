@@ -0,0 +1,147 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversation_msg
+
+import (
+	"context"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sensitive"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+	"github.com/openimsdk/tools/errs"
+	"github.com/openimsdk/tools/log"
+)
+
+const sensitiveWordMask = '*'
+
+// sensitiveWordFilter holds the words loaded from the configured file and the words pushed
+// through user commands, the matcher is rebuilt whenever either of them changes.
+type sensitiveWordFilter struct {
+	lock          sync.RWMutex
+	mode          int32
+	fileWords     []string
+	commandWords  []string
+	commandLoaded bool
+	matcher       *sensitive.Matcher
+}
+
+func (f *sensitiveWordFilter) rebuild() {
+	f.matcher = sensitive.NewMatcher(append(append([]string{}, f.fileWords...), f.commandWords...))
+}
+
+// setSensitiveWordFilter sets the filter mode and reloads the word file, one word per line.
+// An empty wordFilePath keeps only the words pushed through user commands.
+func (c *Conversation) setSensitiveWordFilter(ctx context.Context, mode int32, wordFilePath string) error {
+	switch mode {
+	case constant.SensitiveWordFilterOff, constant.SensitiveWordFilterBlock,
+		constant.SensitiveWordFilterMask, constant.SensitiveWordFilterFlag:
+	default:
+		return sdkerrs.ErrArgs.WrapMsg("unknown sensitive word filter mode", "mode", mode)
+	}
+	var fileWords []string
+	if wordFilePath != "" {
+		data, err := os.ReadFile(wordFilePath)
+		if err != nil {
+			return errs.WrapMsg(err, "read sensitive word file failed", "path", wordFilePath)
+		}
+		fileWords = strings.Split(string(data), "\n")
+	}
+	c.sensitive.lock.Lock()
+	defer c.sensitive.lock.Unlock()
+	c.sensitive.mode = mode
+	c.sensitive.fileWords = fileWords
+	c.sensitive.rebuild()
+	log.ZInfo(ctx, "sensitive word filter updated", "mode", mode, "fileWords", len(fileWords))
+	return nil
+}
+
+func (c *Conversation) loadSensitiveCommandWords(ctx context.Context) ([]string, error) {
+	commands, err := c.db.ProcessUserCommandGetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var words []string
+	for _, command := range commands {
+		if command.Type == constant.SensitiveWordUserCommandType {
+			words = append(words, strings.Split(command.Value, ",")...)
+		}
+	}
+	return words, nil
+}
+
+// sensitiveWordsChanged reloads the command words after the sensitive word user commands changed.
+func (c *Conversation) sensitiveWordsChanged(ctx context.Context) {
+	words, err := c.loadSensitiveCommandWords(ctx)
+	if err != nil {
+		log.ZWarn(ctx, "load sensitive command words failed", err)
+		return
+	}
+	c.sensitive.lock.Lock()
+	defer c.sensitive.lock.Unlock()
+	c.sensitive.commandWords = words
+	c.sensitive.commandLoaded = true
+	c.sensitive.rebuild()
+}
+
+func (c *Conversation) sensitiveWordMatcher(ctx context.Context) (int32, *sensitive.Matcher) {
+	c.sensitive.lock.RLock()
+	mode, matcher, loaded := c.sensitive.mode, c.sensitive.matcher, c.sensitive.commandLoaded
+	c.sensitive.lock.RUnlock()
+	if mode == constant.SensitiveWordFilterOff || loaded {
+		return mode, matcher
+	}
+	c.sensitiveWordsChanged(ctx)
+	c.sensitive.lock.RLock()
+	defer c.sensitive.lock.RUnlock()
+	return c.sensitive.mode, c.sensitive.matcher
+}
+
+// filterSensitiveWords applies the filter mode to the text of the message before it is sent.
+func (c *Conversation) filterSensitiveWords(ctx context.Context, s *sdk_struct.MsgStruct) error {
+	mode, matcher := c.sensitiveWordMatcher(ctx)
+	if mode == constant.SensitiveWordFilterOff || matcher.Empty() {
+		return nil
+	}
+	var text *string
+	switch {
+	case s.ContentType == constant.Text && s.TextElem != nil:
+		text = &s.TextElem.Content
+	case s.ContentType == constant.AtText && s.AtTextElem != nil:
+		text = &s.AtTextElem.Text
+	case s.ContentType == constant.Quote && s.QuoteElem != nil:
+		text = &s.QuoteElem.Text
+	case s.ContentType == constant.AdvancedText && s.AdvancedTextElem != nil:
+		text = &s.AdvancedTextElem.Text
+	}
+	if text == nil || !matcher.Contains(*text) {
+		return nil
+	}
+	switch mode {
+	case constant.SensitiveWordFilterBlock:
+		return sdkerrs.ErrMsgSensitiveWord.WrapMsg("message blocked", "clientMsgID", s.ClientMsgID)
+	case constant.SensitiveWordFilterMask:
+		*text = matcher.Replace(*text, sensitiveWordMask)
+	case constant.SensitiveWordFilterFlag:
+		if s.AttachedInfoElem == nil {
+			s.AttachedInfoElem = &sdk_struct.AttachedInfoElem{}
+		}
+		s.AttachedInfoElem.IsSensitive = true
+	}
+	return nil
+}
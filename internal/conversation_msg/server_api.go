@@ -42,6 +42,13 @@ func (c *Conversation) deleteMessagesFromServer(ctx context.Context, conversatio
 	return api.DeleteMsgs.Execute(ctx, req)
 }
 
+// deleteMessagesForEveryoneFromServer deletes the messages for all the members of the conversation.
+func (c *Conversation) deleteMessagesForEveryoneFromServer(ctx context.Context, conversationID string, seqs []int64) error {
+	req := &pbMsg.DeleteMsgsReq{UserID: c.loginUserID, Seqs: seqs, ConversationID: conversationID,
+		DeleteSyncOpt: &pbMsg.DeleteSyncOpt{IsSyncSelf: true, IsSyncOther: true}}
+	return api.DeleteMsgs.Execute(ctx, req)
+}
+
 func (c *Conversation) revokeMessageFromServer(ctx context.Context, conversationID string, seq int64) error {
 	req := &pbMsg.RevokeMsgReq{UserID: c.loginUserID, ConversationID: conversationID, Seq: seq}
 	return api.RevokeMsg.Execute(ctx, req)
@@ -0,0 +1,234 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversation_msg
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdk_params_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdkerrs"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+	userPb "github.com/openimsdk/protocol/user"
+	"github.com/openimsdk/protocol/wrapperspb"
+	"github.com/openimsdk/tools/log"
+	"github.com/openimsdk/tools/utils/datautil"
+)
+
+// starredMessageTextMaxLength is the number of characters of the message kept in a starred snapshot
+const starredMessageTextMaxLength = 500
+
+// starredMessageSnapshotOf keeps the text view of the message and the metadata of its element.
+func starredMessageSnapshotOf(msg *sdk_struct.MsgStruct) *sdk_struct.StarredMessageSnapshot {
+	view := messageTextOf(msg, nil)
+	snapshot := &sdk_struct.StarredMessageSnapshot{
+		SendID:         msg.SendID,
+		SenderNickname: msg.SenderNickname,
+		SenderFaceURL:  msg.SenderFaceURL,
+		SessionType:    msg.SessionType,
+		ContentType:    msg.ContentType,
+		Seq:            msg.Seq,
+		SendTime:       msg.SendTime,
+		Text:           truncateText(view.Text, starredMessageTextMaxLength),
+		ImageURL:       view.Image,
+		LinkURL:        view.Link,
+	}
+	switch {
+	case msg.FileElem != nil:
+		snapshot.FileName, snapshot.FileSize = msg.FileElem.FileName, msg.FileElem.FileSize
+	case msg.VideoElem != nil:
+		snapshot.FileSize, snapshot.Duration = msg.VideoElem.VideoSize, msg.VideoElem.Duration
+	case msg.SoundElem != nil:
+		snapshot.FileSize, snapshot.Duration = msg.SoundElem.DataSize, msg.SoundElem.Duration
+	}
+	return snapshot
+}
+
+// getAllStarredMessages reads the starred messages from the user commands, the most recently starred first.
+func (c *Conversation) getAllStarredMessages(ctx context.Context) ([]*sdk_struct.StarredMessage, error) {
+	commands, err := c.db.ProcessUserCommandGetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	starred := make([]*sdk_struct.StarredMessage, 0)
+	for _, command := range commands {
+		if command.Type != constant.StarredMessageUserCommandType {
+			continue
+		}
+		var msg sdk_struct.StarredMessage
+		if err := json.Unmarshal([]byte(command.Value), &msg); err != nil {
+			log.ZWarn(ctx, "invalid starred message", err, "uuid", command.Uuid, "value", command.Value)
+			continue
+		}
+		msg.ClientMsgID = command.Uuid
+		starred = append(starred, &msg)
+	}
+	sort.SliceStable(starred, func(i, j int) bool {
+		return starred[i].StarTime > starred[j].StarTime
+	})
+	return starred, nil
+}
+
+func (c *Conversation) getStarredMessage(ctx context.Context, clientMsgID string) (*sdk_struct.StarredMessage, error) {
+	starred, err := c.getAllStarredMessages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, msg := range starred {
+		if msg.ClientMsgID == clientMsgID {
+			return msg, nil
+		}
+	}
+	return nil, sdkerrs.ErrStarredMessageNotFound.WrapMsg("clientMsgID " + clientMsgID)
+}
+
+func normalizeStarredTags(tagList []string) []string {
+	tags := make([]string, 0, len(tagList))
+	for _, tag := range tagList {
+		if tag = strings.TrimSpace(tag); tag != "" && !datautil.Contain(tag, tags...) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// starMessage stores a snapshot of the message as a user command, so that it is synced to the other
+// devices. Starring a message again replaces its tags and keeps its star time.
+func (c *Conversation) starMessage(ctx context.Context, conversationID, clientMsgID string, tagList []string) (*sdk_struct.StarredMessage, error) {
+	if conversationID == "" || clientMsgID == "" {
+		return nil, sdkerrs.ErrArgs.WrapMsg("conversationID and clientMsgID can't be empty")
+	}
+	if existing, err := c.getStarredMessage(ctx, clientMsgID); err == nil {
+		existing.TagList = normalizeStarredTags(tagList)
+		return existing, c.setStarredMessageTags(ctx, clientMsgID, tagList)
+	} else if !sdkerrs.ErrStarredMessageNotFound.Is(err) {
+		return nil, err
+	}
+	localMessage, err := c.db.GetMessage(ctx, conversationID, clientMsgID)
+	if err != nil {
+		return nil, err
+	}
+	if !isSentStatus(localMessage.Status) {
+		return nil, sdkerrs.ErrArgs.WrapMsg("only messages sent successfully can be starred", "status", localMessage.Status)
+	}
+	msg, err := c.localChatLogToMsgStruct(localMessage)
+	if err != nil {
+		return nil, err
+	}
+	starred := &sdk_struct.StarredMessage{
+		ConversationID: conversationID,
+		ClientMsgID:    clientMsgID,
+		TagList:        normalizeStarredTags(tagList),
+		StarTime:       utils.GetCurrentTimestampByMill(),
+		Snapshot:       starredMessageSnapshotOf(msg),
+	}
+	err = c.user.ProcessUserCommandAdd(ctx, &userPb.ProcessUserCommandAddReq{Type: constant.StarredMessageUserCommandType,
+		Uuid: clientMsgID, Value: wrapperspb.String(utils.StructToJsonString(starred))})
+	if err != nil {
+		return nil, err
+	}
+	return starred, nil
+}
+
+func (c *Conversation) unstarMessage(ctx context.Context, clientMsgID string) error {
+	if _, err := c.getStarredMessage(ctx, clientMsgID); err != nil {
+		return err
+	}
+	return c.user.ProcessUserCommandDelete(ctx, &userPb.ProcessUserCommandDeleteReq{Type: constant.StarredMessageUserCommandType, Uuid: clientMsgID})
+}
+
+func (c *Conversation) setStarredMessageTags(ctx context.Context, clientMsgID string, tagList []string) error {
+	starred, err := c.getStarredMessage(ctx, clientMsgID)
+	if err != nil {
+		return err
+	}
+	starred.TagList = normalizeStarredTags(tagList)
+	return c.user.ProcessUserCommandUpdate(ctx, &userPb.ProcessUserCommandUpdateReq{Type: constant.StarredMessageUserCommandType,
+		Uuid: clientMsgID, Value: wrapperspb.String(utils.StructToJsonString(starred))})
+}
+
+func matchStarredMessage(starred *sdk_struct.StarredMessage, keywords []string) bool {
+	if len(keywords) == 0 {
+		return true
+	}
+	var fields []string
+	if starred.Snapshot != nil {
+		sender := starred.Snapshot.SenderNickname
+		if sender == "" {
+			sender = starred.Snapshot.SendID
+		}
+		fields = append(fields, sender, starred.Snapshot.Text, starred.Snapshot.FileName)
+	}
+	fields = append(fields, starred.TagList...)
+	for _, keyword := range keywords {
+		keyword = strings.ToLower(keyword)
+		for _, field := range fields {
+			if strings.Contains(strings.ToLower(field), keyword) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (c *Conversation) getStarredMessageList(ctx context.Context, req *sdk_params_callback.GetStarredMessagesParams) (*sdk_params_callback.GetStarredMessagesCallback, error) {
+	if req.Offset < 0 || req.Count <= 0 {
+		return nil, sdkerrs.ErrArgs.WrapMsg("invalid offset or count")
+	}
+	starred, err := c.getAllStarredMessages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	keywords := utils.TrimStringList(req.KeywordList)
+	tags := normalizeStarredTags(req.TagList)
+	starred = datautil.Filter(starred, func(msg *sdk_struct.StarredMessage) (*sdk_struct.StarredMessage, bool) {
+		if req.ConversationID != "" && msg.ConversationID != req.ConversationID {
+			return nil, false
+		}
+		for _, tag := range tags {
+			if !datautil.Contain(tag, msg.TagList...) {
+				return nil, false
+			}
+		}
+		return msg, matchStarredMessage(msg, keywords)
+	})
+	res := &sdk_params_callback.GetStarredMessagesCallback{TotalCount: len(starred), StarredMessageList: []*sdk_struct.StarredMessage{}}
+	if req.Offset < len(starred) {
+		res.StarredMessageList = starred[req.Offset:min(req.Offset+req.Count, len(starred))]
+	}
+	return res, nil
+}
+
+// getStarredMessageTags returns every tag in use, sorted.
+func (c *Conversation) getStarredMessageTags(ctx context.Context) ([]string, error) {
+	starred, err := c.getAllStarredMessages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var tags []string
+	for _, msg := range starred {
+		tags = append(tags, msg.TagList...)
+	}
+	tags = datautil.Distinct(tags)
+	sort.Strings(tags)
+	if tags == nil {
+		tags = []string{}
+	}
+	return tags, nil
+}
@@ -0,0 +1,173 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !js
+
+package conversation_msg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/openimsdk/openim-sdk-core/v3/internal/user"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/ccontext"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/sdk_params_callback"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+	userPb "github.com/openimsdk/protocol/user"
+)
+
+// newUserCommandServer keeps the user commands of the login user in memory, the same as the server.
+func newUserCommandServer(t *testing.T, c *Conversation) context.Context {
+	var (
+		lock     sync.Mutex
+		commands []*userPb.AllCommandInfoResp
+	)
+	find := func(uuid string) int {
+		for i, command := range commands {
+			if command.Uuid == uuid {
+				return i
+			}
+		}
+		return -1
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		var err error
+		switch r.URL.Path {
+		case "/user/process_user_command_add":
+			var req userPb.ProcessUserCommandAddReq
+			if err = json.NewDecoder(r.Body).Decode(&req); err == nil {
+				commands = append(commands, &userPb.AllCommandInfoResp{Type: req.Type, Uuid: req.Uuid, Value: req.Value.GetValue()})
+			}
+		case "/user/process_user_command_update":
+			var req userPb.ProcessUserCommandUpdateReq
+			if err = json.NewDecoder(r.Body).Decode(&req); err == nil && find(req.Uuid) >= 0 {
+				commands[find(req.Uuid)].Value = req.Value.GetValue()
+			}
+		case "/user/process_user_command_delete":
+			var req userPb.ProcessUserCommandDeleteReq
+			if err = json.NewDecoder(r.Body).Decode(&req); err == nil && find(req.Uuid) >= 0 {
+				commands = append(commands[:find(req.Uuid)], commands[find(req.Uuid)+1:]...)
+			}
+		case "/user/process_user_command_get_all":
+			fmt.Fprintf(w, `{"errCode":0,"data":%s}`, utils.StructToJsonString(&userPb.ProcessUserCommandGetAllResp{CommandResp: commands}))
+			return
+		default:
+			err = fmt.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, `{"errCode":0,"data":{}}`)
+	}))
+	t.Cleanup(server.Close)
+	c.user = user.NewUser(c.db, c.loginUserID, nil)
+	ctx := ccontext.WithInfo(context.Background(), &ccontext.GlobalConfig{UserID: c.loginUserID, IMConfig: sdk_struct.IMConfig{ApiAddr: server.URL}})
+	return ccontext.WithOperationID(ctx, "starred_test")
+}
+
+func TestStarMessageSnapshot(t *testing.T) {
+	c, _ := newTestConversation(t, "u1")
+	ctx := newUserCommandServer(t, c)
+	conversationID := "si_u1_u2"
+	long := newTestTextMessage(conversationID, "m1", "u2", 1, 1000)
+	long.SenderNickname = "bob"
+	long.Content = utils.StructToJsonString(sdk_struct.TextElem{Content: strings.Repeat("好", starredMessageTextMaxLength*4)})
+	file := newTestTextMessage(conversationID, "m2", "u1", 2, 2000)
+	file.ContentType = constant.File
+	file.Content = utils.StructToJsonString(sdk_struct.FileElem{FileName: "report.pdf", FileSize: 2048, SourceURL: "https://example.com/report.pdf"})
+	if err := c.db.BatchInsertMessageList(ctx, conversationID, []*model_struct.LocalChatLog{long, file}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.starMessage(ctx, conversationID, "m1", []string{" work ", "work"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.starMessage(ctx, conversationID, "m2", []string{"docs"}); err != nil {
+		t.Fatal(err)
+	}
+	commands, err := c.db.ProcessUserCommandGetAll(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, command := range commands {
+		if len(command.Value) > 4*starredMessageTextMaxLength+1024 {
+			t.Fatalf("user command of %s holds %d bytes", command.Uuid, len(command.Value))
+		}
+	}
+
+	starred, err := c.getStarredMessage(ctx, "m1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshot := starred.Snapshot
+	if snapshot == nil || snapshot.SenderNickname != "bob" || snapshot.Seq != 1 || snapshot.ContentType != constant.Text {
+		t.Fatalf("snapshot %+v", snapshot)
+	}
+	if utf8.RuneCountInString(snapshot.Text) != starredMessageTextMaxLength+1 || !strings.HasSuffix(snapshot.Text, "…") {
+		t.Fatalf("snapshot text of %d characters", utf8.RuneCountInString(snapshot.Text))
+	}
+	if len(starred.TagList) != 1 || starred.TagList[0] != "work" {
+		t.Fatalf("tags %v", starred.TagList)
+	}
+	starred, err = c.getStarredMessage(ctx, "m2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot := starred.Snapshot; snapshot.FileName != "report.pdf" || snapshot.FileSize != 2048 || snapshot.LinkURL != "https://example.com/report.pdf" {
+		t.Fatalf("file snapshot %+v", snapshot)
+	}
+
+	// the snapshot outlives the message
+	if err := c.db.DeleteConversationMsgs(ctx, conversationID, []string{"m2"}); err != nil {
+		t.Fatal(err)
+	}
+	res, err := c.getStarredMessageList(ctx, &sdk_params_callback.GetStarredMessagesParams{KeywordList: []string{"REPORT"}, Count: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.TotalCount != 1 || res.StarredMessageList[0].ClientMsgID != "m2" {
+		t.Fatalf("keyword search %+v", res)
+	}
+	res, err = c.getStarredMessageList(ctx, &sdk_params_callback.GetStarredMessagesParams{TagList: []string{"work"}, Count: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.TotalCount != 1 || res.StarredMessageList[0].ClientMsgID != "m1" {
+		t.Fatalf("tag filter %+v", res)
+	}
+
+	if err := c.unstarMessage(ctx, "m1"); err != nil {
+		t.Fatal(err)
+	}
+	tags, err := c.getStarredMessageTags(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tags) != 1 || tags[0] != "docs" {
+		t.Fatalf("tags %v after unstarring", tags)
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversation_msg
+
+import (
+	"context"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+	"github.com/openimsdk/tools/log"
+)
+
+func (c *Conversation) getUnreadCountBreakdown(ctx context.Context) (*sdk_struct.UnreadCountBreakdown, error) {
+	groups, err := c.db.GetConversationUnreadCountGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return unreadCountBreakdownOf(groups), nil
+}
+
+// unreadCountBreakdownOf splits the unread counts the same way as the total unread count, muted
+// conversations only count in MutedUnreadCount. A mention is counted whether or not the
+// conversation is muted.
+func unreadCountBreakdownOf(groups []*model_struct.ConversationUnreadCountGroup) *sdk_struct.UnreadCountBreakdown {
+	var breakdown sdk_struct.UnreadCountBreakdown
+	for _, group := range groups {
+		switch group.GroupAtType {
+		case constant.AtMe:
+			breakdown.AtMeConversationCount += group.ConversationCount
+		case constant.AtAll:
+			breakdown.AtAllConversationCount += group.ConversationCount
+		case constant.AtAllAtMe:
+			breakdown.AtMeConversationCount += group.ConversationCount
+			breakdown.AtAllConversationCount += group.ConversationCount
+		}
+		if group.RecvMsgOpt >= constant.ReceiveNotNotifyMessage {
+			breakdown.MutedUnreadCount += group.UnreadCount
+			continue
+		}
+		breakdown.TotalUnreadCount += group.UnreadCount
+		switch group.ConversationType {
+		case constant.SingleChatType:
+			breakdown.SingleUnreadCount += group.UnreadCount
+		case constant.WriteGroupChatType, constant.ReadGroupChatType:
+			breakdown.GroupUnreadCount += group.UnreadCount
+		case constant.NotificationChatType:
+			breakdown.NotificationUnreadCount += group.UnreadCount
+		}
+	}
+	return &breakdown
+}
+
+// unreadCountsChanged reads the unread count groups once for the folder unread counts and the breakdown.
+func (c *Conversation) unreadCountsChanged(ctx context.Context) {
+	groups, err := c.db.GetConversationUnreadCountGroups(ctx)
+	if err != nil {
+		log.ZWarn(ctx, "get conversation unread count groups failed", err)
+		return
+	}
+	c.folderUnreadCountChanged(ctx, groups)
+	c.unreadCountBreakdownChanged(groups)
+}
+
+// unreadCountBreakdownChanged notifies the breakdown if it changed since the last call.
+func (c *Conversation) unreadCountBreakdownChanged(groups []*model_struct.ConversationUnreadCountGroup) {
+	breakdown := unreadCountBreakdownOf(groups)
+	c.unreadBreakdownLock.Lock()
+	changed := c.unreadBreakdown == nil || *c.unreadBreakdown != *breakdown
+	c.unreadBreakdown = breakdown
+	c.unreadBreakdownLock.Unlock()
+	if changed {
+		c.ConversationListener().OnUnreadCountBreakdownChanged(utils.StructToJsonString(breakdown))
+	}
+}
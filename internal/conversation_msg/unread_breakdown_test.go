@@ -0,0 +1,52 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !js
+
+package conversation_msg
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+func TestUnreadCountBreakdown(t *testing.T) {
+	ctx := context.Background()
+	c, listener := newTestConversation(t, "u1")
+	insertUnreadTestConversations(t, c)
+	breakdown, err := c.getUnreadCountBreakdown(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := sdk_struct.UnreadCountBreakdown{TotalUnreadCount: 17, SingleUnreadCount: 4, GroupUnreadCount: 6,
+		NotificationUnreadCount: 7, MutedUnreadCount: 6, AtMeConversationCount: 2, AtAllConversationCount: 2}
+	if *breakdown != want {
+		t.Fatalf("breakdown %+v, want %+v", *breakdown, want)
+	}
+	total, err := c.db.GetTotalUnreadMsgCountDB(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != breakdown.TotalUnreadCount {
+		t.Fatalf("total unread count %d, breakdown %d", total, breakdown.TotalUnreadCount)
+	}
+
+	c.unreadCountsChanged(ctx)
+	c.unreadCountsChanged(ctx)
+	if got := listener.get("OnUnreadCountBreakdownChanged"); len(got) != 1 {
+		t.Fatalf("breakdown callbacks %v", got)
+	}
+}
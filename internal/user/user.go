@@ -118,6 +118,24 @@ func (u *User) initSyncer() {
 			return a.Uuid == b.Uuid && a.Type == b.Type && a.Value == b.Value
 		},
 		func(ctx context.Context, state int, serverCommand *model_struct.LocalUserCommand, localCommand *model_struct.LocalUserCommand) error {
+			command := serverCommand
+			if state == syncer.Delete {
+				command = localCommand
+			}
+			if command != nil {
+				switch command.Type {
+				case constant.SensitiveWordUserCommandType:
+					_ = common.TriggerCmdSensitiveWordsChanged(ctx, u.conversationCh)
+				case constant.ConversationFolderUserCommandType:
+					_ = common.TriggerCmdConversationFoldersChanged(ctx, u.conversationCh)
+				case constant.ConversationDraftUserCommandType:
+					_ = common.TriggerCmdConversationDraftsChanged(ctx, u.conversationCh)
+				case constant.PinnedMessageUserCommandType:
+					_ = common.TriggerCmdPinnedMessagesChanged(ctx, u.conversationCh)
+				case constant.MarkedUnreadUserCommandType:
+					_ = common.TriggerCmdMarkedUnreadChanged(ctx, u.conversationCh)
+				}
+			}
 			if u.listener == nil {
 				return nil
 			}
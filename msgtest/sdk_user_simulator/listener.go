@@ -42,6 +42,26 @@ func (c *conversationCallBack) OnConversationUserInputStatusChanged(change strin
 
 }
 
+func (c *conversationCallBack) OnConversationLiveLocationChanged(change string) {
+
+}
+
+func (c *conversationCallBack) OnConversationPatched(delta string) {
+
+}
+
+func (c *conversationCallBack) OnConversationPinnedMessagesChanged(change string) {
+
+}
+
+func (c *conversationCallBack) OnConversationFolderUnreadCountChanged(folderUnreadCounts string) {
+
+}
+
+func (c *conversationCallBack) OnUnreadCountBreakdownChanged(breakdown string) {
+
+}
+
 type userCallback struct {
 }
 
@@ -121,6 +141,14 @@ func (m *MsgListenerCallBak) OnRecvOnlineOnlyMessage(message string) {
 
 }
 
+func (m *MsgListenerCallBak) OnPollResultChanged(pollResult string) {
+
+}
+
+func (m *MsgListenerCallBak) OnRecvMessageDelivered(deliveryReceiptList string) {
+
+}
+
 type testFriendListener struct {
 }
 
@@ -26,6 +26,66 @@ func GetConversationListSplit(callback open_im_sdk_callback.Base, operationID st
 	call(callback, operationID, UserForSDK.Conversation().GetConversationListSplit, offset, count)
 }
 
+func QueryConversationList(callback open_im_sdk_callback.Base, operationID string, query string) {
+	call(callback, operationID, UserForSDK.Conversation().QueryConversationList, query)
+}
+
+func GetConversationListSplitByFolder(callback open_im_sdk_callback.Base, operationID string, folderID string, offset int, count int) {
+	call(callback, operationID, UserForSDK.Conversation().GetConversationListSplitByFolder, folderID, offset, count)
+}
+
+func CreateConversationFolder(callback open_im_sdk_callback.Base, operationID string, folder string) {
+	call(callback, operationID, UserForSDK.Conversation().CreateConversationFolder, folder)
+}
+
+func UpdateConversationFolder(callback open_im_sdk_callback.Base, operationID string, folder string) {
+	call(callback, operationID, UserForSDK.Conversation().UpdateConversationFolder, folder)
+}
+
+func DeleteConversationFolder(callback open_im_sdk_callback.Base, operationID string, folderID string) {
+	call(callback, operationID, UserForSDK.Conversation().DeleteConversationFolder, folderID)
+}
+
+func GetConversationFolders(callback open_im_sdk_callback.Base, operationID string) {
+	call(callback, operationID, UserForSDK.Conversation().GetConversationFolders)
+}
+
+func GetConversationFolderUnreadCounts(callback open_im_sdk_callback.Base, operationID string) {
+	call(callback, operationID, UserForSDK.Conversation().GetConversationFolderUnreadCounts)
+}
+
+func StarMessage(callback open_im_sdk_callback.Base, operationID string, conversationID string, clientMsgID string, tagList string) {
+	call(callback, operationID, UserForSDK.Conversation().StarMessage, conversationID, clientMsgID, tagList)
+}
+
+func UnstarMessage(callback open_im_sdk_callback.Base, operationID string, clientMsgID string) {
+	call(callback, operationID, UserForSDK.Conversation().UnstarMessage, clientMsgID)
+}
+
+func SetStarredMessageTags(callback open_im_sdk_callback.Base, operationID string, clientMsgID string, tagList string) {
+	call(callback, operationID, UserForSDK.Conversation().SetStarredMessageTags, clientMsgID, tagList)
+}
+
+func GetStarredMessageList(callback open_im_sdk_callback.Base, operationID string, req string) {
+	call(callback, operationID, UserForSDK.Conversation().GetStarredMessageList, req)
+}
+
+func GetStarredMessageTags(callback open_im_sdk_callback.Base, operationID string) {
+	call(callback, operationID, UserForSDK.Conversation().GetStarredMessageTags)
+}
+
+func MuteConversationUntil(callback open_im_sdk_callback.Base, operationID string, conversationID string, untilTime int64) {
+	call(callback, operationID, UserForSDK.Conversation().MuteConversationUntil, conversationID, untilTime)
+}
+
+func SnoozeConversationUntil(callback open_im_sdk_callback.Base, operationID string, conversationID string, untilTime int64) {
+	call(callback, operationID, UserForSDK.Conversation().SnoozeConversationUntil, conversationID, untilTime)
+}
+
+func GetConversationTimerList(callback open_im_sdk_callback.Base, operationID string) {
+	call(callback, operationID, UserForSDK.Conversation().GetConversationTimerList)
+}
+
 func GetOneConversation(callback open_im_sdk_callback.Base, operationID string, sessionType int32, sourceID string) {
 	call(callback, operationID, UserForSDK.Conversation().GetOneConversation, sessionType, sourceID)
 }
@@ -46,9 +106,22 @@ func SetConversationDraft(callback open_im_sdk_callback.Base, operationID string
 	call(callback, operationID, UserForSDK.Conversation().SetConversationDraft, conversationID, draftText)
 }
 
+func SetConversationRichDraft(callback open_im_sdk_callback.Base, operationID string, conversationID string, draft string) {
+	call(callback, operationID, UserForSDK.Conversation().SetConversationRichDraft, conversationID, draft)
+}
+
+func GetConversationRichDraft(callback open_im_sdk_callback.Base, operationID string, conversationID string) {
+	call(callback, operationID, UserForSDK.Conversation().GetConversationRichDraft, conversationID)
+}
+
 func GetTotalUnreadMsgCount(callback open_im_sdk_callback.Base, operationID string) {
 	call(callback, operationID, UserForSDK.Conversation().GetTotalUnreadMsgCount)
 }
+
+func GetUnreadCountBreakdown(callback open_im_sdk_callback.Base, operationID string) {
+	call(callback, operationID, UserForSDK.Conversation().GetUnreadCountBreakdown)
+}
+
 func GetAtAllTag(operationID string) string {
 	return syncCall(operationID, UserForSDK.Conversation().GetAtAllTag)
 
@@ -69,6 +142,9 @@ func CreateLocationMessage(operationID string, description string, longitude, la
 func CreateCustomMessage(operationID string, data, extension string, description string) string {
 	return syncCall(operationID, UserForSDK.Conversation().CreateCustomMessage, data, extension, description)
 }
+func CreatePollMessage(operationID string, question string, optionList string, isMultiple, isAnonymous bool, deadline int64) string {
+	return syncCall(operationID, UserForSDK.Conversation().CreatePollMessage, question, optionList, isMultiple, isAnonymous, deadline)
+}
 func CreateQuoteMessage(operationID string, text string, message string) string {
 	return syncCall(operationID, UserForSDK.Conversation().CreateQuoteMessage, text, message)
 }
@@ -135,6 +211,10 @@ func SendMessageNotOss(callback open_im_sdk_callback.SendMsgCallBack, operationI
 	messageCall(callback, operationID, UserForSDK.Conversation().SendMessageNotOss, message, recvID, groupID, offlinePushInfo, isOnlineOnly)
 }
 
+func ForwardMessages(callback open_im_sdk_callback.Base, operationID string, messages, targets string, mode int32) {
+	call(callback, operationID, UserForSDK.Conversation().ForwardMessages, messages, targets, mode)
+}
+
 func FindMessageList(callback open_im_sdk_callback.Base, operationID string, findMessageOptions string) {
 	call(callback, operationID, UserForSDK.Conversation().FindMessageList, findMessageOptions)
 }
@@ -147,10 +227,46 @@ func GetAdvancedHistoryMessageListReverse(callback open_im_sdk_callback.Base, op
 	call(callback, operationID, UserForSDK.Conversation().GetAdvancedHistoryMessageListReverse, getMessageOptions)
 }
 
+func GetMessageListAroundAnchor(callback open_im_sdk_callback.Base, operationID string, getMessageOptions string) {
+	call(callback, operationID, UserForSDK.Conversation().GetMessageListAroundAnchor, getMessageOptions)
+}
+
 func RevokeMessage(callback open_im_sdk_callback.Base, operationID string, conversationID, clientMsgID string) {
 	call(callback, operationID, UserForSDK.Conversation().RevokeMessage, conversationID, clientMsgID)
 }
 
+func RevokeMessages(callback open_im_sdk_callback.Base, operationID string, conversationID string, clientMsgIDs string) {
+	call(callback, operationID, UserForSDK.Conversation().RevokeMessages, conversationID, clientMsgIDs)
+}
+
+func RevokeUserMessages(callback open_im_sdk_callback.Base, operationID string, conversationID, userID string, startTime, endTime int64) {
+	call(callback, operationID, UserForSDK.Conversation().RevokeUserMessages, conversationID, userID, startTime, endTime)
+}
+
+func PinMessage(callback open_im_sdk_callback.Base, operationID string, conversationID, clientMsgID string) {
+	call(callback, operationID, UserForSDK.Conversation().PinMessage, conversationID, clientMsgID)
+}
+
+func UnpinMessage(callback open_im_sdk_callback.Base, operationID string, conversationID, clientMsgID string) {
+	call(callback, operationID, UserForSDK.Conversation().UnpinMessage, conversationID, clientMsgID)
+}
+
+func GetPinnedMessageList(callback open_im_sdk_callback.Base, operationID string, conversationID string) {
+	call(callback, operationID, UserForSDK.Conversation().GetPinnedMessageList, conversationID)
+}
+
+func SetSensitiveWordFilter(callback open_im_sdk_callback.Base, operationID string, mode int32, wordFilePath string) {
+	call(callback, operationID, UserForSDK.Conversation().SetSensitiveWordFilter, mode, wordFilePath)
+}
+
+func GetLinkPreview(callback open_im_sdk_callback.Base, operationID string, url string) {
+	call(callback, operationID, UserForSDK.Conversation().GetLinkPreview, url)
+}
+
+func VotePoll(callback open_im_sdk_callback.Base, operationID string, conversationID, clientMsgID string, optionIDList string) {
+	call(callback, operationID, UserForSDK.Conversation().VotePoll, conversationID, clientMsgID, optionIDList)
+}
+
 func TypingStatusUpdate(callback open_im_sdk_callback.Base, operationID string, recvID string, msgTip string) {
 	call(callback, operationID, UserForSDK.Conversation().TypingStatusUpdate, recvID, msgTip)
 }
@@ -160,6 +276,14 @@ func MarkConversationMessageAsRead(callback open_im_sdk_callback.Base, operation
 	call(callback, operationID, UserForSDK.Conversation().MarkConversationMessageAsRead, conversationID)
 }
 
+func MarkConversationAsUnread(callback open_im_sdk_callback.Base, operationID string, conversationID string) {
+	call(callback, operationID, UserForSDK.Conversation().MarkConversationAsUnread, conversationID)
+}
+
+func GetMessageReadMembers(callback open_im_sdk_callback.Base, operationID string, conversationID, clientMsgID string) {
+	call(callback, operationID, UserForSDK.Conversation().GetMessageReadMembers, conversationID, clientMsgID)
+}
+
 func MarkMessagesAsReadByMsgID(callback open_im_sdk_callback.Base, operationID string, conversationID string, clientMsgIDs string) {
 	call(callback, operationID, UserForSDK.Conversation().MarkMessagesAsReadByMsgID, conversationID, clientMsgIDs)
 }
@@ -172,6 +296,10 @@ func DeleteMessage(callback open_im_sdk_callback.Base, operationID string, conve
 	call(callback, operationID, UserForSDK.Conversation().DeleteMessage, conversationID, clientMsgID)
 }
 
+func DeleteMessages(callback open_im_sdk_callback.Base, operationID string, conversationID string, clientMsgIDs string, forEveryone bool) {
+	call(callback, operationID, UserForSDK.Conversation().DeleteMessages, conversationID, clientMsgIDs, forEveryone)
+}
+
 func HideAllConversations(callback open_im_sdk_callback.Base, operationID string) {
 	call(callback, operationID, UserForSDK.Conversation().HideAllConversations)
 }
@@ -203,6 +331,19 @@ func InsertGroupMessageToLocalStorage(callback open_im_sdk_callback.Base, operat
 func SearchLocalMessages(callback open_im_sdk_callback.Base, operationID string, searchParam string) {
 	call(callback, operationID, UserForSDK.Conversation().SearchLocalMessages, searchParam)
 }
+
+func GlobalSearch(callback open_im_sdk_callback.Base, operationID string, searchParam string) {
+	call(callback, operationID, UserForSDK.Conversation().GlobalSearch, searchParam)
+}
+
+func ExportConversationHistory(callback open_im_sdk_callback.Base, operationID string, exportParam string) {
+	call(callback, operationID, UserForSDK.Conversation().ExportConversationHistory, exportParam)
+}
+
+func ImportConversationHistory(callback open_im_sdk_callback.Base, operationID string, archivePath string) {
+	call(callback, operationID, UserForSDK.Conversation().ImportConversationHistory, archivePath)
+}
+
 func SetMessageLocalEx(callback open_im_sdk_callback.Base, operationID string, conversationID, clientMsgID, localEx string) {
 	call(callback, operationID, UserForSDK.Conversation().SetMessageLocalEx, conversationID, clientMsgID, localEx)
 }
@@ -218,3 +359,31 @@ func ChangeInputStates(callback open_im_sdk_callback.Base, operationID string, c
 func GetInputStates(callback open_im_sdk_callback.Base, operationID string, conversationID string, userID string) {
 	call(callback, operationID, UserForSDK.Conversation().GetInputStates, conversationID, userID)
 }
+
+func ChangeInputActivity(callback open_im_sdk_callback.Base, operationID string, conversationID string, activity int32) {
+	call(callback, operationID, UserForSDK.Conversation().ChangeInputActivity, conversationID, activity)
+}
+
+func GetConversationInputStates(callback open_im_sdk_callback.Base, operationID string, conversationID string) {
+	call(callback, operationID, UserForSDK.Conversation().GetConversationInputStates, conversationID)
+}
+
+func StartLiveLocation(callback open_im_sdk_callback.Base, operationID string, conversationID string, duration int64, longitude, latitude, accuracy float64) {
+	call(callback, operationID, UserForSDK.Conversation().StartLiveLocation, conversationID, duration, longitude, latitude, accuracy)
+}
+
+func UpdateLiveLocation(callback open_im_sdk_callback.Base, operationID string, conversationID string, longitude, latitude, accuracy float64) {
+	call(callback, operationID, UserForSDK.Conversation().UpdateLiveLocation, conversationID, longitude, latitude, accuracy)
+}
+
+func StopLiveLocation(callback open_im_sdk_callback.Base, operationID string, conversationID string) {
+	call(callback, operationID, UserForSDK.Conversation().StopLiveLocation, conversationID)
+}
+
+func GetLiveLocations(callback open_im_sdk_callback.Base, operationID string, conversationID string) {
+	call(callback, operationID, UserForSDK.Conversation().GetLiveLocations, conversationID)
+}
+
+func SetConversationDeltaMode(callback open_im_sdk_callback.Base, operationID string, enabled bool, coalesceWindow int64) {
+	call(callback, operationID, UserForSDK.Conversation().SetConversationDeltaMode, enabled, coalesceWindow)
+}
@@ -183,6 +183,31 @@ func (e *emptyConversationListener) OnConversationUserInputStatusChanged(change
 
 }
 
+func (e *emptyConversationListener) OnConversationLiveLocationChanged(change string) {
+	log.ZWarn(e.ctx, "ConversationListener is not implemented", nil,
+		"change", change)
+}
+
+func (e *emptyConversationListener) OnConversationPatched(delta string) {
+	log.ZWarn(e.ctx, "ConversationListener is not implemented", nil,
+		"delta", delta)
+}
+
+func (e *emptyConversationListener) OnConversationPinnedMessagesChanged(change string) {
+	log.ZWarn(e.ctx, "ConversationListener is not implemented", nil,
+		"change", change)
+}
+
+func (e *emptyConversationListener) OnConversationFolderUnreadCountChanged(folderUnreadCounts string) {
+	log.ZWarn(e.ctx, "ConversationListener is not implemented", nil,
+		"folderUnreadCounts", folderUnreadCounts)
+}
+
+func (e *emptyConversationListener) OnUnreadCountBreakdownChanged(breakdown string) {
+	log.ZWarn(e.ctx, "ConversationListener is not implemented", nil,
+		"breakdown", breakdown)
+}
+
 type emptyAdvancedMsgListener struct {
 	ctx context.Context
 }
@@ -195,6 +220,14 @@ func (e *emptyAdvancedMsgListener) OnRecvOnlineOnlyMessage(message string) {
 
 }
 
+func (e *emptyAdvancedMsgListener) OnPollResultChanged(pollResult string) {
+	log.ZWarn(e.ctx, "AdvancedMsgListener is not implemented", nil, "pollResult", pollResult)
+}
+
+func (e *emptyAdvancedMsgListener) OnRecvMessageDelivered(deliveryReceiptList string) {
+	log.ZWarn(e.ctx, "AdvancedMsgListener is not implemented", nil, "deliveryReceiptList", deliveryReceiptList)
+}
+
 func (e *emptyAdvancedMsgListener) OnRecvNewMessage(message string) {
 	log.ZWarn(e.ctx, "AdvancedMsgListener is not implemented", nil, "message", message)
 }
@@ -251,6 +284,10 @@ func (e *emptyBatchMsgListener) OnRecvOfflineNewMessages(messageList string) {
 
 }
 
+func (e *emptyBatchMsgListener) OnRecvMessagesRevoked(messageRevokedList string) {
+
+}
+
 type emptyUserListener struct {
 	ctx context.Context
 }
@@ -180,6 +180,9 @@ func (u *LoginMgr) ImConfig() sdk_struct.IMConfig {
 		DataDir:              u.info.DataDir,
 		LogLevel:             u.info.LogLevel,
 		IsExternalExtensions: u.info.IsExternalExtensions,
+		EnableLinkPreview:    u.info.EnableLinkPreview,
+		OfflinePushLanguage:  u.info.OfflinePushLanguage,
+		OfflinePushTemplates: u.info.OfflinePushTemplates,
 	}
 }
 
@@ -442,6 +445,9 @@ func (u *LoginMgr) logout(ctx context.Context, isTokenValid bool) error {
 			log.ZDebug(ctx, "TriggerCmdLogout server recycle resources success...")
 		}
 	}
+	if u.conversation != nil {
+		u.conversation.Close(ctx)
+	}
 	u.Exit()
 	err := u.db.Close(u.ctx)
 	if err != nil {
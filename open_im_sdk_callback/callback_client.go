@@ -65,20 +65,30 @@ type OnConversationListener interface {
 	OnConversationChanged(conversationList string)
 	OnTotalUnreadMessageCountChanged(totalUnreadCount int32)
 	OnConversationUserInputStatusChanged(change string)
+	OnConversationLiveLocationChanged(change string)
+	OnConversationPinnedMessagesChanged(change string)
+	OnConversationFolderUnreadCountChanged(folderUnreadCounts string)
+	OnUnreadCountBreakdownChanged(breakdown string)
+	// OnConversationPatched replaces OnNewConversation and OnConversationChanged once the delta mode is enabled.
+	OnConversationPatched(delta string)
 }
 
 type OnAdvancedMsgListener interface {
 	OnRecvNewMessage(message string)
 	OnRecvC2CReadReceipt(msgReceiptList string)
+	OnRecvGroupReadReceipt(groupMsgReceiptList string)
 	OnNewRecvMessageRevoked(messageRevoked string)
 	OnRecvOfflineNewMessage(message string)
 	OnMsgDeleted(message string)
 	OnRecvOnlineOnlyMessage(message string)
+	OnPollResultChanged(pollResult string)
+	OnRecvMessageDelivered(deliveryReceiptList string)
 }
 
 type OnBatchMsgListener interface {
 	OnRecvNewMessages(messageList string)
 	OnRecvOfflineNewMessages(messageList string)
+	OnRecvMessagesRevoked(messageRevokedList string)
 }
 
 type OnUserListener interface {
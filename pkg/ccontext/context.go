@@ -44,6 +44,9 @@ type ContextInfo interface {
 	LogLevel() uint32
 	OperationID() string
 	IsExternalExtensions() bool
+	EnableLinkPreview() bool
+	OfflinePushLanguage() string
+	OfflinePushTemplates() map[string]*sdk_struct.OfflinePushTemplate
 }
 
 func Info(ctx context.Context) ContextInfo {
@@ -120,6 +123,18 @@ func (i *info) IsExternalExtensions() bool {
 	return i.conf.IsExternalExtensions
 }
 
+func (i *info) EnableLinkPreview() bool {
+	return i.conf.EnableLinkPreview
+}
+
+func (i *info) OfflinePushLanguage() string {
+	return i.conf.OfflinePushLanguage
+}
+
+func (i *info) OfflinePushTemplates() map[string]*sdk_struct.OfflinePushTemplate {
+	return i.conf.OfflinePushTemplates
+}
+
 type apiErrCode struct{}
 
 type ApiErrCodeCallback interface {
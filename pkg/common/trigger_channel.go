@@ -106,6 +106,54 @@ func TriggerCmdUpdateMessage(ctx context.Context, node UpdateMessageNode, conver
 	return sendCmd(conversationCh, c2v, timeOut)
 }
 
+func TriggerCmdSensitiveWordsChanged(ctx context.Context, conversationCh chan Cmd2Value) error {
+	if conversationCh == nil {
+		return errs.Wrap(ErrChanNil)
+	}
+	c2v := Cmd2Value{Cmd: constant.CmdSensitiveWordsChanged, Ctx: ctx}
+	return sendCmd(conversationCh, c2v, timeOut)
+}
+
+func TriggerCmdConversationFoldersChanged(ctx context.Context, conversationCh chan Cmd2Value) error {
+	if conversationCh == nil {
+		return errs.Wrap(ErrChanNil)
+	}
+	c2v := Cmd2Value{Cmd: constant.CmdConversationFoldersChanged, Ctx: ctx}
+	return sendCmd(conversationCh, c2v, timeOut)
+}
+
+func TriggerCmdConversationTimerExpired(ctx context.Context, conversationCh chan Cmd2Value) error {
+	if conversationCh == nil {
+		return errs.Wrap(ErrChanNil)
+	}
+	c2v := Cmd2Value{Cmd: constant.CmdConversationTimerExpired, Ctx: ctx}
+	return sendCmd(conversationCh, c2v, timeOut)
+}
+
+func TriggerCmdConversationDraftsChanged(ctx context.Context, conversationCh chan Cmd2Value) error {
+	if conversationCh == nil {
+		return errs.Wrap(ErrChanNil)
+	}
+	c2v := Cmd2Value{Cmd: constant.CmdConversationDraftsChanged, Ctx: ctx}
+	return sendCmd(conversationCh, c2v, timeOut)
+}
+
+func TriggerCmdPinnedMessagesChanged(ctx context.Context, conversationCh chan Cmd2Value) error {
+	if conversationCh == nil {
+		return errs.Wrap(ErrChanNil)
+	}
+	c2v := Cmd2Value{Cmd: constant.CmdPinnedMessagesChanged, Ctx: ctx}
+	return sendCmd(conversationCh, c2v, timeOut)
+}
+
+func TriggerCmdMarkedUnreadChanged(ctx context.Context, conversationCh chan Cmd2Value) error {
+	if conversationCh == nil {
+		return errs.Wrap(ErrChanNil)
+	}
+	c2v := Cmd2Value{Cmd: constant.CmdMarkedUnreadChanged, Ctx: ctx}
+	return sendCmd(conversationCh, c2v, timeOut)
+}
+
 // Push message, msg for msgData slice
 func TriggerCmdPushMsg(ctx context.Context, msg *sdkws.PushMessages, ch chan Cmd2Value) error {
 	if ch == nil {
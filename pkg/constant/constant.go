@@ -36,6 +36,13 @@ const (
 	CmdJoinedSuperGroup = "018"
 	CmdUpdateMessage    = "updateMessage"
 
+	CmdSensitiveWordsChanged      = "sensitiveWordsChanged"
+	CmdConversationFoldersChanged = "conversationFoldersChanged"
+	CmdConversationTimerExpired   = "conversationTimerExpired"
+	CmdConversationDraftsChanged  = "conversationDraftsChanged"
+	CmdPinnedMessagesChanged      = "pinnedMessagesChanged"
+	CmdMarkedUnreadChanged        = "markedUnreadChanged"
+
 	CmdReconnect = "020"
 	CmdInit      = "021"
 
@@ -64,6 +71,67 @@ const (
 	AdvancedText                    = 117
 	CustomMsgNotTriggerConversation = 119
 	CustomMsgOnlineOnly             = 120
+	Poll                            = 123
+	DeliveryReceipt                 = 124
+	LiveLocation                    = 125
+
+	// MessageEntity type of the links found in text messages
+	MessageEntityTypeURL = "url"
+
+	// SensitiveWordUserCommandType is the user command type whose values are comma separated sensitive words
+	SensitiveWordUserCommandType = 10
+	// sensitive word filter mode applied before sending
+	SensitiveWordFilterOff   = 0
+	SensitiveWordFilterBlock = 1
+	SensitiveWordFilterMask  = 2
+	SensitiveWordFilterFlag  = 3
+
+	// ConversationFolderUserCommandType is the user command type whose values are conversation folders
+	ConversationFolderUserCommandType = 11
+	// conversation folder filter on the muted and unread state
+	ConversationFolderFilterAny     = 0
+	ConversationFolderFilterOnly    = 1
+	ConversationFolderFilterExclude = 2
+	// conversation list query filter on the pinned and muted state
+	ConversationQueryFilterAny     = 0
+	ConversationQueryFilterOnly    = 1
+	ConversationQueryFilterExclude = 2
+	// conversation list query sort options, the latest message or draft and unread count descending, the name ascending
+	ConversationSortByLatestMsg   = 0
+	ConversationSortByUnreadCount = 1
+	ConversationSortByName        = 2
+	// ConversationQueryMaxCount is the largest page of a conversation list query
+	ConversationQueryMaxCount = 500
+
+	// ConversationDraftUserCommandType is the user command type whose values are conversation drafts
+	ConversationDraftUserCommandType = 12
+
+	// StarredMessageUserCommandType is the user command type whose values are starred messages
+	StarredMessageUserCommandType = 13
+
+	// PinnedMessageUserCommandType is the user command type whose values are the pinned messages
+	// known to the user, restoring the pins older than the synced history on a new device
+	PinnedMessageUserCommandType = 14
+
+	// MarkedUnreadUserCommandType is the user command type whose uuids are the conversations marked as unread
+	MarkedUnreadUserCommandType = 15
+
+	// keys of the offline push description templates
+	OfflinePushDescText     = "text"
+	OfflinePushDescPicture  = "picture"
+	OfflinePushDescVoice    = "voice"
+	OfflinePushDescVideo    = "video"
+	OfflinePushDescFile     = "file"
+	OfflinePushDescLocation = "location"
+	OfflinePushDescMention  = "mention"
+	OfflinePushDescCard     = "card"
+	OfflinePushDescMerger   = "merger"
+	OfflinePushDescFace     = "face"
+	OfflinePushDescPoll     = "poll"
+	OfflinePushDescCustom   = "custom"
+	OfflinePushDescDefault  = "default"
+	// OfflinePushDescHidden is used for private chats and encrypted messages, it must not contain {text}
+	OfflinePushDescHidden = "hidden"
 
 	NotificationBegin = 1000
 
@@ -126,6 +194,10 @@ const (
 
 	HasReadReceipt = 2200
 
+	PinnedMessageNotification = 2301
+	PollVoteNotification      = 2302
+	PollResultNotification    = 2303
+
 	NotificationEnd = 5000
 	////////////////////////////////////////
 
@@ -146,6 +218,8 @@ const (
 	MsgStatusSendFailed  = 3
 	MsgStatusHasDeleted  = 4
 	MsgStatusFiltered    = 5
+	// MsgStatusDelivered is a sent message that reached a device of a recipient
+	MsgStatusDelivered = 6
 
 	//OptionsKey
 	IsHistory                  = "history"
@@ -253,6 +327,38 @@ const (
 	KeywordMatchAnd = 1 // Keyword match mode: match all keywords
 )
 
+const (
+	GlobalSearchSectionMessage      = "message"
+	GlobalSearchSectionFriend       = "friend"
+	GlobalSearchSectionGroup        = "group"
+	GlobalSearchSectionGroupMember  = "groupMember"
+	GlobalSearchSectionConversation = "conversation"
+)
+
+const (
+	ForwardModeOneByOne = 0 // Forward mode: send a copy of each message
+	ForwardModeMerge    = 1 // Forward mode: bundle the messages into one merger message
+)
+
+const (
+	// GroupReadReceiptMaxMemberCount is the largest group whose messages track who has read them
+	GroupReadReceiptMaxMemberCount = 200
+)
+
+// BatchMessageOperationMaxCount is the largest number of messages revoked or deleted in one call
+const BatchMessageOperationMaxCount = 500
+
+const (
+	InputActivityNone           = 0 // Input activity: stopped, the input area lost focus
+	InputActivityTyping         = 1 // Input activity: typing text
+	InputActivityRecordingVoice = 2 // Input activity: recording a voice message
+	InputActivityChoosingMedia  = 3 // Input activity: choosing a picture or video
+	InputActivityUploadingFile  = 4 // Input activity: uploading a file
+
+	// GroupInputStatesMaxMemberCount is the largest group that input states are broadcast to
+	GroupInputStatesMaxMemberCount = 500
+)
+
 const BigVersion = "v3"
 
 const (
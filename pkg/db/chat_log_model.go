@@ -74,6 +74,11 @@ func (d *DataBase) initChatLog(ctx context.Context, conversationID string) error
 		if result.Error != nil {
 			return errs.WrapMsg(result.Error, "Create index_send_time failed", "table", tableName, "index", "index_send_time_"+conversationID)
 		}
+		if d.tableChecker.HasTable(messageSearchTable) {
+			if err := d.createMessageSearchTriggers(ctx, conversationID); err != nil {
+				return err
+			}
+		}
 		d.tableChecker.UpdateTable(tableName)
 	}
 	return nil
@@ -221,10 +226,13 @@ func (d *DataBase) DeleteConversationMsgsBySeqs(ctx context.Context, conversatio
 	return errs.WrapMsg(d.conn.WithContext(ctx).Table(utils.GetTableName(conversationID)).Where("seq IN ?", seqs).Delete(model_struct.LocalChatLog{}).Error, "DeleteConversationMsgs failed")
 }
 
+// shownStatusCondition matches the messages neither deleted nor filtered, the delivered ones included.
+var shownStatusCondition = fmt.Sprintf("(status <= %d OR status = %d)", constant.MsgStatusSendFailed, constant.MsgStatusDelivered)
+
 func (d *DataBase) SearchMessageByContentType(ctx context.Context, contentType []int, conversationID string, startTime, endTime int64, offset, count int) (result []*model_struct.LocalChatLog, err error) {
 	d.mRWMutex.RLock()
 	defer d.mRWMutex.RUnlock()
-	condition := fmt.Sprintf("send_time between %d and %d AND status <=%d And content_type IN ?", startTime, endTime, constant.MsgStatusSendFailed)
+	condition := fmt.Sprintf("send_time between %d and %d AND %s And content_type IN ?", startTime, endTime, shownStatusCondition)
 	err = errs.WrapMsg(d.conn.WithContext(ctx).Table(utils.GetTableName(conversationID)).Where(condition, contentType).Order("send_time DESC").Offset(offset).Limit(count).Find(&result).Error, "SearchMessage failed")
 	return result, err
 }
@@ -257,7 +265,7 @@ func (d *DataBase) SearchMessageByKeyword(ctx context.Context, contentType []int
 			}
 		}
 	}
-	condition = fmt.Sprintf(" send_time  between %d and %d AND status <=%d  And content_type IN ? ", startTime, endTime, constant.MsgStatusSendFailed)
+	condition = fmt.Sprintf(" send_time  between %d and %d AND %s And content_type IN ? ", startTime, endTime, shownStatusCondition)
 	condition += subCondition
 	err = errs.WrapMsg(d.conn.WithContext(ctx).Table(utils.GetTableName(conversationID)).Where(condition, contentType).Order("send_time DESC").Offset(offset).Limit(count).Find(&result).Error, "SearchMessage failed")
 	return result, err
@@ -298,7 +306,7 @@ func (d *DataBase) SearchMessageByContentTypeAndKeyword(ctx context.Context, con
 	}
 
 	// Construct the main SQL condition string
-	condition = fmt.Sprintf("send_time between %d and %d AND status <=%d  And content_type IN ? ", startTime, endTime, constant.MsgStatusSendFailed)
+	condition = fmt.Sprintf("send_time between %d and %d AND %s And content_type IN ? ", startTime, endTime, shownStatusCondition)
 	condition += subCondition
 
 	// Execute the query using the constructed condition and handle errors
@@ -401,6 +409,20 @@ func (d *DataBase) GetMessagesByClientMsgIDs(ctx context.Context, conversationID
 	return msgs, err
 }
 
+func (d *DataBase) GetMessagesBySendID(ctx context.Context, conversationID, sendID string, startTime, endTime int64) (msgs []*model_struct.LocalChatLog, err error) {
+	d.mRWMutex.RLock()
+	defer d.mRWMutex.RUnlock()
+	err = errs.WrapMsg(d.conn.WithContext(ctx).Table(utils.GetConversationTableName(conversationID)).Where("send_id = ? AND send_time BETWEEN ? AND ?", sendID, startTime, endTime).Order("send_time ASC").Find(&msgs).Error, "GetMessagesBySendID error")
+	return msgs, err
+}
+
+func (d *DataBase) GetMessagesBySendIDSeqRange(ctx context.Context, conversationID, sendID string, startSeq, endSeq int64) (msgs []*model_struct.LocalChatLog, err error) {
+	d.mRWMutex.RLock()
+	defer d.mRWMutex.RUnlock()
+	err = errs.WrapMsg(d.conn.WithContext(ctx).Table(utils.GetConversationTableName(conversationID)).Where("send_id = ? AND seq > ? AND seq <= ?", sendID, startSeq, endSeq).Order("seq ASC").Find(&msgs).Error, "GetMessagesBySendIDSeqRange error")
+	return msgs, err
+}
+
 func (d *DataBase) GetMessagesBySeqs(ctx context.Context, conversationID string, seqs []int64) (msgs []*model_struct.LocalChatLog, err error) {
 	d.mRWMutex.RLock()
 	defer d.mRWMutex.RUnlock()
@@ -464,8 +486,8 @@ func (d *DataBase) GetLatestActiveMessage(ctx context.Context, conversationID st
 		timeOrder = "send_time DESC"
 	}
 
-	// only get status < 4(NotHasDeleted) Msg
-	err = errs.WrapMsg(d.conn.WithContext(ctx).Table(utils.GetTableName(conversationID)).Where("status < ?", constant.MsgStatusHasDeleted).Order(timeOrder).Offset(0).Limit(1).Find(&result).Error, "GetMessageList failed")
+	// only get the messages not deleted nor filtered
+	err = errs.WrapMsg(d.conn.WithContext(ctx).Table(utils.GetTableName(conversationID)).Where(shownStatusCondition).Order(timeOrder).Offset(0).Limit(1).Find(&result).Error, "GetMessageList failed")
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,52 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/tools/utils/datautil"
+)
+
+func Test_ShownStatusCondition(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewDataBase(ctx, "1695766238", "./", 6)
+	if err != nil {
+		return
+	}
+	conversationID := "si_1695766238_shown_status"
+	if err := db.initChatLog(ctx, conversationID); err != nil {
+		t.Fatal(err)
+	}
+	statuses := map[string]int32{
+		"shown_sent":      constant.MsgStatusSendSuccess,
+		"shown_delivered": constant.MsgStatusDelivered,
+		"shown_failed":    constant.MsgStatusSendFailed,
+		"shown_deleted":   constant.MsgStatusHasDeleted,
+		"shown_filtered":  constant.MsgStatusFiltered,
+	}
+	var sendTime int64
+	for clientMsgID, status := range statuses {
+		sendTime += 1000
+		message := &model_struct.LocalChatLog{ClientMsgID: clientMsgID, ContentType: constant.Text, Content: `{"content":"hello"}`, Status: status, SendTime: sendTime}
+		if err := db.InsertMessage(ctx, conversationID, message); err != nil {
+			t.Fatal(err)
+		}
+	}
+	list, err := db.SearchMessageByContentType(ctx, []int{constant.Text}, conversationID, 0, sendTime, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shown := datautil.Slice(list, func(message *model_struct.LocalChatLog) string { return message.ClientMsgID })
+	for _, clientMsgID := range []string{"shown_sent", "shown_delivered", "shown_failed"} {
+		if !datautil.Contain(clientMsgID, shown...) {
+			t.Fatalf("%s is not shown in %v", clientMsgID, shown)
+		}
+	}
+	for _, clientMsgID := range []string{"shown_deleted", "shown_filtered"} {
+		if datautil.Contain(clientMsgID, shown...) {
+			t.Fatalf("%s is shown in %v", clientMsgID, shown)
+		}
+	}
+}
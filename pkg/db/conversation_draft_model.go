@@ -0,0 +1,57 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !js
+// +build !js
+
+package db
+
+import (
+	"context"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/tools/errs"
+	"gorm.io/gorm"
+)
+
+func (d *DataBase) SaveRichDraft(ctx context.Context, draft *model_struct.LocalConversationDraft) error {
+	d.mRWMutex.Lock()
+	defer d.mRWMutex.Unlock()
+	return errs.WrapMsg(d.conn.WithContext(ctx).Save(draft).Error, "SaveRichDraft failed")
+}
+
+func (d *DataBase) GetRichDraft(ctx context.Context, conversationID string) (*model_struct.LocalConversationDraft, error) {
+	d.mRWMutex.RLock()
+	defer d.mRWMutex.RUnlock()
+	var draft model_struct.LocalConversationDraft
+	err := d.conn.WithContext(ctx).Where("conversation_id = ?", conversationID).Take(&draft).Error
+	if err == gorm.ErrRecordNotFound {
+		err = errs.ErrRecordNotFound
+	}
+	return &draft, errs.WrapMsg(err, "GetRichDraft failed")
+}
+
+func (d *DataBase) DeleteRichDraft(ctx context.Context, conversationID string) error {
+	d.mRWMutex.Lock()
+	defer d.mRWMutex.Unlock()
+	return errs.WrapMsg(d.conn.WithContext(ctx).Where("conversation_id = ?", conversationID).Delete(&model_struct.LocalConversationDraft{}).Error,
+		"DeleteRichDraft failed")
+}
+
+func (d *DataBase) DeleteAllRichDrafts(ctx context.Context) error {
+	d.mRWMutex.Lock()
+	defer d.mRWMutex.Unlock()
+	return errs.WrapMsg(d.conn.WithContext(ctx).Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&model_struct.LocalConversationDraft{}).Error,
+		"DeleteAllRichDrafts failed")
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
 
 	"gorm.io/gorm"
 
@@ -36,6 +37,10 @@ const (
 	batchSize = 200
 )
 
+// unreadCountColumn is the unread count of a conversation, a conversation marked as unread
+// without unread messages counts as one.
+const unreadCountColumn = "(CASE WHEN unread_count = 0 AND is_marked_unread THEN 1 ELSE unread_count END)"
+
 func (d *DataBase) GetConversationByUserID(ctx context.Context, userID string) (*model_struct.LocalConversation, error) {
 	d.mRWMutex.RLock()
 	defer d.mRWMutex.RUnlock()
@@ -91,6 +96,13 @@ func (d *DataBase) GetAllSingleConversationIDList(ctx context.Context) (result [
 	return result, errs.WrapMsg(d.conn.WithContext(ctx).Model(&c).Where("conversation_type = ?", constant.SingleChatType).Pluck("conversation_id", &result).Error, "GetAllConversationIDList failed ")
 }
 
+func (d *DataBase) GetMarkedUnreadConversationIDList(ctx context.Context) (result []string, err error) {
+	d.mRWMutex.RLock()
+	defer d.mRWMutex.RUnlock()
+	var c model_struct.LocalConversation
+	return result, errs.WrapMsg(d.conn.WithContext(ctx).Model(&c).Where("is_marked_unread = ?", true).Pluck("conversation_id", &result).Error, "GetMarkedUnreadConversationIDList failed ")
+}
+
 func (d *DataBase) GetConversationListSplitDB(ctx context.Context, offset, count int) ([]*model_struct.LocalConversation, error) {
 	d.mRWMutex.RLock()
 	defer d.mRWMutex.RUnlock()
@@ -98,6 +110,70 @@ func (d *DataBase) GetConversationListSplitDB(ctx context.Context, offset, count
 	return conversationList, errs.Wrap(d.conn.WithContext(ctx).Where("latest_msg_send_time > ?", 0).Order("case when is_pinned=1 then 0 else 1 end,max(latest_msg_send_time,draft_text_time) DESC").Offset(offset).Limit(count).Find(&conversationList).Error)
 }
 
+// QueryConversationList pages through the visible conversations matching the query, starting after the cursor
+// when it is not nil. The conversation ID breaks the ties of the sort key, keeping the pages stable.
+func (d *DataBase) QueryConversationList(ctx context.Context, query *sdk_struct.ConversationQuery, cursor *sdk_struct.ConversationCursor, count int) ([]*model_struct.LocalConversation, error) {
+	d.mRWMutex.RLock()
+	defer d.mRWMutex.RUnlock()
+	db := d.conn.WithContext(ctx).Where("latest_msg_send_time > ?", 0)
+	if len(query.SessionTypeList) > 0 {
+		db = db.Where("conversation_type IN ?", query.SessionTypeList)
+	}
+	if len(query.GroupIDList) > 0 {
+		db = db.Where("group_id IN ?", query.GroupIDList)
+	}
+	if query.UnreadOnly {
+		db = db.Where("(unread_count > ? OR is_marked_unread = ?)", 0, true)
+	}
+	if query.MentionOnly {
+		db = db.Where("group_at_type IN ?", []int32{constant.AtMe, constant.AtAll, constant.AtAllAtMe})
+	}
+	if query.HasDraft {
+		db = db.Where("draft_text <> ?", "")
+	}
+	switch query.PinnedFilter {
+	case constant.ConversationQueryFilterOnly:
+		db = db.Where("is_pinned = ?", true)
+	case constant.ConversationQueryFilterExclude:
+		db = db.Where("is_pinned = ?", false)
+	}
+	switch query.MutedFilter {
+	case constant.ConversationQueryFilterOnly:
+		db = db.Where("recv_msg_opt <> ?", constant.ReceiveMessage)
+	case constant.ConversationQueryFilterExclude:
+		db = db.Where("recv_msg_opt = ?", constant.ReceiveMessage)
+	}
+	var column, direction, symbol string
+	var sortValue any
+	switch query.SortBy {
+	case constant.ConversationSortByUnreadCount:
+		column, direction, symbol = "unread_count", "DESC", "<"
+	case constant.ConversationSortByName:
+		column, direction, symbol = "show_name", "ASC", ">"
+	default:
+		// the same key as GetConversationListSplitDB, a new draft moves the conversation up
+		column, direction, symbol = "max(latest_msg_send_time,draft_text_time)", "DESC", "<"
+	}
+	if cursor != nil {
+		sortValue = cursor.SortKey
+		if query.SortBy == constant.ConversationSortByName {
+			sortValue = cursor.SortName
+		}
+		after := d.conn.Where(column+" "+symbol+" ?", sortValue).
+			Or(d.conn.Where(column+" = ?", sortValue).Where("conversation_id > ?", cursor.ConversationID))
+		if query.PinnedFirst {
+			after = d.conn.Where("is_pinned < ?", cursor.IsPinned).Or(d.conn.Where("is_pinned = ?", cursor.IsPinned).Where(after))
+		}
+		db = db.Where(after)
+	}
+	if query.PinnedFirst {
+		db = db.Order("is_pinned DESC")
+	}
+	var conversationList []*model_struct.LocalConversation
+	return conversationList, errs.WrapMsg(db.Order(column+" "+direction).Order("conversation_id ASC").Limit(count).Find(&conversationList).Error,
+		"QueryConversationList failed")
+}
+
 func (d *DataBase) BatchInsertConversationList(ctx context.Context, conversationList []*model_struct.LocalConversation) error {
 	if conversationList == nil {
 		return nil
@@ -280,6 +356,19 @@ func (d *DataBase) SetConversationDraftDB(ctx context.Context, conversationID, d
 	return errs.WrapMsg(t.Error, "SetConversationDraft failed")
 }
 
+// UpdateConversationDraftText sets the draft text only, unlike SetConversationDraftDB it does not
+// show a hidden conversation again.
+func (d *DataBase) UpdateConversationDraftText(ctx context.Context, conversationID, draftText string, draftTextTime int64) error {
+	d.mRWMutex.Lock()
+	defer d.mRWMutex.Unlock()
+	c := model_struct.LocalConversation{ConversationID: conversationID, DraftText: draftText, DraftTextTime: draftTextTime}
+	t := d.conn.WithContext(ctx).Select("draft_text", "draft_text_time").Updates(c)
+	if t.RowsAffected == 0 {
+		return errs.WrapMsg(errors.New("RowsAffected == 0"), "no update")
+	}
+	return errs.WrapMsg(t.Error, "UpdateConversationDraftText failed")
+}
+
 func (d *DataBase) RemoveConversationDraft(ctx context.Context, conversationID, draftText string) error {
 	d.mRWMutex.Lock()
 	defer d.mRWMutex.Unlock()
@@ -340,7 +429,7 @@ func (d *DataBase) GetTotalUnreadMsgCountDB(ctx context.Context) (totalUnreadCou
 	d.mRWMutex.RLock()
 	defer d.mRWMutex.RUnlock()
 	var result []int64
-	err = d.conn.WithContext(ctx).Model(&model_struct.LocalConversation{}).Where("recv_msg_opt < ? and latest_msg_send_time > ?", constant.ReceiveNotNotifyMessage, 0).Pluck("unread_count", &result).Error
+	err = d.conn.WithContext(ctx).Model(&model_struct.LocalConversation{}).Where("recv_msg_opt < ? and latest_msg_send_time > ?", constant.ReceiveNotNotifyMessage, 0).Pluck(unreadCountColumn, &result).Error
 	if err != nil {
 		return totalUnreadCount, errs.WrapMsg(errors.New("GetTotalUnreadMsgCount err"), "GetTotalUnreadMsgCount err")
 	}
@@ -350,6 +439,17 @@ func (d *DataBase) GetTotalUnreadMsgCountDB(ctx context.Context) (totalUnreadCou
 	return totalUnreadCount, nil
 }
 
+func (d *DataBase) GetConversationUnreadCountGroups(ctx context.Context) ([]*model_struct.ConversationUnreadCountGroup, error) {
+	d.mRWMutex.RLock()
+	defer d.mRWMutex.RUnlock()
+	var groups []*model_struct.ConversationUnreadCountGroup
+	err := d.conn.WithContext(ctx).Model(&model_struct.LocalConversation{}).
+		Select("conversation_type, recv_msg_opt, group_at_type, SUM("+unreadCountColumn+") AS unread_count, COUNT(*) AS conversation_count").
+		Where("latest_msg_send_time > ? AND (unread_count > ? OR is_marked_unread = ?)", 0, 0, true).
+		Group("conversation_type, recv_msg_opt, group_at_type").Find(&groups).Error
+	return groups, errs.WrapMsg(err, "GetConversationUnreadCountGroups failed")
+}
+
 func (d *DataBase) SetMultipleConversationRecvMsgOpt(ctx context.Context, conversationIDList []string, opt int) (err error) {
 	d.mRWMutex.Lock()
 	defer d.mRWMutex.Unlock()
@@ -0,0 +1,74 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+func Test_QueryConversationList(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewDataBase(ctx, "1695766238", "./", 6)
+	if err != nil {
+		return
+	}
+	conversations := []*model_struct.LocalConversation{
+		{ConversationID: "query_sg_1", ConversationType: constant.ReadGroupChatType, GroupID: "query_1", LatestMsgSendTime: 1000, UnreadCount: 3, IsPinned: true},
+		{ConversationID: "query_sg_2", ConversationType: constant.ReadGroupChatType, GroupID: "query_2", LatestMsgSendTime: 3000, UnreadCount: 1},
+		{ConversationID: "query_sg_3", ConversationType: constant.ReadGroupChatType, GroupID: "query_3", LatestMsgSendTime: 2000},
+	}
+	for _, conversation := range conversations {
+		_ = db.DeleteConversation(ctx, conversation.ConversationID)
+		if err := db.InsertConversation(ctx, conversation); err != nil {
+			t.Fatal(err)
+		}
+		defer db.DeleteConversation(ctx, conversation.ConversationID)
+	}
+	query := &sdk_struct.ConversationQuery{GroupIDList: []string{"query_1", "query_2", "query_3"}, PinnedFirst: true}
+	first, err := db.QueryConversationList(ctx, query, nil, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first) != 2 || first[0].ConversationID != "query_sg_1" || first[1].ConversationID != "query_sg_2" {
+		t.Fatalf("unexpected first page %v", first)
+	}
+	cursor := &sdk_struct.ConversationCursor{SortKey: first[1].LatestMsgSendTime, ConversationID: first[1].ConversationID}
+	second, err := db.QueryConversationList(ctx, query, cursor, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(second) != 1 || second[0].ConversationID != "query_sg_3" {
+		t.Fatalf("unexpected second page %v", second)
+	}
+	query = &sdk_struct.ConversationQuery{GroupIDList: query.GroupIDList, UnreadOnly: true, SortBy: constant.ConversationSortByUnreadCount}
+	unread, err := db.QueryConversationList(ctx, query, nil, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(unread) != 2 || unread[0].ConversationID != "query_sg_1" {
+		t.Fatalf("unexpected unread conversations %v", unread)
+	}
+	// a newer draft sorts the conversation as GetConversationListSplitDB does
+	if err := db.UpdateColumnsConversation(ctx, "query_sg_3", map[string]any{"draft_text": "draft", "draft_text_time": 4000}); err != nil {
+		t.Fatal(err)
+	}
+	query = &sdk_struct.ConversationQuery{GroupIDList: query.GroupIDList, PinnedFilter: constant.ConversationQueryFilterExclude}
+	first, err = db.QueryConversationList(ctx, query, nil, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first) != 1 || first[0].ConversationID != "query_sg_3" {
+		t.Fatalf("unexpected draft first page %v", first)
+	}
+	cursor = &sdk_struct.ConversationCursor{SortKey: first[0].DraftTextTime, ConversationID: first[0].ConversationID}
+	second, err = db.QueryConversationList(ctx, query, cursor, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(second) != 1 || second[0].ConversationID != "query_sg_2" {
+		t.Fatalf("unexpected draft second page %v", second)
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !js
+// +build !js
+
+package db
+
+import (
+	"context"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/tools/errs"
+	"gorm.io/gorm"
+)
+
+func (d *DataBase) SetConversationTimer(ctx context.Context, timer *model_struct.LocalConversationTimer) error {
+	d.mRWMutex.Lock()
+	defer d.mRWMutex.Unlock()
+	return errs.WrapMsg(d.conn.WithContext(ctx).Save(timer).Error, "SetConversationTimer failed")
+}
+
+func (d *DataBase) DeleteConversationTimer(ctx context.Context, conversationID string) error {
+	d.mRWMutex.Lock()
+	defer d.mRWMutex.Unlock()
+	return errs.WrapMsg(d.conn.WithContext(ctx).Where("conversation_id = ?", conversationID).Delete(&model_struct.LocalConversationTimer{}).Error, "DeleteConversationTimer failed")
+}
+
+func (d *DataBase) GetConversationTimer(ctx context.Context, conversationID string) (*model_struct.LocalConversationTimer, error) {
+	d.mRWMutex.RLock()
+	defer d.mRWMutex.RUnlock()
+	var timer model_struct.LocalConversationTimer
+	err := d.conn.WithContext(ctx).Where("conversation_id = ?", conversationID).Take(&timer).Error
+	if err == gorm.ErrRecordNotFound {
+		err = errs.ErrRecordNotFound
+	}
+	return &timer, errs.WrapMsg(err, "GetConversationTimer failed")
+}
+
+func (d *DataBase) GetConversationTimerList(ctx context.Context) ([]*model_struct.LocalConversationTimer, error) {
+	d.mRWMutex.RLock()
+	defer d.mRWMutex.RUnlock()
+	var timers []*model_struct.LocalConversationTimer
+	return timers, errs.WrapMsg(d.conn.WithContext(ctx).Find(&timers).Error, "GetConversationTimerList failed")
+}
@@ -74,6 +74,8 @@ type DataBase struct {
 	conn         *gorm.DB
 	tableChecker *TableChecker
 	mRWMutex     sync.RWMutex
+	// messageSearch is set when the full text index of messages is available.
+	messageSearch bool
 }
 
 func (d *DataBase) GetMultipleMessageReactionExtension(ctx context.Context, msgIDList []string) (result []*model_struct.LocalChatLogReactionExtensions, err error) {
@@ -123,6 +125,7 @@ func NewDataBase(ctx context.Context, loginUserID string, dbDir string, logLevel
 		return dataBase, errs.Wrap(err)
 	}
 	dataBase.tableChecker = NewTableChecker(tables)
+	dataBase.initMessageSearch(ctx, tables)
 
 	return dataBase, nil
 }
@@ -210,6 +213,12 @@ func (d *DataBase) versionDataMigrate(ctx context.Context) error {
 			&model_struct.LocalSendingMessages{},
 			&model_struct.LocalUserCommand{},
 			&model_struct.LocalVersionSync{},
+			&model_struct.LocalPinnedMessage{},
+			&model_struct.LocalPollVote{},
+			&model_struct.LocalLinkPreview{},
+			&model_struct.LocalConversationTimer{},
+			&model_struct.LocalConversationDraft{},
+			&model_struct.LocalGroupMemberReadSeq{},
 		)
 		if err != nil {
 			return err
@@ -226,7 +235,9 @@ func (d *DataBase) versionDataMigrate(ctx context.Context) error {
 	if verModel.Version != version.Version {
 		switch version.Version {
 		case "3.8.0":
-			d.conn.AutoMigrate(&model_struct.LocalAppSDKVersion{})
+			d.conn.AutoMigrate(&model_struct.LocalAppSDKVersion{}, &model_struct.LocalPinnedMessage{},
+				&model_struct.LocalPollVote{}, &model_struct.LocalLinkPreview{}, &model_struct.LocalConversationTimer{},
+				&model_struct.LocalConversationDraft{}, &model_struct.LocalGroupMemberReadSeq{}, &model_struct.LocalConversation{})
 		}
 		err = d.SetAppSDKVersion(ctx, &model_struct.LocalAppSDKVersion{Version: version.Version})
 		if err != nil {
@@ -18,6 +18,7 @@ import (
 	"context"
 
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
 )
 
 type GroupModel interface {
@@ -59,6 +60,10 @@ type MessageModel interface {
 	BatchInsertMessageList(ctx context.Context, conversationID string, MessageList []*model_struct.LocalChatLog) error
 	InsertMessage(ctx context.Context, conversationID string, Message *model_struct.LocalChatLog) error
 	SearchMessageByKeyword(ctx context.Context, contentType []int, keywordList []string, keywordListMatchType int, conversationID string, startTime, endTime int64, offset, count int) (result []*model_struct.LocalChatLog, err error)
+	// SearchMessageByFullText searches the full text index, in all conversations when conversationID is empty
+	SearchMessageByFullText(ctx context.Context, contentType []int, keywordList []string, keywordListMatchType int, conversationID string, startTime, endTime int64, offset, count int) (result []*model_struct.LocalChatLog, err error)
+	// SearchMessagePageByFullText searches the full text index of all conversations, newest first, and counts the matches
+	SearchMessagePageByFullText(ctx context.Context, query *sdk_struct.MessageSearchQuery, offset, count int) ([]*model_struct.LocalChatLog, int64, error)
 	SearchMessageByContentType(ctx context.Context, contentType []int, conversationID string, startTime, endTime int64, offset, count int) (result []*model_struct.LocalChatLog, err error)
 	SearchMessageByContentTypeAndKeyword(ctx context.Context, contentType []int, conversationID string, keywordList []string, keywordListMatchType int, startTime, endTime int64) (result []*model_struct.LocalChatLog, err error)
 	GetMessage(ctx context.Context, conversationID, clientMsgID string) (*model_struct.LocalChatLog, error)
@@ -75,6 +80,10 @@ type MessageModel interface {
 	MarkConversationAllMessageAsRead(ctx context.Context, conversationID string) (rowsAffected int64, err error)
 	GetMessagesByClientMsgIDs(ctx context.Context, conversationID string, msgIDs []string) (result []*model_struct.LocalChatLog, err error)
 	GetMessagesBySeqs(ctx context.Context, conversationID string, seqs []int64) (result []*model_struct.LocalChatLog, err error)
+	// GetMessagesBySendID returns the messages of sendID sent between startTime and endTime, oldest first
+	GetMessagesBySendID(ctx context.Context, conversationID, sendID string, startTime, endTime int64) (result []*model_struct.LocalChatLog, err error)
+	// GetMessagesBySendIDSeqRange returns the messages of sendID whose seq is in (startSeq, endSeq], oldest first
+	GetMessagesBySendIDSeqRange(ctx context.Context, conversationID, sendID string, startSeq, endSeq int64) (result []*model_struct.LocalChatLog, err error)
 	GetConversationNormalMsgSeq(ctx context.Context, conversationID string) (int64, error)
 	CheckConversationNormalMsgSeq(ctx context.Context, conversationID string) (int64, error)
 	GetConversationPeerNormalMsgSeq(ctx context.Context, conversationID string) (int64, error)
@@ -107,7 +116,9 @@ type ConversationModel interface {
 	GetAllConversations(ctx context.Context) ([]*model_struct.LocalConversation, error)
 	GetAllSingleConversationIDList(ctx context.Context) (result []string, err error)
 	GetAllConversationIDList(ctx context.Context) (result []string, err error)
+	GetMarkedUnreadConversationIDList(ctx context.Context) (result []string, err error)
 	GetConversationListSplitDB(ctx context.Context, offset, count int) ([]*model_struct.LocalConversation, error)
+	QueryConversationList(ctx context.Context, query *sdk_struct.ConversationQuery, cursor *sdk_struct.ConversationCursor, count int) ([]*model_struct.LocalConversation, error)
 	BatchInsertConversationList(ctx context.Context, conversationList []*model_struct.LocalConversation) error
 	UpdateOrCreateConversations(ctx context.Context, conversationList []*model_struct.LocalConversation) error
 	InsertConversation(ctx context.Context, conversationList *model_struct.LocalConversation) error
@@ -122,6 +133,7 @@ type ConversationModel interface {
 	ResetAllConversation(ctx context.Context) error
 	ClearConversation(ctx context.Context, conversationID string) error
 	SetConversationDraftDB(ctx context.Context, conversationID, draftText string) error
+	UpdateConversationDraftText(ctx context.Context, conversationID, draftText string, draftTextTime int64) error
 	RemoveConversationDraft(ctx context.Context, conversationID, draftText string) error
 	UnPinConversation(ctx context.Context, conversationID string, isPinned int) error
 	UpdateColumnsConversation(ctx context.Context, conversationID string, args map[string]interface{}) error
@@ -129,6 +141,8 @@ type ConversationModel interface {
 	IncrConversationUnreadCount(ctx context.Context, conversationID string) error
 	DecrConversationUnreadCount(ctx context.Context, conversationID string, count int64) (err error)
 	GetTotalUnreadMsgCountDB(ctx context.Context) (totalUnreadCount int32, err error)
+	// GetConversationUnreadCountGroups sums the unread conversations by type, receive option and mention type
+	GetConversationUnreadCountGroups(ctx context.Context) ([]*model_struct.ConversationUnreadCountGroup, error)
 	SetMultipleConversationRecvMsgOpt(ctx context.Context, conversationIDList []string, opt int) (err error)
 	GetMultipleConversationDB(ctx context.Context, conversationIDList []string) (result []*model_struct.LocalConversation, err error)
 	SearchAllMessageByContentType(ctx context.Context, conversationID string, contentType int) ([]*model_struct.LocalChatLog, error)
@@ -198,6 +212,39 @@ type SendingMessagesModel interface {
 	GetAllSendingMessages(ctx context.Context) (friendRequests []*model_struct.LocalSendingMessages, err error)
 }
 
+type PinnedMessageModel interface {
+	InsertPinnedMessage(ctx context.Context, message *model_struct.LocalPinnedMessage) error
+	DeletePinnedMessage(ctx context.Context, conversationID, clientMsgID string) error
+	GetPinnedMessage(ctx context.Context, conversationID, clientMsgID string) (*model_struct.LocalPinnedMessage, error)
+	GetPinnedMessageList(ctx context.Context, conversationID string) ([]*model_struct.LocalPinnedMessage, error)
+}
+type PollVoteModel interface {
+	InsertPollVote(ctx context.Context, vote *model_struct.LocalPollVote) error
+	DeletePollVote(ctx context.Context, conversationID, clientMsgID, voterID string) error
+	GetPollVote(ctx context.Context, conversationID, clientMsgID, voterID string) (*model_struct.LocalPollVote, error)
+	GetPollVoteList(ctx context.Context, conversationID, clientMsgID string) ([]*model_struct.LocalPollVote, error)
+}
+type LinkPreviewModel interface {
+	InsertLinkPreview(ctx context.Context, preview *model_struct.LocalLinkPreview) error
+	GetLinkPreview(ctx context.Context, url string) (*model_struct.LocalLinkPreview, error)
+	GetLinkPreviewList(ctx context.Context, urls []string) ([]*model_struct.LocalLinkPreview, error)
+}
+type ConversationTimerModel interface {
+	SetConversationTimer(ctx context.Context, timer *model_struct.LocalConversationTimer) error
+	DeleteConversationTimer(ctx context.Context, conversationID string) error
+	GetConversationTimer(ctx context.Context, conversationID string) (*model_struct.LocalConversationTimer, error)
+	GetConversationTimerList(ctx context.Context) ([]*model_struct.LocalConversationTimer, error)
+}
+type ConversationDraftModel interface {
+	SaveRichDraft(ctx context.Context, draft *model_struct.LocalConversationDraft) error
+	GetRichDraft(ctx context.Context, conversationID string) (*model_struct.LocalConversationDraft, error)
+	DeleteRichDraft(ctx context.Context, conversationID string) error
+	DeleteAllRichDrafts(ctx context.Context) error
+}
+type GroupMemberReadSeqModel interface {
+	SetGroupMemberReadSeq(ctx context.Context, readSeq *model_struct.LocalGroupMemberReadSeq) error
+	GetGroupMemberReadSeq(ctx context.Context, conversationID, userID string) (*model_struct.LocalGroupMemberReadSeq, error)
+}
 type VersionSyncModel interface {
 	GetVersionSync(ctx context.Context, tableName, entityID string) (*model_struct.LocalVersionSync, error)
 	SetVersionSync(ctx context.Context, version *model_struct.LocalVersionSync) error
@@ -222,6 +269,12 @@ type DataBase interface {
 	ReactionModel
 	S3Model
 	SendingMessagesModel
+	PinnedMessageModel
+	PollVoteModel
+	LinkPreviewModel
+	ConversationTimerModel
+	ConversationDraftModel
+	GroupMemberReadSeqModel
 	VersionSyncModel
 	AppSDKVersion
 	TableMaster
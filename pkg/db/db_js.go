@@ -40,6 +40,12 @@ type IndexDB struct {
 	*indexdb.NotificationSeqs
 	*indexdb.LocalUpload
 	*indexdb.LocalSendingMessages
+	*indexdb.LocalPinnedMessages
+	*indexdb.LocalPollVotes
+	*indexdb.LocalLinkPreviews
+	*indexdb.LocalConversationTimers
+	*indexdb.LocalConversationDrafts
+	*indexdb.LocalGroupMemberReadSeqs
 	*indexdb.LocalUserCommand
 	*indexdb.LocalVersionSync
 	*indexdb.LocalAppSDKVersion
@@ -74,6 +80,12 @@ func NewDataBase(ctx context.Context, loginUserID string, dbDir string, logLevel
 		NotificationSeqs:                indexdb.NewNotificationSeqs(),
 		LocalUpload:                     indexdb.NewLocalUpload(),
 		LocalSendingMessages:            indexdb.NewLocalSendingMessages(),
+		LocalPinnedMessages:             indexdb.NewLocalPinnedMessages(),
+		LocalPollVotes:                  indexdb.NewLocalPollVotes(),
+		LocalLinkPreviews:               indexdb.NewLocalLinkPreviews(),
+		LocalConversationTimers:         indexdb.NewLocalConversationTimers(),
+		LocalConversationDrafts:         indexdb.NewLocalConversationDrafts(),
+		LocalGroupMemberReadSeqs:        indexdb.NewLocalGroupMemberReadSeqs(),
 		LocalUserCommand:                indexdb.NewLocalUserCommand(),
 		LocalVersionSync:                indexdb.NewLocalVersionSync(),
 		LocalAppSDKVersion:              indexdb.NewLocalAppSDKVersion(),
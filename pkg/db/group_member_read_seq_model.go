@@ -0,0 +1,43 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !js
+// +build !js
+
+package db
+
+import (
+	"context"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/tools/errs"
+	"gorm.io/gorm"
+)
+
+func (d *DataBase) SetGroupMemberReadSeq(ctx context.Context, readSeq *model_struct.LocalGroupMemberReadSeq) error {
+	d.mRWMutex.Lock()
+	defer d.mRWMutex.Unlock()
+	return errs.WrapMsg(d.conn.WithContext(ctx).Save(readSeq).Error, "SetGroupMemberReadSeq failed")
+}
+
+func (d *DataBase) GetGroupMemberReadSeq(ctx context.Context, conversationID, userID string) (*model_struct.LocalGroupMemberReadSeq, error) {
+	d.mRWMutex.RLock()
+	defer d.mRWMutex.RUnlock()
+	var readSeq model_struct.LocalGroupMemberReadSeq
+	err := d.conn.WithContext(ctx).Where("conversation_id = ? AND user_id = ?", conversationID, userID).Take(&readSeq).Error
+	if err == gorm.ErrRecordNotFound {
+		err = errs.ErrRecordNotFound
+	}
+	return &readSeq, errs.WrapMsg(err, "GetGroupMemberReadSeq failed")
+}
@@ -0,0 +1,51 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !js
+// +build !js
+
+package db
+
+import (
+	"context"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/tools/errs"
+	"gorm.io/gorm"
+)
+
+// InsertLinkPreview caches the preview of a url, replacing the previous one.
+func (d *DataBase) InsertLinkPreview(ctx context.Context, preview *model_struct.LocalLinkPreview) error {
+	d.mRWMutex.Lock()
+	defer d.mRWMutex.Unlock()
+	return errs.WrapMsg(d.conn.WithContext(ctx).Save(preview).Error, "InsertLinkPreview failed")
+}
+
+func (d *DataBase) GetLinkPreview(ctx context.Context, url string) (*model_struct.LocalLinkPreview, error) {
+	d.mRWMutex.RLock()
+	defer d.mRWMutex.RUnlock()
+	var preview model_struct.LocalLinkPreview
+	err := d.conn.WithContext(ctx).Where("url = ?", url).Take(&preview).Error
+	if err == gorm.ErrRecordNotFound {
+		err = errs.ErrRecordNotFound
+	}
+	return &preview, errs.WrapMsg(err, "GetLinkPreview failed")
+}
+
+func (d *DataBase) GetLinkPreviewList(ctx context.Context, urls []string) ([]*model_struct.LocalLinkPreview, error) {
+	d.mRWMutex.RLock()
+	defer d.mRWMutex.RUnlock()
+	var previews []*model_struct.LocalLinkPreview
+	return previews, errs.WrapMsg(d.conn.WithContext(ctx).Where("url IN ?", urls).Find(&previews).Error, "GetLinkPreviewList failed")
+}
@@ -0,0 +1,23 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !js && (sqlite_fts5 || fts5)
+// +build !js
+// +build sqlite_fts5 fts5
+
+package db
+
+// fts5Built is set when go-sqlite3 is built with the FTS5 module, the full text index must then be
+// available.
+const fts5Built = true
@@ -0,0 +1,333 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !js
+// +build !js
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+
+	"gorm.io/gorm"
+
+	"github.com/openimsdk/tools/errs"
+	"github.com/openimsdk/tools/log"
+)
+
+// The searchable text of every message is copied into local_message_search by triggers on the
+// chat log tables, and indexed by an FTS5 table with the trigram tokenizer, which matches any
+// substring of at least three characters and so works for CJK text without word boundaries.
+// FTS5 needs the sqlite_fts5 build tag, without it search falls back to scanning the chat logs.
+const (
+	messageSearchTable    = "local_message_search"
+	messageSearchFTSTable = "local_message_search_fts"
+	// trigramLength is the shortest keyword the trigram index can match.
+	trigramLength = 3
+)
+
+// messageSearchText extracts the searchable text from a chat log row, NULL for other content types.
+var messageSearchText = fmt.Sprintf(`CASE WHEN json_valid(new.content) THEN CASE new.content_type
+	WHEN %d THEN json_extract(new.content, '$.content')
+	WHEN %d THEN json_extract(new.content, '$.text')
+	WHEN %d THEN json_extract(new.content, '$.text')
+	WHEN %d THEN json_extract(new.content, '$.text')
+	WHEN %d THEN json_extract(new.content, '$.fileName')
+	WHEN %d THEN json_extract(new.content, '$.title')
+	END END`, constant.Text, constant.AtText, constant.Quote, constant.AdvancedText, constant.File, constant.Merger)
+
+// initMessageSearch creates the search tables and indexes the existing chat logs the first time.
+// local_message_search is kept by the chat log triggers even without FTS5, only the index on it
+// needs the module, so it is rebuilt whenever FTS5 becomes available again.
+func (d *DataBase) initMessageSearch(ctx context.Context, tables []string) {
+	d.mRWMutex.Lock()
+	defer d.mRWMutex.Unlock()
+	db := d.conn.WithContext(ctx)
+	err := db.Exec(fmt.Sprintf(`CREATE VIRTUAL TABLE IF NOT EXISTS %s USING fts5(text, content='%s', content_rowid='id', tokenize='trigram')`,
+		messageSearchFTSTable, messageSearchTable)).Error
+	if err == nil {
+		err = db.Exec(fmt.Sprintf("SELECT rowid FROM %s LIMIT 0", messageSearchFTSTable)).Error
+	}
+	if err != nil {
+		if fts5Built {
+			log.ZError(ctx, "fts5 is built in but not available, message search scans the chat logs", err)
+		} else {
+			log.ZError(ctx, "built without the sqlite_fts5 tag, message search scans the chat logs", err)
+		}
+		for _, trigger := range []string{messageSearchTable + "_ai", messageSearchTable + "_ad"} {
+			if err := db.Exec("DROP TRIGGER IF EXISTS " + trigger).Error; err != nil {
+				log.ZWarn(ctx, "drop message search trigger failed", err, "trigger", trigger)
+			}
+		}
+		return
+	}
+	if d.tableChecker.HasTable(messageSearchTable) && !db.Migrator().HasColumn(messageSearchTable, "send_id") {
+		// indexed before the sender was kept, the messages are indexed again
+		if err := d.dropMessageSearchTable(ctx); err != nil {
+			log.ZWarn(ctx, "drop message search table failed", err)
+			return
+		}
+		if err := d.createMessageSearchTable(ctx, tables); err != nil {
+			log.ZWarn(ctx, "create message search table failed", err)
+			return
+		}
+	} else if !d.tableChecker.HasTable(messageSearchTable) {
+		if err := d.createMessageSearchTable(ctx, tables); err != nil {
+			log.ZWarn(ctx, "create message search table failed", err)
+			return
+		}
+	}
+	var triggers int64
+	if err := db.Raw("SELECT count(*) FROM sqlite_master WHERE type = 'trigger' AND name = ?", messageSearchTable+"_ai").Scan(&triggers).Error; err != nil {
+		log.ZWarn(ctx, "check message search trigger failed", err)
+		return
+	}
+	if triggers == 0 {
+		statements := []string{
+			fmt.Sprintf(`CREATE TRIGGER IF NOT EXISTS %[1]s_ai AFTER INSERT ON %[1]s BEGIN
+				INSERT INTO %[2]s(rowid, text) VALUES (new.id, new.text);
+			END`, messageSearchTable, messageSearchFTSTable),
+			fmt.Sprintf(`CREATE TRIGGER IF NOT EXISTS %[1]s_ad AFTER DELETE ON %[1]s BEGIN
+				INSERT INTO %[2]s(%[2]s, rowid, text) VALUES ('delete', old.id, old.text);
+			END`, messageSearchTable, messageSearchFTSTable),
+			fmt.Sprintf(`INSERT INTO %[1]s(%[1]s) VALUES ('rebuild')`, messageSearchFTSTable),
+		}
+		for _, statement := range statements {
+			if err := db.Exec(statement).Error; err != nil {
+				log.ZWarn(ctx, "create message search index failed", err)
+				return
+			}
+		}
+	}
+	d.messageSearch = true
+}
+
+// createMessageSearchTable creates local_message_search and fills it from the existing chat logs.
+func (d *DataBase) createMessageSearchTable(ctx context.Context, tables []string) error {
+	err := d.conn.WithContext(ctx).Exec(fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		conversation_id CHAR(128),
+		client_msg_id CHAR(64),
+		send_id CHAR(64),
+		content_type INTEGER,
+		send_time INTEGER,
+		text TEXT,
+		UNIQUE (conversation_id, client_msg_id)
+	)`, messageSearchTable)).Error
+	if err != nil {
+		return errs.WrapMsg(err, "create message search table failed")
+	}
+	for _, table := range tables {
+		if !strings.HasPrefix(table, constant.ChatLogsTableNamePre) {
+			continue
+		}
+		conversationID := strings.TrimPrefix(table, constant.ChatLogsTableNamePre)
+		if err := d.createMessageSearchTriggers(ctx, conversationID); err != nil {
+			return err
+		}
+		err := d.conn.WithContext(ctx).Exec(fmt.Sprintf(`INSERT INTO %s(conversation_id, client_msg_id, send_id, content_type, send_time, text)
+			SELECT ?, new.client_msg_id, new.send_id, new.content_type, new.send_time, %s AS text FROM %s AS new
+			WHERE (new.status <= ? OR new.status = ?) AND text IS NOT NULL AND text != ''`, messageSearchTable, messageSearchText, table),
+			conversationID, constant.MsgStatusSendFailed, constant.MsgStatusDelivered).Error
+		if err != nil {
+			return errs.WrapMsg(err, "index chat logs failed", "conversationID", conversationID)
+		}
+	}
+	d.tableChecker.UpdateTable(messageSearchTable)
+	return nil
+}
+
+// createMessageSearchTriggers keeps local_message_search in step with the chat log table of the
+// conversation, so inserts, edits, revokes and deletes done by any query are all reflected.
+func (d *DataBase) createMessageSearchTriggers(ctx context.Context, conversationID string) error {
+	table := utils.GetTableName(conversationID)
+	deleteRow := fmt.Sprintf(`DELETE FROM %s WHERE conversation_id = '%s' AND client_msg_id = old.client_msg_id;`, messageSearchTable, conversationID)
+	insertRow := fmt.Sprintf(`INSERT INTO %s(conversation_id, client_msg_id, send_id, content_type, send_time, text)
+		SELECT '%s', new.client_msg_id, new.send_id, new.content_type, new.send_time, text FROM (SELECT %s AS text)
+		WHERE (new.status <= %d OR new.status = %d) AND text IS NOT NULL AND text != '';`, messageSearchTable, conversationID, messageSearchText,
+		constant.MsgStatusSendFailed, constant.MsgStatusDelivered)
+	statements := []string{
+		fmt.Sprintf(`CREATE TRIGGER IF NOT EXISTS search_ai_%s AFTER INSERT ON %s BEGIN %s END`, conversationID, table, insertRow),
+		fmt.Sprintf(`CREATE TRIGGER IF NOT EXISTS search_au_%s AFTER UPDATE OF content, content_type, status, send_time ON %s BEGIN %s %s END`,
+			conversationID, table, deleteRow, insertRow),
+		fmt.Sprintf(`CREATE TRIGGER IF NOT EXISTS search_ad_%s AFTER DELETE ON %s BEGIN %s END`, conversationID, table, deleteRow),
+	}
+	for _, statement := range statements {
+		if err := d.conn.WithContext(ctx).Exec(statement).Error; err != nil {
+			return errs.WrapMsg(err, "create message search trigger failed", "conversationID", conversationID)
+		}
+	}
+	return nil
+}
+
+// dropMessageSearchTable drops local_message_search with the triggers of the chat log tables filling
+// it, the full text index is rebuilt once the table is created again.
+func (d *DataBase) dropMessageSearchTable(ctx context.Context) error {
+	db := d.conn.WithContext(ctx)
+	var triggers []string
+	err := db.Raw(`SELECT name FROM sqlite_master WHERE type = 'trigger' AND name LIKE 'search\_a_\_%' ESCAPE '\'`).Scan(&triggers).Error
+	if err != nil {
+		return errs.WrapMsg(err, "get message search triggers failed")
+	}
+	for _, trigger := range triggers {
+		if err := db.Exec("DROP TRIGGER IF EXISTS " + trigger).Error; err != nil {
+			return errs.WrapMsg(err, "drop message search trigger failed", "trigger", trigger)
+		}
+	}
+	return errs.WrapMsg(db.Exec("DROP TABLE IF EXISTS "+messageSearchTable).Error, "drop message search table failed")
+}
+
+type messageSearchHit struct {
+	ConversationID string `gorm:"column:conversation_id"`
+	ClientMsgID    string `gorm:"column:client_msg_id"`
+}
+
+// SearchMessageByFullText finds the messages whose text contains the keywords, in one conversation
+// or, when conversationID is empty, in all of them. Results are ordered by relevance when every
+// keyword is long enough for the index, otherwise by send time.
+func (d *DataBase) SearchMessageByFullText(ctx context.Context, contentType []int, keywordList []string, keywordListMatchType int,
+	conversationID string, startTime, endTime int64, offset, count int) ([]*model_struct.LocalChatLog, error) {
+	d.mRWMutex.RLock()
+	defer d.mRWMutex.RUnlock()
+	if !d.messageSearch {
+		return nil, errs.New("full text search is not available").Wrap()
+	}
+	if len(keywordList) == 0 {
+		return nil, errs.ErrArgs.WrapMsg("keyword list is empty")
+	}
+	db, useIndex := d.messageSearchKeywords(ctx, keywordList, keywordListMatchType)
+	db = db.Select("s.conversation_id, s.client_msg_id")
+	if useIndex {
+		db = db.Order(messageSearchFTSTable + ".rank")
+	}
+	db = db.Where("s.send_time BETWEEN ? AND ?", startTime, endTime).Where("s.content_type IN ?", contentType)
+	if conversationID != "" {
+		db = db.Where("s.conversation_id = ?", conversationID)
+	}
+	if count > 0 {
+		db = db.Offset(offset).Limit(count)
+	}
+	var hits []*messageSearchHit
+	if err := db.Order("s.send_time DESC").Find(&hits).Error; err != nil {
+		return nil, errs.WrapMsg(err, "SearchMessageByFullText failed")
+	}
+	return d.getMessageSearchHits(ctx, hits)
+}
+
+// SearchMessagePageByFullText finds the messages of all conversations matching the query, the most
+// recent first. The filters and the page are applied in SQL, the number of matches is returned too.
+func (d *DataBase) SearchMessagePageByFullText(ctx context.Context, query *sdk_struct.MessageSearchQuery, offset, count int) ([]*model_struct.LocalChatLog, int64, error) {
+	d.mRWMutex.RLock()
+	defer d.mRWMutex.RUnlock()
+	if !d.messageSearch {
+		return nil, 0, errs.New("full text search is not available").Wrap()
+	}
+	if len(query.KeywordList) == 0 {
+		return nil, 0, errs.ErrArgs.WrapMsg("keyword list is empty")
+	}
+	db, _ := d.messageSearchKeywords(ctx, query.KeywordList, query.KeywordListMatchType)
+	// messages of conversations no longer in the list are not shown
+	db = db.Joins("JOIN local_conversations AS c ON c.conversation_id = s.conversation_id").
+		Where("s.send_time BETWEEN ? AND ?", query.StartTime, query.EndTime).Where("s.content_type IN ?", query.ContentTypeList)
+	if len(query.SenderUserIDList) > 0 {
+		db = db.Where("s.send_id IN ?", query.SenderUserIDList)
+	}
+	if len(query.ConversationTypeList) > 0 {
+		db = db.Where("c.conversation_type IN ?", query.ConversationTypeList)
+	}
+	var total int64
+	if err := db.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, errs.WrapMsg(err, "SearchMessagePageByFullText count failed")
+	}
+	var hits []*messageSearchHit
+	err := db.Select("s.conversation_id, s.client_msg_id").Order("s.send_time DESC, s.id DESC").
+		Offset(offset).Limit(count).Find(&hits).Error
+	if err != nil {
+		return nil, 0, errs.WrapMsg(err, "SearchMessagePageByFullText failed")
+	}
+	list, err := d.getMessageSearchHits(ctx, hits)
+	return list, total, err
+}
+
+// messageSearchKeywords starts a query on local_message_search AS s matching the keywords, through
+// the full text index when every keyword is long enough for it, which it reports.
+func (d *DataBase) messageSearchKeywords(ctx context.Context, keywordList []string, keywordListMatchType int) (*gorm.DB, bool) {
+	separator := " OR "
+	if keywordListMatchType == constant.KeywordMatchAnd {
+		separator = " AND "
+	}
+	useIndex := true
+	for _, keyword := range keywordList {
+		if utf8.RuneCountInString(keyword) < trigramLength {
+			useIndex = false
+			break
+		}
+	}
+	db := d.conn.WithContext(ctx).Table(messageSearchTable + " AS s")
+	if useIndex {
+		phrases := make([]string, 0, len(keywordList))
+		for _, keyword := range keywordList {
+			phrases = append(phrases, `"`+strings.ReplaceAll(keyword, `"`, `""`)+`"`)
+		}
+		return db.Joins(fmt.Sprintf("JOIN %[1]s ON %[1]s.rowid = s.id", messageSearchFTSTable)).
+			Where(messageSearchFTSTable+" MATCH ?", strings.Join(phrases, separator)), true
+	}
+	conditions := make([]string, 0, len(keywordList))
+	args := make([]any, 0, len(keywordList))
+	for _, keyword := range keywordList {
+		conditions = append(conditions, `s.text LIKE ? ESCAPE '\'`)
+		args = append(args, "%"+escapeLike(keyword)+"%")
+	}
+	return db.Where("("+strings.Join(conditions, separator)+")", args...), false
+}
+
+// getMessageSearchHits loads the chat logs of the hits, keeping the order of the hits.
+func (d *DataBase) getMessageSearchHits(ctx context.Context, hits []*messageSearchHit) ([]*model_struct.LocalChatLog, error) {
+	clientMsgIDs := make(map[string][]string)
+	for _, hit := range hits {
+		clientMsgIDs[hit.ConversationID] = append(clientMsgIDs[hit.ConversationID], hit.ClientMsgID)
+	}
+	messages := make(map[[2]string]*model_struct.LocalChatLog, len(hits))
+	for conversationID, ids := range clientMsgIDs {
+		if !d.tableChecker.HasTable(utils.GetTableName(conversationID)) {
+			continue
+		}
+		var list []*model_struct.LocalChatLog
+		if err := d.conn.WithContext(ctx).Table(utils.GetTableName(conversationID)).Where("client_msg_id IN ?", ids).Find(&list).Error; err != nil {
+			return nil, errs.WrapMsg(err, "get message search hits failed", "conversationID", conversationID)
+		}
+		for _, message := range list {
+			messages[[2]string{conversationID, message.ClientMsgID}] = message
+		}
+	}
+	result := make([]*model_struct.LocalChatLog, 0, len(hits))
+	for _, hit := range hits {
+		if message, ok := messages[[2]string{hit.ConversationID, hit.ClientMsgID}]; ok {
+			result = append(result, message)
+		}
+	}
+	return result, nil
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
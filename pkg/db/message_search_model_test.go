@@ -0,0 +1,136 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+)
+
+// requireMessageSearch fails when the full text index is missing although FTS5 is built in, and
+// skips the test when it is not, make test builds it in.
+func requireMessageSearch(t *testing.T, db *DataBase) {
+	if db.messageSearch {
+		return
+	}
+	if fts5Built {
+		t.Fatal("fts5 is built in but the full text index is not available")
+	}
+	t.Skip("fts5 is not built in, run the tests with the sqlite_fts5 tag")
+}
+
+func Test_SearchMessageByFullText(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewDataBase(ctx, "1695766238", "./", 6)
+	if err != nil {
+		return
+	}
+	requireMessageSearch(t, db)
+	conversationID := "si_1695766238_2882899447"
+	if err := db.initChatLog(ctx, conversationID); err != nil {
+		t.Fatal(err)
+	}
+	messages := []*model_struct.LocalChatLog{
+		{ClientMsgID: "search_msg_1", ContentType: constant.Text, Content: `{"content":"今天一起吃火锅吗"}`, Status: constant.MsgStatusSendSuccess, SendTime: 1000},
+		{ClientMsgID: "search_msg_2", ContentType: constant.File, Content: `{"fileName":"火锅店菜单.pdf"}`, Status: constant.MsgStatusSendSuccess, SendTime: 2000},
+		{ClientMsgID: "search_msg_3", ContentType: constant.Text, Content: `{"content":"Hello OpenIM"}`, Status: constant.MsgStatusSendSuccess, SendTime: 3000},
+	}
+	for _, message := range messages {
+		if err := db.InsertMessage(ctx, conversationID, message); err != nil {
+			t.Fatal(err)
+		}
+	}
+	contentType := []int{constant.Text, constant.File}
+	search := func(keywords ...string) []*model_struct.LocalChatLog {
+		list, err := db.SearchMessageByFullText(ctx, contentType, keywords, constant.KeywordMatchOr, "", 0, 4000, 0, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return list
+	}
+	if list := search("吃火锅"); len(list) != 1 || list[0].ClientMsgID != "search_msg_1" {
+		t.Fatalf("unexpected result %v", list)
+	}
+	// keywords shorter than a trigram are still found
+	if list := search("火锅"); len(list) != 2 || list[0].ClientMsgID != "search_msg_2" {
+		t.Fatalf("unexpected result %v", list)
+	}
+	if list := search("openim"); len(list) != 1 {
+		t.Fatalf("unexpected result %v", list)
+	}
+	// a revoked message is no longer searchable
+	if err := db.UpdateColumnsMessage(ctx, conversationID, "search_msg_1", map[string]any{"content_type": constant.RevokeNotification}); err != nil {
+		t.Fatal(err)
+	}
+	if list := search("吃火锅"); len(list) != 0 {
+		t.Fatalf("unexpected result %v", list)
+	}
+	if err := db.DeleteConversationAllMessages(ctx, conversationID); err != nil {
+		t.Fatal(err)
+	}
+	if list := search("Hello"); len(list) != 0 {
+		t.Fatalf("unexpected result %v", list)
+	}
+}
+
+func Test_SearchMessagePageByFullText(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewDataBase(ctx, "u1", t.TempDir(), 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	requireMessageSearch(t, db)
+	conversations := []*model_struct.LocalConversation{
+		{ConversationID: "si_u1_u2", ConversationType: constant.SingleChatType, UserID: "u2"},
+		{ConversationID: "sg_g1", ConversationType: constant.ReadGroupChatType, GroupID: "g1"},
+	}
+	for _, conversation := range conversations {
+		if err := db.InsertConversation(ctx, conversation); err != nil {
+			t.Fatal(err)
+		}
+	}
+	insert := func(conversationID, clientMsgID, sendID string, sendTime int64) {
+		t.Helper()
+		if err := db.initChatLog(ctx, conversationID); err != nil {
+			t.Fatal(err)
+		}
+		message := &model_struct.LocalChatLog{ClientMsgID: clientMsgID, SendID: sendID, ContentType: constant.Text,
+			Content: `{"content":"hello ` + clientMsgID + `"}`, Status: constant.MsgStatusSendSuccess, SendTime: sendTime}
+		if err := db.InsertMessage(ctx, conversationID, message); err != nil {
+			t.Fatal(err)
+		}
+	}
+	insert("si_u1_u2", "m1", "u2", 1000)
+	insert("sg_g1", "m2", "u3", 2000)
+	insert("si_u1_u2", "m3", "u1", 3000)
+	// the conversation is no longer in the list
+	insert("si_u1_u4", "m4", "u4", 4000)
+	search := func(query sdk_struct.MessageSearchQuery, offset, count int) (ids []string, total int64) {
+		t.Helper()
+		query.ContentTypeList = []int{constant.Text}
+		query.KeywordList = []string{"hello"}
+		query.EndTime = 5000
+		list, total, err := db.SearchMessagePageByFullText(ctx, &query, offset, count)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, message := range list {
+			ids = append(ids, message.ClientMsgID)
+		}
+		return ids, total
+	}
+	if ids, total := search(sdk_struct.MessageSearchQuery{}, 0, 2); total != 3 || len(ids) != 2 || ids[0] != "m3" || ids[1] != "m2" {
+		t.Fatalf("first page %v of %d", ids, total)
+	}
+	if ids, total := search(sdk_struct.MessageSearchQuery{}, 2, 2); total != 3 || len(ids) != 1 || ids[0] != "m1" {
+		t.Fatalf("last page %v of %d", ids, total)
+	}
+	if ids, total := search(sdk_struct.MessageSearchQuery{SenderUserIDList: []string{"u2", "u3"}}, 0, 10); total != 2 || len(ids) != 2 {
+		t.Fatalf("messages of senders %v of %d", ids, total)
+	}
+	if ids, total := search(sdk_struct.MessageSearchQuery{ConversationTypeList: []int32{constant.ReadGroupChatType}}, 0, 10); total != 1 || ids[0] != "m2" {
+		t.Fatalf("group messages %v of %d", ids, total)
+	}
+}
@@ -237,19 +237,19 @@ type TempCacheLocalChatLog struct {
 
 type LocalConversation struct {
 	ConversationID        string `gorm:"column:conversation_id;primary_key;type:char(128)" json:"conversationID"`
-	ConversationType      int32  `gorm:"column:conversation_type" json:"conversationType"`
+	ConversationType      int32  `gorm:"column:conversation_type;index:index_conversation_type" json:"conversationType"`
 	UserID                string `gorm:"column:user_id;type:char(64)" json:"userID"`
-	GroupID               string `gorm:"column:group_id;type:char(128)" json:"groupID"`
-	ShowName              string `gorm:"column:show_name;type:varchar(255)" json:"showName"`
+	GroupID               string `gorm:"column:group_id;type:char(128);index:index_conversation_group_id" json:"groupID"`
+	ShowName              string `gorm:"column:show_name;type:varchar(255);index:index_show_name" json:"showName"`
 	FaceURL               string `gorm:"column:face_url;type:varchar(255)" json:"faceURL"`
 	RecvMsgOpt            int32  `gorm:"column:recv_msg_opt" json:"recvMsgOpt"`
-	UnreadCount           int32  `gorm:"column:unread_count" json:"unreadCount"`
+	UnreadCount           int32  `gorm:"column:unread_count;index:index_unread_count" json:"unreadCount"`
 	GroupAtType           int32  `gorm:"column:group_at_type" json:"groupAtType"`
 	LatestMsg             string `gorm:"column:latest_msg;type:varchar(1000)" json:"latestMsg"`
-	LatestMsgSendTime     int64  `gorm:"column:latest_msg_send_time;index:index_latest_msg_send_time" json:"latestMsgSendTime"`
+	LatestMsgSendTime     int64  `gorm:"column:latest_msg_send_time;index:index_latest_msg_send_time;index:index_pinned_latest_msg_send_time,priority:2" json:"latestMsgSendTime"`
 	DraftText             string `gorm:"column:draft_text" json:"draftText"`
 	DraftTextTime         int64  `gorm:"column:draft_text_time" json:"draftTextTime"`
-	IsPinned              bool   `gorm:"column:is_pinned" json:"isPinned"`
+	IsPinned              bool   `gorm:"column:is_pinned;index:index_pinned_latest_msg_send_time,priority:1" json:"isPinned"`
 	IsPrivateChat         bool   `gorm:"column:is_private_chat" json:"isPrivateChat"`
 	BurnDuration          int32  `gorm:"column:burn_duration;default:30" json:"burnDuration"`
 	IsNotInGroup          bool   `gorm:"column:is_not_in_group" json:"isNotInGroup"`
@@ -260,6 +260,7 @@ type LocalConversation struct {
 	MinSeq                int64  `gorm:"column:min_seq" json:"minSeq"`
 	MsgDestructTime       int64  `gorm:"column:msg_destruct_time;default:604800" json:"msgDestructTime"`
 	IsMsgDestruct         bool   `gorm:"column:is_msg_destruct;default:false" json:"isMsgDestruct"`
+	IsMarkedUnread        bool   `gorm:"column:is_marked_unread;default:false" json:"isMarkedUnread"`
 }
 
 func (LocalConversation) TableName() string {
@@ -286,6 +287,16 @@ type LocalWorkMomentsNotification struct {
 	CreateTime int64  `gorm:"create_time"`
 }
 
+// ConversationUnreadCountGroup sums the unread counts of the visible unread conversations sharing
+// the same type, receive option and mention type.
+type ConversationUnreadCountGroup struct {
+	ConversationType  int32 `gorm:"column:conversation_type" json:"conversationType"`
+	RecvMsgOpt        int32 `gorm:"column:recv_msg_opt" json:"recvMsgOpt"`
+	GroupAtType       int32 `gorm:"column:group_at_type" json:"groupAtType"`
+	UnreadCount       int32 `gorm:"column:unread_count" json:"unreadCount"`
+	ConversationCount int32 `gorm:"column:conversation_count" json:"conversationCount"`
+}
+
 type WorkMomentNotificationMsg struct {
 	NotificationMsgType int32  `json:"notificationMsgType"`
 	ReplyUserName       string `json:"replyUserName"`
@@ -405,3 +416,77 @@ type LocalAppSDKVersion struct {
 func (LocalAppSDKVersion) TableName() string {
 	return "local_app_sdk_version"
 }
+
+type LocalPinnedMessage struct {
+	ConversationID string `gorm:"column:conversation_id;primary_key;type:char(128)" json:"conversationID"`
+	ClientMsgID    string `gorm:"column:client_msg_id;primary_key;type:char(64)" json:"clientMsgID"`
+	Seq            int64  `gorm:"column:seq" json:"seq"`
+	PinnedUserID   string `gorm:"column:pinned_user_id;type:char(64)" json:"pinnedUserID"`
+	PinnedTime     int64  `gorm:"column:pinned_time;index:index_pinned_time" json:"pinnedTime"`
+	Ex             string `gorm:"column:ex;type:varchar(1024)" json:"ex"`
+}
+
+func (LocalPinnedMessage) TableName() string {
+	return "local_pinned_messages"
+}
+
+type LocalPollVote struct {
+	ConversationID string      `gorm:"column:conversation_id;primary_key;type:char(128)" json:"conversationID"`
+	ClientMsgID    string      `gorm:"column:client_msg_id;primary_key;type:char(64)" json:"clientMsgID"`
+	VoterID        string      `gorm:"column:voter_id;primary_key;type:char(64)" json:"voterID"`
+	OptionIDList   StringArray `gorm:"column:option_id_list;type:text" json:"optionIDList"`
+	VoteTime       int64       `gorm:"column:vote_time" json:"voteTime"`
+}
+
+func (LocalPollVote) TableName() string {
+	return "local_poll_votes"
+}
+
+type LocalLinkPreview struct {
+	URL         string `gorm:"column:url;primary_key;type:varchar(2048)" json:"url"`
+	Title       string `gorm:"column:title;type:varchar(256)" json:"title"`
+	Description string `gorm:"column:description;type:varchar(1024)" json:"description"`
+	ImageURL    string `gorm:"column:image_url;type:varchar(2048)" json:"imageURL"`
+	SiteName    string `gorm:"column:site_name;type:varchar(255)" json:"siteName"`
+	UpdateTime  int64  `gorm:"column:update_time" json:"updateTime"`
+}
+
+func (LocalLinkPreview) TableName() string {
+	return "local_link_previews"
+}
+
+// LocalConversationTimer records a time bounded mute or snooze of a conversation, until the time
+// elapses and the previous state is restored.
+type LocalConversationTimer struct {
+	ConversationID    string `gorm:"column:conversation_id;primary_key;type:char(128)" json:"conversationID"`
+	MuteUntil         int64  `gorm:"column:mute_until" json:"muteUntil"`
+	RestoreRecvMsgOpt int32  `gorm:"column:restore_recv_msg_opt" json:"restoreRecvMsgOpt"`
+	SnoozeUntil       int64  `gorm:"column:snooze_until" json:"snoozeUntil"`
+}
+
+func (LocalConversationTimer) TableName() string {
+	return "local_conversation_timers"
+}
+
+// LocalConversationDraft holds the structured draft of a conversation, Content is the draft in json.
+type LocalConversationDraft struct {
+	ConversationID string `gorm:"column:conversation_id;primary_key;type:char(128)" json:"conversationID"`
+	Content        string `gorm:"column:content;type:text" json:"content"`
+	UpdateTime     int64  `gorm:"column:update_time" json:"updateTime"`
+}
+
+func (LocalConversationDraft) TableName() string {
+	return "local_conversation_drafts"
+}
+
+// LocalGroupMemberReadSeq is the read seq of a group member last seen by the login user, the
+// start of the messages covered by the next read receipt of the member.
+type LocalGroupMemberReadSeq struct {
+	ConversationID string `gorm:"column:conversation_id;primary_key;type:char(128)" json:"conversationID"`
+	UserID         string `gorm:"column:user_id;primary_key;type:varchar(64)" json:"userID"`
+	HasReadSeq     int64  `gorm:"column:has_read_seq" json:"hasReadSeq"`
+}
+
+func (LocalGroupMemberReadSeq) TableName() string {
+	return "local_group_member_read_seqs"
+}
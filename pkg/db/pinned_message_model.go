@@ -0,0 +1,60 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !js
+// +build !js
+
+package db
+
+import (
+	"context"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/tools/errs"
+	"gorm.io/gorm"
+)
+
+// InsertPinnedMessage inserts a pinned message, replacing the existing record of the same message.
+func (d *DataBase) InsertPinnedMessage(ctx context.Context, message *model_struct.LocalPinnedMessage) error {
+	d.mRWMutex.Lock()
+	defer d.mRWMutex.Unlock()
+	return errs.WrapMsg(d.conn.WithContext(ctx).Save(message).Error, "InsertPinnedMessage failed")
+}
+
+func (d *DataBase) DeletePinnedMessage(ctx context.Context, conversationID, clientMsgID string) error {
+	d.mRWMutex.Lock()
+	defer d.mRWMutex.Unlock()
+	return errs.WrapMsg(d.conn.WithContext(ctx).Where("conversation_id = ? AND client_msg_id = ?", conversationID, clientMsgID).
+		Delete(&model_struct.LocalPinnedMessage{}).Error, "DeletePinnedMessage failed")
+}
+
+func (d *DataBase) GetPinnedMessage(ctx context.Context, conversationID, clientMsgID string) (*model_struct.LocalPinnedMessage, error) {
+	d.mRWMutex.RLock()
+	defer d.mRWMutex.RUnlock()
+	var message model_struct.LocalPinnedMessage
+	err := d.conn.WithContext(ctx).Where("conversation_id = ? AND client_msg_id = ?", conversationID, clientMsgID).Take(&message).Error
+	if err == gorm.ErrRecordNotFound {
+		err = errs.ErrRecordNotFound
+	}
+	return &message, errs.WrapMsg(err, "GetPinnedMessage failed")
+}
+
+// GetPinnedMessageList returns the pinned messages of a conversation, the most recently pinned first.
+func (d *DataBase) GetPinnedMessageList(ctx context.Context, conversationID string) ([]*model_struct.LocalPinnedMessage, error) {
+	d.mRWMutex.RLock()
+	defer d.mRWMutex.RUnlock()
+	var messages []*model_struct.LocalPinnedMessage
+	return messages, errs.WrapMsg(d.conn.WithContext(ctx).Where("conversation_id = ?", conversationID).
+		Order("pinned_time DESC").Find(&messages).Error, "GetPinnedMessageList failed")
+}
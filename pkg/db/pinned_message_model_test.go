@@ -0,0 +1,46 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/tools/errs"
+)
+
+func Test_PinnedMessage(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewDataBase(ctx, "1695766238", "./", 6)
+	if err != nil {
+		return
+	}
+	conversationID := "sg_3559848838"
+	pinned := []*model_struct.LocalPinnedMessage{
+		{ConversationID: conversationID, ClientMsgID: "pinned_msg_1", Seq: 10, PinnedUserID: "1695766238", PinnedTime: 1000},
+		{ConversationID: conversationID, ClientMsgID: "pinned_msg_2", Seq: 12, PinnedUserID: "1695766238", PinnedTime: 2000},
+	}
+	for _, message := range pinned {
+		if err := db.InsertPinnedMessage(ctx, message); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// pinning again replaces the existing record
+	if err := db.InsertPinnedMessage(ctx, pinned[0]); err != nil {
+		t.Fatal(err)
+	}
+	list, err := db.GetPinnedMessageList(ctx, conversationID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[0].ClientMsgID != "pinned_msg_2" {
+		t.Fatalf("unexpected pinned message list %v", list)
+	}
+	for _, message := range pinned {
+		if err := db.DeletePinnedMessage(ctx, conversationID, message.ClientMsgID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := db.GetPinnedMessage(ctx, conversationID, "pinned_msg_1"); !errs.ErrRecordNotFound.Is(err) {
+		t.Fatalf("expected record not found, got %v", err)
+	}
+}
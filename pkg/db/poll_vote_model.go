@@ -0,0 +1,59 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !js
+// +build !js
+
+package db
+
+import (
+	"context"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/tools/errs"
+	"gorm.io/gorm"
+)
+
+// InsertPollVote saves the options chosen by a voter, replacing the voter's previous choice.
+func (d *DataBase) InsertPollVote(ctx context.Context, vote *model_struct.LocalPollVote) error {
+	d.mRWMutex.Lock()
+	defer d.mRWMutex.Unlock()
+	return errs.WrapMsg(d.conn.WithContext(ctx).Save(vote).Error, "InsertPollVote failed")
+}
+
+func (d *DataBase) DeletePollVote(ctx context.Context, conversationID, clientMsgID, voterID string) error {
+	d.mRWMutex.Lock()
+	defer d.mRWMutex.Unlock()
+	return errs.WrapMsg(d.conn.WithContext(ctx).Where("conversation_id = ? AND client_msg_id = ? AND voter_id = ?", conversationID, clientMsgID, voterID).
+		Delete(&model_struct.LocalPollVote{}).Error, "DeletePollVote failed")
+}
+
+func (d *DataBase) GetPollVote(ctx context.Context, conversationID, clientMsgID, voterID string) (*model_struct.LocalPollVote, error) {
+	d.mRWMutex.RLock()
+	defer d.mRWMutex.RUnlock()
+	var vote model_struct.LocalPollVote
+	err := d.conn.WithContext(ctx).Where("conversation_id = ? AND client_msg_id = ? AND voter_id = ?", conversationID, clientMsgID, voterID).Take(&vote).Error
+	if err == gorm.ErrRecordNotFound {
+		err = errs.ErrRecordNotFound
+	}
+	return &vote, errs.WrapMsg(err, "GetPollVote failed")
+}
+
+func (d *DataBase) GetPollVoteList(ctx context.Context, conversationID, clientMsgID string) ([]*model_struct.LocalPollVote, error) {
+	d.mRWMutex.RLock()
+	defer d.mRWMutex.RUnlock()
+	var votes []*model_struct.LocalPollVote
+	return votes, errs.WrapMsg(d.conn.WithContext(ctx).Where("conversation_id = ? AND client_msg_id = ?", conversationID, clientMsgID).
+		Order("vote_time ASC").Find(&votes).Error, "GetPollVoteList failed")
+}
@@ -0,0 +1,43 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+)
+
+func Test_PollVote(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewDataBase(ctx, "1695766238", "./", 6)
+	if err != nil {
+		return
+	}
+	conversationID, clientMsgID := "sg_3559848838", "poll_msg_1"
+	votes := []*model_struct.LocalPollVote{
+		{ConversationID: conversationID, ClientMsgID: clientMsgID, VoterID: "1695766238", OptionIDList: []string{"1"}, VoteTime: 1000},
+		{ConversationID: conversationID, ClientMsgID: clientMsgID, VoterID: "2882899447", OptionIDList: []string{"1", "2"}, VoteTime: 2000},
+	}
+	for _, vote := range votes {
+		if err := db.InsertPollVote(ctx, vote); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// voting again replaces the previous choice
+	if err := db.InsertPollVote(ctx, &model_struct.LocalPollVote{ConversationID: conversationID, ClientMsgID: clientMsgID,
+		VoterID: "1695766238", OptionIDList: []string{"2"}, VoteTime: 3000}); err != nil {
+		t.Fatal(err)
+	}
+	list, err := db.GetPollVoteList(ctx, conversationID, clientMsgID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[1].VoterID != "1695766238" || len(list[1].OptionIDList) != 1 || list[1].OptionIDList[0] != "2" {
+		t.Fatalf("unexpected poll vote list %v", list)
+	}
+	for _, vote := range votes {
+		if err := db.DeletePollVote(ctx, conversationID, clientMsgID, vote.VoterID); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
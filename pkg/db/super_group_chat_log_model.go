@@ -90,7 +90,7 @@ func (d *DataBase) SuperGroupSearchMessageByKeyword(ctx context.Context, content
 			}
 		}
 	}
-	condition = fmt.Sprintf("recv_id=%q And send_time between %d and %d AND status <=%d  And content_type IN ? ", sourceID, startTime, endTime, constant.MsgStatusSendFailed)
+	condition = fmt.Sprintf("recv_id=%q And send_time between %d and %d AND %s And content_type IN ? ", sourceID, startTime, endTime, shownStatusCondition)
 	condition += subCondition
 	return messageList, errs.WrapMsg(d.conn.WithContext(ctx).Table(utils.GetConversationTableName(sourceID)).Where(condition, contentType).Order("send_time DESC").Offset(offset).Limit(count).Find(&messageList).Error, "InsertMessage failed")
 }
@@ -106,7 +106,7 @@ func (d *DataBase) SuperGroupSearchMessageByContentType(ctx context.Context, con
 	d.mRWMutex.RLock()
 	defer d.mRWMutex.RUnlock()
 	var messageList []*model_struct.LocalChatLog
-	condition := fmt.Sprintf("session_type=%d And recv_id==%q And send_time between %d and %d AND status <=%d And content_type IN ?", sessionType, sourceID, startTime, endTime, constant.MsgStatusSendFailed)
+	condition := fmt.Sprintf("session_type=%d And recv_id==%q And send_time between %d and %d AND %s And content_type IN ?", sessionType, sourceID, startTime, endTime, shownStatusCondition)
 	return messageList, errs.WrapMsg(d.conn.WithContext(ctx).Table(utils.GetConversationTableName(sourceID)).Where(condition, contentType).Order("send_time DESC").Offset(offset).Limit(count).Find(&messageList).Error, "SearchMessage failed")
 }
 
@@ -140,7 +140,7 @@ func (d *DataBase) SuperGroupSearchMessageByContentTypeAndKeyword(ctx context.Co
 			}
 		}
 	}
-	condition = fmt.Sprintf("send_time between %d and %d AND status <=%d  And content_type IN ? ", startTime, endTime, constant.MsgStatusSendFailed)
+	condition = fmt.Sprintf("send_time between %d and %d AND %s And content_type IN ? ", startTime, endTime, shownStatusCondition)
 	condition += subCondition
 	return messageList, errs.WrapMsg(d.conn.WithContext(ctx).Table(utils.GetConversationTableName(groupID)).Where(condition, contentType).Order("send_time DESC").Find(&messageList).Error, "SearchMessage failed")
 }
@@ -285,9 +285,9 @@ func (d *DataBase) SuperGroupGetMessageList(ctx context.Context, sourceID string
 		timeOrder = "send_time DESC"
 		timeSymbol = "<"
 	}
-	condition = " recv_id = ? AND status <=? And session_type = ? And send_time " + timeSymbol + " ?"
+	condition = " recv_id = ? AND " + shownStatusCondition + " And session_type = ? And send_time " + timeSymbol + " ?"
 
-	return messageList, errs.WrapMsg(d.conn.WithContext(ctx).Table(utils.GetConversationTableName(sourceID)).Where(condition, sourceID, constant.MsgStatusSendFailed, sessionType, startTime).
+	return messageList, errs.WrapMsg(d.conn.WithContext(ctx).Table(utils.GetConversationTableName(sourceID)).Where(condition, sourceID, sessionType, startTime).
 		Order(timeOrder).Offset(0).Limit(count).Find(&messageList).Error, "GetMessageList failed")
 }
 
@@ -302,8 +302,8 @@ func (d *DataBase) SuperGroupGetMessageListNoTime(ctx context.Context, sourceID
 	} else {
 		timeOrder = "send_time DESC"
 	}
-	condition = "recv_id = ? AND status <=? And session_type = ? "
-	return messageList, errs.WrapMsg(d.conn.WithContext(ctx).Table(utils.GetConversationTableName(sourceID)).Where(condition, sourceID, constant.MsgStatusSendFailed, sessionType).
+	condition = "recv_id = ? AND " + shownStatusCondition + " And session_type = ? "
+	return messageList, errs.WrapMsg(d.conn.WithContext(ctx).Table(utils.GetConversationTableName(sourceID)).Where(condition, sourceID, sessionType).
 		Order(timeOrder).Offset(0).Limit(count).Find(&messageList).Error, "GetMessageList failed")
 }
 
@@ -0,0 +1,243 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package linkpreview finds links in message text and reads the OpenGraph metadata used to
+// render link preview cards.
+package linkpreview
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
+	"unicode/utf16"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/constant"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
+	"github.com/openimsdk/tools/errs"
+	"golang.org/x/net/html"
+)
+
+const (
+	maxBodySize    = 512 * 1024
+	maxURLLength   = 2048
+	maxRedirects   = 3
+	fetchTimeout   = 5 * time.Second
+	maxTitleLength = 256
+	maxDescLength  = 1024
+)
+
+var urlRegexp = regexp.MustCompile(`(?i)\bhttps?://[^\s<>"]+`)
+
+// carrierGradeNAT is the shared address space of RFC 6598, not reachable from the internet either.
+var carrierGradeNAT = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+var client = newClient(checkPublicAddress)
+
+func newClient(control func(network, address string, c syscall.RawConn) error) *http.Client {
+	dialer := &net.Dialer{Timeout: fetchTimeout, Control: control}
+	return &http.Client{
+		Timeout:   fetchTimeout,
+		Transport: &http.Transport{DialContext: dialer.DialContext, TLSHandshakeTimeout: fetchTimeout},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return http.ErrUseLastResponse
+			}
+			return nil
+		},
+	}
+}
+
+// checkPublicAddress refuses to connect to the loopback, private and link-local addresses, so that a
+// link sent by someone else can not make the device probe its own network. It runs on the resolved
+// address of every connection, the redirected ones included.
+func checkPublicAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return errs.WrapMsg(err, "invalid address", "address", address)
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !isPublicIP(ip) {
+		return errs.New("link preview address is not public", "network", network, "address", address).Wrap()
+	}
+	return nil
+}
+
+func isPublicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || carrierGradeNAT.Contains(ip))
+}
+
+// ExtractURLEntities returns a url entity for every http(s) link in text. Offset and Length are
+// counted in UTF-16 code units, the unit the client platforms use to slice strings.
+func ExtractURLEntities(text string) []*sdk_struct.MessageEntity {
+	var entities []*sdk_struct.MessageEntity
+	for _, loc := range urlRegexp.FindAllStringIndex(text, -1) {
+		rawURL := trimURL(text[loc[0]:loc[1]])
+		if len(rawURL) > maxURLLength {
+			continue
+		}
+		if u, err := url.Parse(rawURL); err != nil || u.Host == "" {
+			continue
+		}
+		entities = append(entities, &sdk_struct.MessageEntity{
+			Type:   constant.MessageEntityTypeURL,
+			Offset: utf16Length(text[:loc[0]]),
+			Length: utf16Length(rawURL),
+			Url:    rawURL,
+		})
+	}
+	return entities
+}
+
+// trimURL drops the trailing punctuation that usually ends the sentence rather than the link,
+// closing brackets are kept when the link itself opened them.
+func trimURL(rawURL string) string {
+	for len(rawURL) > 0 {
+		last := rawURL[len(rawURL)-1]
+		switch last {
+		case '.', ',', ';', ':', '!', '?', '\'':
+		case ')':
+			if strings.Count(rawURL, "(") >= strings.Count(rawURL, ")") {
+				return rawURL
+			}
+		case ']':
+			if strings.Count(rawURL, "[") >= strings.Count(rawURL, "]") {
+				return rawURL
+			}
+		default:
+			return rawURL
+		}
+		rawURL = rawURL[:len(rawURL)-1]
+	}
+	return rawURL
+}
+
+func utf16Length(s string) int32 {
+	return int32(len(utf16.Encode([]rune(s))))
+}
+
+// Fetch downloads the page at rawURL and builds its preview. Redirects, the response time and
+// the number of bytes read are all limited, so a slow or huge page can not stall the caller, and
+// only public addresses are connected to.
+func Fetch(ctx context.Context, rawURL string) (*sdk_struct.LinkPreview, error) {
+	return fetch(ctx, client, rawURL)
+}
+
+func fetch(ctx context.Context, client *http.Client, rawURL string) (*sdk_struct.LinkPreview, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, errs.WrapMsg(err, "parse url failed", "url", rawURL)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, errs.New("unsupported url scheme", "url", rawURL).Wrap()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, errs.WrapMsg(err, "new request failed", "url", rawURL)
+	}
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, errs.WrapMsg(err, "fetch url failed", "url", rawURL)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errs.New("unexpected status code", "url", rawURL, "statusCode", resp.StatusCode).Wrap()
+	}
+	if contentType := resp.Header.Get("Content-Type"); contentType != "" && !strings.Contains(contentType, "html") {
+		return nil, errs.New("url is not a html page", "url", rawURL, "contentType", contentType).Wrap()
+	}
+	preview := Parse(resp.Request.URL, io.LimitReader(resp.Body, maxBodySize))
+	preview.URL = rawURL
+	return preview, nil
+}
+
+// Parse reads the preview from the head of a html document. OpenGraph tags win over the title
+// and description tags, relative image links are resolved against base.
+func Parse(base *url.URL, r io.Reader) *sdk_struct.LinkPreview {
+	preview := &sdk_struct.LinkPreview{URL: base.String()}
+	var title, desc string
+	tokenizer := html.NewTokenizer(r)
+loop:
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			break loop
+		case html.EndTagToken:
+			if name, _ := tokenizer.TagName(); string(name) == "head" {
+				break loop
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := tokenizer.TagName()
+			switch string(name) {
+			case "body":
+				break loop
+			case "title":
+				if tokenizer.Next() == html.TextToken {
+					title = strings.TrimSpace(string(tokenizer.Text()))
+				}
+			case "meta":
+				var key, content string
+				for hasAttr {
+					var k, v []byte
+					k, v, hasAttr = tokenizer.TagAttr()
+					switch string(k) {
+					case "property", "name":
+						if key == "" {
+							key = strings.ToLower(string(v))
+						}
+					case "content":
+						content = strings.TrimSpace(string(v))
+					}
+				}
+				switch key {
+				case "og:title":
+					preview.Title = content
+				case "og:description":
+					preview.Description = content
+				case "og:site_name":
+					preview.SiteName = content
+				case "og:image", "og:image:url":
+					if image, err := base.Parse(content); err == nil && preview.ImageURL == "" {
+						preview.ImageURL = image.String()
+					}
+				case "description":
+					desc = content
+				}
+			}
+		}
+	}
+	if preview.Title == "" {
+		preview.Title = title
+	}
+	if preview.Description == "" {
+		preview.Description = desc
+	}
+	preview.Title = truncate(preview.Title, maxTitleLength)
+	preview.Description = truncate(preview.Description, maxDescLength)
+	return preview
+}
+
+func truncate(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n])
+	}
+	return s
+}
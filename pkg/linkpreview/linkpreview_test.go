@@ -0,0 +1,66 @@
+package linkpreview
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestExtractURLEntities(t *testing.T) {
+	text := "😀 see https://example.com/a_(b), and http://openim.io."
+	entities := ExtractURLEntities(text)
+	if len(entities) != 2 {
+		t.Fatalf("unexpected entities %v", entities)
+	}
+	if entities[0].Url != "https://example.com/a_(b)" || entities[0].Offset != 7 || entities[0].Length != 25 {
+		t.Fatalf("unexpected entity %+v", entities[0])
+	}
+	if entities[1].Url != "http://openim.io" {
+		t.Fatalf("unexpected entity %+v", entities[1])
+	}
+}
+
+func TestFetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(`<html><head><title>Fallback</title>
+<meta property="og:title" content="OpenIM">
+<meta name="description" content="Instant messaging">
+<meta property="og:image" content="/logo.png">
+</head><body><meta property="og:site_name" content="ignored"></body></html>`))
+	}))
+	defer server.Close()
+	if _, err := Fetch(context.Background(), server.URL+"/page"); err == nil {
+		t.Fatal("expected the loopback address to be refused")
+	}
+	preview, err := fetch(context.Background(), newClient(nil), server.URL+"/page")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if preview.Title != "OpenIM" || preview.Description != "Instant messaging" ||
+		preview.ImageURL != server.URL+"/logo.png" || preview.SiteName != "" {
+		t.Fatalf("unexpected preview %+v", preview)
+	}
+}
+
+func TestIsPublicIP(t *testing.T) {
+	for address, public := range map[string]bool{
+		"8.8.8.8":      true,
+		"2606:4700::1": true,
+		"127.0.0.1":    false,
+		"10.1.2.3":     false,
+		"192.168.1.1":  false,
+		"169.254.1.1":  false,
+		"100.64.0.1":   false,
+		"0.0.0.0":      false,
+		"::1":          false,
+		"fe80::1":      false,
+		"fd00::1":      false,
+	} {
+		if isPublicIP(net.ParseIP(address)) != public {
+			t.Errorf("isPublicIP(%s) != %v", address, public)
+		}
+	}
+}
@@ -15,6 +15,7 @@
 package sdk_params_callback
 
 import (
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
 	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
 )
 
@@ -43,6 +44,36 @@ type GetAdvancedHistoryMessageListCallback struct {
 	ErrMsg      string                  `json:"errMsg"`
 }
 
+type QueryConversationListCallback struct {
+	ConversationList []*model_struct.LocalConversation `json:"conversationList"`
+	NextCursor       string                            `json:"nextCursor"`
+	IsEnd            bool                              `json:"isEnd"`
+}
+
+type GetMessageListAroundAnchorParams struct {
+	ConversationID string `json:"conversationID"`
+	// The anchor is located by ClientMsgID, or by Seq when ClientMsgID is empty.
+	ClientMsgID string `json:"clientMsgID"`
+	Seq         int64  `json:"seq"`
+	BeforeCount int    `json:"beforeCount"`
+	AfterCount  int    `json:"afterCount"`
+}
+
+type GetMessageListAroundAnchorCallback struct {
+	MessageList []*sdk_struct.MsgStruct `json:"messageList"`
+	// AnchorIndex is the position of the anchor in MessageList, -1 if it has been deleted.
+	AnchorIndex int `json:"anchorIndex"`
+	// StartClientMsgID and LastMinSeq continue the window backwards with GetAdvancedHistoryMessageList,
+	// EndClientMsgID continues it forwards with GetAdvancedHistoryMessageListReverse.
+	StartClientMsgID string `json:"startClientMsgID"`
+	EndClientMsgID   string `json:"endClientMsgID"`
+	LastMinSeq       int64  `json:"lastMinSeq"`
+	IsStart          bool   `json:"isStart"`
+	IsEnd            bool   `json:"isEnd"`
+	ErrCode          int32  `json:"errCode"`
+	ErrMsg           string `json:"errMsg"`
+}
+
 type SearchLocalMessagesParams struct {
 	ConversationID       string   `json:"conversationID"`
 	KeywordList          []string `json:"keywordList"`
@@ -69,3 +100,148 @@ type SearchByConversationResult struct {
 	MessageCount      int                     `json:"messageCount"`
 	MessageList       []*sdk_struct.MsgStruct `json:"messageList"`
 }
+
+// GlobalSearchParams searches several sections at once. An empty SectionList searches all sections.
+// The sender, time and content type filters apply to messages, the conversation type filter applies
+// to messages and conversations. Cursors holds the NextCursor of each section from the previous page.
+type GlobalSearchParams struct {
+	KeywordList          []string          `json:"keywordList"`
+	KeywordListMatchType int               `json:"keywordListMatchType"`
+	SectionList          []string          `json:"sectionList"`
+	SenderUserIDList     []string          `json:"senderUserIDList"`
+	StartTime            int64             `json:"startTime"`
+	EndTime              int64             `json:"endTime"`
+	MessageTypeList      []int             `json:"messageTypeList"`
+	ConversationTypeList []int32           `json:"conversationTypeList"`
+	Cursors              map[string]string `json:"cursors"`
+	Count                int               `json:"count"`
+}
+
+type GlobalSearchCallback struct {
+	// SectionList is the searched sections with results, the most relevant first.
+	SectionList   []string                   `json:"sectionList"`
+	Messages      *GlobalSearchMessages      `json:"messages,omitempty"`
+	Friends       *GlobalSearchFriends       `json:"friends,omitempty"`
+	Groups        *GlobalSearchGroups        `json:"groups,omitempty"`
+	GroupMembers  *GlobalSearchGroupMembers  `json:"groupMembers,omitempty"`
+	Conversations *GlobalSearchConversations `json:"conversations,omitempty"`
+	// SectionErrors holds the error of every section that failed, the other sections are still returned.
+	SectionErrors map[string]string `json:"sectionErrors,omitempty"`
+}
+
+type GlobalSearchPage struct {
+	TotalCount int    `json:"totalCount"`
+	NextCursor string `json:"nextCursor"`
+	IsEnd      bool   `json:"isEnd"`
+}
+
+type GlobalSearchMessageItem struct {
+	ConversationID   string                `json:"conversationID"`
+	ConversationType int32                 `json:"conversationType"`
+	ShowName         string                `json:"showName"`
+	FaceURL          string                `json:"faceURL"`
+	Message          *sdk_struct.MsgStruct `json:"message"`
+}
+
+type GlobalSearchMessages struct {
+	GlobalSearchPage
+	MessageList []*GlobalSearchMessageItem `json:"messageList"`
+}
+
+type GlobalSearchFriends struct {
+	GlobalSearchPage
+	FriendList []*SearchFriendItem `json:"friendList"`
+}
+
+type GlobalSearchGroups struct {
+	GlobalSearchPage
+	GroupList []*model_struct.LocalGroup `json:"groupList"`
+}
+
+type GlobalSearchGroupMembers struct {
+	GlobalSearchPage
+	GroupMemberList []*model_struct.LocalGroupMember `json:"groupMemberList"`
+}
+
+type GlobalSearchConversations struct {
+	GlobalSearchPage
+	ConversationList []*model_struct.LocalConversation `json:"conversationList"`
+}
+
+type ExportConversationHistoryParams struct {
+	// ConversationIDList exports all conversations when empty
+	ConversationIDList []string `json:"conversationIDList"`
+	ArchivePath        string   `json:"archivePath"`
+	// DownloadMedia stores the media in the archive, otherwise only their urls are kept
+	DownloadMedia bool `json:"downloadMedia"`
+}
+
+type ExportConversationHistoryCallback struct {
+	ArchivePath       string `json:"archivePath"`
+	ConversationCount int    `json:"conversationCount"`
+	MessageCount      int    `json:"messageCount"`
+	MediaCount        int    `json:"mediaCount"`
+	FailedMediaCount  int    `json:"failedMediaCount"`
+}
+
+type ImportConversationHistoryCallback struct {
+	ConversationCount int `json:"conversationCount"`
+	MessageCount      int `json:"messageCount"`
+	SkippedCount      int `json:"skippedCount"`
+}
+
+type ForwardTarget struct {
+	RecvID  string `json:"recvID"`
+	GroupID string `json:"groupID"`
+}
+
+type ForwardMessageResult struct {
+	// SourceClientMsgID is empty for the merger message
+	SourceClientMsgID string                `json:"sourceClientMsgID"`
+	Message           *sdk_struct.MsgStruct `json:"message"`
+	ErrCode           int32                 `json:"errCode"`
+	ErrMsg            string                `json:"errMsg"`
+}
+
+type ForwardTargetResult struct {
+	ForwardTarget
+	ResultList []*ForwardMessageResult `json:"resultList"`
+}
+
+type ForwardMessagesCallback struct {
+	TargetList []*ForwardTargetResult `json:"targetList"`
+}
+
+type GetMessageReadMembersCallback struct {
+	HasReadMemberList []*model_struct.LocalGroupMember `json:"hasReadMemberList"`
+	UnreadMemberList  []*model_struct.LocalGroupMember `json:"unreadMemberList"`
+	HasReadCount      int                              `json:"hasReadCount"`
+	UnreadCount       int                              `json:"unreadCount"`
+}
+
+type GetStarredMessagesParams struct {
+	// ConversationID limits the list to one conversation when set
+	ConversationID string `json:"conversationID"`
+	// KeywordList matches the message text, the sender name or a tag, any keyword is enough
+	KeywordList []string `json:"keywordList"`
+	// TagList keeps the messages having all the tags
+	TagList []string `json:"tagList"`
+	Offset  int      `json:"offset"`
+	Count   int      `json:"count"`
+}
+
+type GetStarredMessagesCallback struct {
+	TotalCount         int                          `json:"totalCount"`
+	StarredMessageList []*sdk_struct.StarredMessage `json:"starredMessageList"`
+}
+
+type MessageOperationResult struct {
+	ClientMsgID string `json:"clientMsgID"`
+	ErrCode     int32  `json:"errCode"`
+	ErrMsg      string `json:"errMsg"`
+}
+
+type BatchMessageOperationCallback struct {
+	SuccessClientMsgIDList []string                  `json:"successClientMsgIDList"`
+	FailedList             []*MessageOperationResult `json:"failedList"`
+}
@@ -39,13 +39,20 @@ const (
 	MsgRepeatError                = 10204 // Message repeated
 	MsgContentTypeNotSupportError = 10205 // Message content type not supported
 	MsgHasNoSeqError              = 10206 // Message does not have a sequence number
+	PollClosedError               = 10207 // Poll has passed its deadline
+	MsgInterceptedError           = 10208 // Message rejected by an interceptor
+	MsgSensitiveWordError         = 10209 // Message contains sensitive words
 
 	// Conversation-related errors
 	NotSupportOptError  = 10301 // Operation not supported
 	NotSupportTypeError = 10302 // Type not supported
 	UnreadCountError    = 10303 // Unread count is zero
 
+	ConversationFolderNotFoundError = 10304 // Conversation folder not found
+	StarredMessageNotFoundError     = 10305 // Message is not starred
+
 	// Group-related errors
 	GroupIDNotFoundError = 10400 // GroupID not found
 	GroupTypeErr         = 10401 // Invalid group type
+	NoPermissionError    = 10402 // Operation requires group owner or administrator
 )
@@ -37,14 +37,21 @@ var (
 	ErrMsgRepeated              = errs.NewCodeError(MsgRepeatError, "Only failed messages can be resent")
 	ErrMsgContentTypeNotSupport = errs.NewCodeError(MsgContentTypeNotSupportError, "Message content type not supported")
 	ErrMsgHasNoSeq              = errs.NewCodeError(MsgHasNoSeqError, "Message has no sequence number")
+	ErrPollClosed               = errs.NewCodeError(PollClosedError, "Poll is closed")
+	ErrMsgIntercepted           = errs.NewCodeError(MsgInterceptedError, "Message rejected by interceptor")
+	ErrMsgSensitiveWord         = errs.NewCodeError(MsgSensitiveWordError, "Message contains sensitive words")
 
 	// Conversation-related errors
 	ErrNotSupportOpt  = errs.NewCodeError(NotSupportOptError, "Operation not supported for supergroup")
 	ErrNotSupportType = errs.NewCodeError(NotSupportTypeError, "Only supergroup type supported")
 	ErrUnreadCount    = errs.NewCodeError(UnreadCountError, "Unread count is zero")
 
+	ErrConversationFolderNotFound = errs.NewCodeError(ConversationFolderNotFoundError, "Conversation folder not found")
+	ErrStarredMessageNotFound     = errs.NewCodeError(StarredMessageNotFoundError, "Message is not starred")
+
 	// Group-related errors
-	ErrGroupType = errs.NewCodeError(GroupTypeErr, "Invalid group type")
+	ErrGroupType    = errs.NewCodeError(GroupTypeErr, "Invalid group type")
+	ErrNoPermission = errs.NewCodeError(NoPermissionError, "Only group owner or administrator can do this")
 
 	ErrLoginOut    = errs.NewCodeError(LoginOutError, "User has logged out")
 	ErrLoginRepeat = errs.NewCodeError(LoginRepeatError, "User has logged in repeatedly")
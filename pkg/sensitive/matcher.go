@@ -0,0 +1,143 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sensitive finds sensitive words in text with an Aho-Corasick automaton, so the cost of
+// a lookup depends on the length of the text and not on the size of the word list.
+package sensitive
+
+import (
+	"strings"
+	"unicode"
+)
+
+type node struct {
+	children map[rune]int
+	fail     int
+	// depth is the length in runes of the longest word ending at this node, 0 if none does.
+	depth int
+}
+
+// Matcher is immutable once built and safe for concurrent use. Matching ignores letter case.
+type Matcher struct {
+	nodes []node
+}
+
+// Match is a found word, Start and End are rune indexes of the text with End excluded.
+type Match struct {
+	Start int
+	End   int
+}
+
+func NewMatcher(words []string) *Matcher {
+	m := &Matcher{nodes: []node{{children: make(map[rune]int)}}}
+	for _, word := range words {
+		word = strings.TrimSpace(word)
+		if word == "" {
+			continue
+		}
+		cur, length := 0, 0
+		for _, r := range word {
+			r = unicode.ToLower(r)
+			next, ok := m.nodes[cur].children[r]
+			if !ok {
+				next = len(m.nodes)
+				m.nodes = append(m.nodes, node{children: make(map[rune]int)})
+				m.nodes[cur].children[r] = next
+			}
+			cur = next
+			length++
+		}
+		m.nodes[cur].depth = length
+	}
+	m.buildFail()
+	return m
+}
+
+// buildFail links every node to the longest proper suffix that is also in the trie, breadth first.
+func (m *Matcher) buildFail() {
+	queue := make([]int, 0, len(m.nodes))
+	for _, child := range m.nodes[0].children {
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for r, child := range m.nodes[cur].children {
+			fail := m.nodes[cur].fail
+			for fail != 0 {
+				if _, ok := m.nodes[fail].children[r]; ok {
+					break
+				}
+				fail = m.nodes[fail].fail
+			}
+			if next, ok := m.nodes[fail].children[r]; ok && next != child {
+				m.nodes[child].fail = next
+			}
+			if d := m.nodes[m.nodes[child].fail].depth; d > m.nodes[child].depth {
+				m.nodes[child].depth = d
+			}
+			queue = append(queue, child)
+		}
+	}
+}
+
+// Empty reports whether the matcher has no words.
+func (m *Matcher) Empty() bool {
+	return m == nil || len(m.nodes) <= 1
+}
+
+// FindAll returns the longest word ending at every position of text where one ends.
+func (m *Matcher) FindAll(text string) []Match {
+	if m.Empty() {
+		return nil
+	}
+	var matches []Match
+	cur := 0
+	for i, r := range []rune(text) {
+		r = unicode.ToLower(r)
+		for {
+			if next, ok := m.nodes[cur].children[r]; ok {
+				cur = next
+				break
+			}
+			if cur == 0 {
+				break
+			}
+			cur = m.nodes[cur].fail
+		}
+		if depth := m.nodes[cur].depth; depth > 0 {
+			matches = append(matches, Match{Start: i + 1 - depth, End: i + 1})
+		}
+	}
+	return matches
+}
+
+func (m *Matcher) Contains(text string) bool {
+	return len(m.FindAll(text)) > 0
+}
+
+// Replace masks every rune of the found words with mask.
+func (m *Matcher) Replace(text string, mask rune) string {
+	matches := m.FindAll(text)
+	if len(matches) == 0 {
+		return text
+	}
+	runes := []rune(text)
+	for _, match := range matches {
+		for i := match.Start; i < match.End; i++ {
+			runes[i] = mask
+		}
+	}
+	return string(runes)
+}
@@ -0,0 +1,23 @@
+package sensitive
+
+import "testing"
+
+func TestMatcher(t *testing.T) {
+	m := NewMatcher([]string{"he", "she", "hers", "坏人", " "})
+	if m.Empty() {
+		t.Fatal("matcher should not be empty")
+	}
+	if got := m.Replace("USHERS and 坏人们", '*'); got != "U***** and **们" {
+		t.Fatalf("unexpected replace result %q", got)
+	}
+	if m.Contains("a clean text") {
+		t.Fatal("clean text should not match")
+	}
+	matches := m.FindAll("shers")
+	if len(matches) != 2 || matches[0] != (Match{Start: 0, End: 3}) || matches[1] != (Match{Start: 1, End: 5}) {
+		t.Fatalf("unexpected matches %v", matches)
+	}
+	if !NewMatcher(nil).Empty() {
+		t.Fatal("matcher without words should be empty")
+	}
+}
@@ -29,6 +29,21 @@ type MessageReceipt struct {
 	ContentType int32    `json:"contentType"`
 	SessionType int32    `json:"sessionType"`
 }
+type MessageDeliveryReceipt struct {
+	ConversationID string   `json:"conversationID"`
+	GroupID        string   `json:"groupID"`
+	UserID         string   `json:"userID"`
+	MsgIDList      []string `json:"msgIDList"`
+	DeliverTime    int64    `json:"deliverTime"`
+	SessionType    int32    `json:"sessionType"`
+}
+
+// DeliveryReceiptElem is sent by a recipient to the sender of the messages it stored, it is kept in
+// the history so that an offline sender gets it too.
+type DeliveryReceiptElem struct {
+	GroupID      string   `json:"groupID,omitempty"`
+	ClientMsgIDs []string `json:"clientMsgIDs"`
+}
 type MessageRevoked struct {
 	RevokerID                   string `json:"revokerID"`
 	RevokerRole                 int32  `json:"revokerRole"`
@@ -43,6 +58,35 @@ type MessageRevoked struct {
 	Ex                          string `json:"ex"`
 	IsAdminRevoke               bool   `json:"isAdminRevoke"`
 }
+type PinnedMessageTips struct {
+	ConversationID string `json:"conversationID"`
+	ClientMsgID    string `json:"clientMsgID"`
+	Seq            int64  `json:"seq"`
+	OpUserID       string `json:"opUserID"`
+	IsPinned       bool   `json:"isPinned"`
+	OpTime         int64  `json:"opTime"`
+}
+type PollVoteTips struct {
+	ConversationID string   `json:"conversationID"`
+	ClientMsgID    string   `json:"clientMsgID"`
+	Seq            int64    `json:"seq"`
+	VoterID        string   `json:"voterID,omitempty"`
+	OptionIDList   []string `json:"optionIDList"`
+	VoteTime       int64    `json:"voteTime"`
+}
+
+// PollResultTips carries the counts of an anonymous poll, sent by the poll creator who is the only
+// one receiving its votes.
+type PollResultTips struct {
+	ConversationID string      `json:"conversationID"`
+	ClientMsgID    string      `json:"clientMsgID"`
+	Result         *PollResult `json:"result"`
+}
+type PollResultChanged struct {
+	ConversationID string      `json:"conversationID"`
+	ClientMsgID    string      `json:"clientMsgID"`
+	Result         *PollResult `json:"result"`
+}
 type MessageReaction struct {
 	ClientMsgID  string `json:"clientMsgID"`
 	ReactionType int    `json:"reactionType"`
@@ -99,7 +143,9 @@ type FileBaseInfo struct {
 }
 
 type TextElem struct {
-	Content string `json:"content"`
+	Content           string           `json:"content"`
+	MessageEntityList []*MessageEntity `json:"messageEntityList,omitempty"`
+	LinkPreview       *LinkPreview     `json:"linkPreview,omitempty"`
 }
 
 type CardElem struct {
@@ -188,6 +234,40 @@ type QuoteElem struct {
 	MessageEntityList []*MessageEntity `json:"messageEntityList,omitempty"`
 }
 
+type PollOption struct {
+	OptionID string `json:"optionID"`
+	Text     string `json:"text"`
+}
+
+type PollOptionResult struct {
+	OptionID    string   `json:"optionID"`
+	VoteCount   int32    `json:"voteCount"`
+	VoterIDList []string `json:"voterIDList,omitempty"`
+}
+
+type PollResult struct {
+	VoterCount       int32               `json:"voterCount"`
+	OptionResultList []*PollOptionResult `json:"optionResultList,omitempty"`
+	SelfOptionIDList []string            `json:"selfOptionIDList,omitempty"`
+}
+
+type PollElem struct {
+	Question    string        `json:"question"`
+	OptionList  []*PollOption `json:"optionList"`
+	IsMultiple  bool          `json:"isMultiple"`
+	IsAnonymous bool          `json:"isAnonymous"`
+	Deadline    int64         `json:"deadline"`
+	Result      *PollResult   `json:"result,omitempty"`
+}
+
+type LinkPreview struct {
+	URL         string `json:"url"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	ImageURL    string `json:"imageURL,omitempty"`
+	SiteName    string `json:"siteName,omitempty"`
+}
+
 type NotificationElem struct {
 	Detail string `json:"detail,omitempty"`
 }
@@ -195,10 +275,23 @@ type NotificationElem struct {
 type AdvancedTextElem struct {
 	Text              string           `json:"text,omitempty"`
 	MessageEntityList []*MessageEntity `json:"messageEntityList,omitempty"`
+	LinkPreview       *LinkPreview     `json:"linkPreview,omitempty"`
+}
+
+// LiveLocationElem is sent online only while a user shares their location. ExpireTime is when the
+// sharing ends, Stopped is set on the last message of a session.
+type LiveLocationElem struct {
+	SessionID  string  `json:"sessionID"`
+	Longitude  float64 `json:"longitude"`
+	Latitude   float64 `json:"latitude"`
+	Accuracy   float64 `json:"accuracy,omitempty"`
+	ExpireTime int64   `json:"expireTime"`
+	Stopped    bool    `json:"stopped,omitempty"`
 }
 
 type TypingElem struct {
-	MsgTips string `json:"msgTips,omitempty"`
+	MsgTips  string `json:"msgTips,omitempty"`
+	Activity int32  `json:"activity,omitempty"`
 }
 
 type MsgStruct struct {
@@ -240,6 +333,9 @@ type MsgStruct struct {
 	NotificationElem     *NotificationElem      `json:"notificationElem,omitempty"`
 	AdvancedTextElem     *AdvancedTextElem      `json:"advancedTextElem,omitempty"`
 	TypingElem           *TypingElem            `json:"typingElem,omitempty"`
+	LiveLocationElem     *LiveLocationElem      `json:"liveLocationElem,omitempty"`
+	PollElem             *PollElem              `json:"pollElem,omitempty"`
+	LinkPreview          *LinkPreview           `json:"linkPreview,omitempty"`
 	AttachedInfoElem     *AttachedInfoElem      `json:"attachedInfoElem,omitempty"`
 }
 
@@ -255,6 +351,10 @@ type AttachedInfoElem struct {
 	MessageEntityList []*MessageEntity `json:"messageEntityList,omitempty"`
 	IsEncryption      bool             `json:"isEncryption"`
 	InEncryptStatus   bool             `json:"inEncryptStatus"`
+	IsSensitive       bool             `json:"isSensitive,omitempty"`
+	// DeliveredTime is when the message first reached a device of a recipient
+	DeliveredTime       int64    `json:"deliveredTime,omitempty"`
+	DeliveredUserIDList []string `json:"deliveredUserIDList,omitempty"`
 	//MessageReactionElem       []*ReactionElem  `json:"messageReactionElem,omitempty"`
 	Progress *UploadProgress `json:"uploadProgress,omitempty"`
 }
@@ -317,6 +417,143 @@ type IMConfig struct {
 	IsLogStandardOutput  bool   `json:"isLogStandardOutput"`
 	LogFilePath          string `json:"logFilePath"`
 	IsExternalExtensions bool   `json:"isExternalExtensions"`
+	EnableLinkPreview    bool   `json:"enableLinkPreview"`
+	// OfflinePushTemplates enables the offline push generated for messages sent without one, it
+	// puts the message text in the push payload. OfflinePushLanguage selects the template of the
+	// fallback text, e.g. "zh-CN" or "zh", the recipient device localises it with OfflinePushLocalization.
+	OfflinePushLanguage  string                          `json:"offlinePushLanguage"`
+	OfflinePushTemplates map[string]*OfflinePushTemplate `json:"offlinePushTemplates"`
+}
+
+// OfflinePushTemplate localises the offline push generated for messages sent without one. The
+// placeholders {sender}, {group}, {text} and {desc} are replaced, Desc is keyed by the names in
+// constant.OfflinePushDesc*, the missing entries are taken from the built-in English template.
+type OfflinePushTemplate struct {
+	Title      string            `json:"title"`
+	GroupTitle string            `json:"groupTitle"`
+	GroupDesc  string            `json:"groupDesc"`
+	Desc       map[string]string `json:"desc"`
+}
+
+// OfflinePushLocalization is set as the Ex of the generated offline push, so that the recipient
+// device can build the push in its own language instead of the fallback text of the sender.
+type OfflinePushLocalization struct {
+	DescKey string `json:"descKey"`
+	IsGroup bool   `json:"isGroup"`
+	Sender  string `json:"sender"`
+	Group   string `json:"group,omitempty"`
+	Text    string `json:"text,omitempty"`
+}
+
+// ConversationFolder groups the conversations explicitly included and the ones matching its rules,
+// unless they are explicitly excluded. A folder without rules only holds the included conversations.
+type ConversationFolder struct {
+	FolderID               string   `json:"folderID"`
+	Name                   string   `json:"name"`
+	Order                  int32    `json:"order"`
+	SessionTypeList        []int32  `json:"sessionTypeList"`
+	MutedFilter            int32    `json:"mutedFilter"`
+	UnreadFilter           int32    `json:"unreadFilter"`
+	IncludeConversationIDs []string `json:"includeConversationIDs"`
+	ExcludeConversationIDs []string `json:"excludeConversationIDs"`
+}
+
+// ConversationQuery filters and sorts the visible conversations. PinnedFilter and MutedFilter take
+// the constant.ConversationQueryFilter* values, SortBy the constant.ConversationSortBy* ones. Cursor
+// is the NextCursor of the previous page, empty for the first one.
+type ConversationQuery struct {
+	SessionTypeList []int32  `json:"sessionTypeList"`
+	GroupIDList     []string `json:"groupIDList"`
+	UnreadOnly      bool     `json:"unreadOnly"`
+	MentionOnly     bool     `json:"mentionOnly"`
+	HasDraft        bool     `json:"hasDraft"`
+	PinnedFilter    int32    `json:"pinnedFilter"`
+	MutedFilter     int32    `json:"mutedFilter"`
+	SortBy          int32    `json:"sortBy"`
+	PinnedFirst     bool     `json:"pinnedFirst"`
+	Cursor          string   `json:"cursor"`
+	Count           int      `json:"count"`
+}
+
+// ConversationCursor is the position of the last conversation of a page, SortKey holds the latest
+// message or draft time or the unread count and SortName the name, depending on the sort option.
+type ConversationCursor struct {
+	IsPinned       bool   `json:"isPinned"`
+	SortKey        int64  `json:"sortKey"`
+	SortName       string `json:"sortName"`
+	ConversationID string `json:"conversationID"`
+}
+
+// MessageSearchQuery filters the messages of a full text search across all conversations. The times
+// are in milliseconds.
+type MessageSearchQuery struct {
+	ContentTypeList      []int    `json:"contentTypeList"`
+	KeywordList          []string `json:"keywordList"`
+	KeywordListMatchType int      `json:"keywordListMatchType"`
+	SenderUserIDList     []string `json:"senderUserIDList"`
+	ConversationTypeList []int32  `json:"conversationTypeList"`
+	StartTime            int64    `json:"startTime"`
+	EndTime              int64    `json:"endTime"`
+}
+
+// StarredMessage is a message bookmarked by the user. Snapshot is taken when it was starred, so
+// that the entry outlives the message being deleted locally.
+type StarredMessage struct {
+	ConversationID string                  `json:"conversationID"`
+	ClientMsgID    string                  `json:"clientMsgID"`
+	TagList        []string                `json:"tagList"`
+	StarTime       int64                   `json:"starTime"`
+	Snapshot       *StarredMessageSnapshot `json:"snapshot"`
+}
+
+// StarredMessageSnapshot is the text view of a starred message and the metadata of its element,
+// the text is truncated so that the user command holding it stays small.
+type StarredMessageSnapshot struct {
+	SendID         string `json:"sendID"`
+	SenderNickname string `json:"senderNickname"`
+	SenderFaceURL  string `json:"senderFaceURL"`
+	SessionType    int32  `json:"sessionType"`
+	ContentType    int32  `json:"contentType"`
+	Seq            int64  `json:"seq"`
+	SendTime       int64  `json:"sendTime"`
+	Text           string `json:"text"`
+	ImageURL       string `json:"imageURL"`
+	LinkURL        string `json:"linkURL"`
+	FileName       string `json:"fileName"`
+	FileSize       int64  `json:"fileSize"`
+	Duration       int64  `json:"duration"`
+}
+
+type ConversationFolderUnreadCount struct {
+	FolderID    string `json:"folderID"`
+	UnreadCount int32  `json:"unreadCount"`
+}
+
+// UnreadCountBreakdown splits the unread messages of the visible conversations. Except MutedUnreadCount
+// and the mention counts, the counts leave out the conversations not notifying, the same as the total
+// unread count.
+type UnreadCountBreakdown struct {
+	TotalUnreadCount        int32 `json:"totalUnreadCount"`
+	SingleUnreadCount       int32 `json:"singleUnreadCount"`
+	GroupUnreadCount        int32 `json:"groupUnreadCount"`
+	NotificationUnreadCount int32 `json:"notificationUnreadCount"`
+	MutedUnreadCount        int32 `json:"mutedUnreadCount"`
+	// the number of conversations with unread messages mentioning the user or everyone, muted or not
+	AtMeConversationCount  int32 `json:"atMeConversationCount"`
+	AtAllConversationCount int32 `json:"atAllConversationCount"`
+}
+
+// ConversationDraft is the draft of a conversation, AttachmentPathList holds local file paths
+// which may not exist on the other devices of the user.
+type ConversationDraft struct {
+	ConversationID     string           `json:"conversationID"`
+	Text               string           `json:"text"`
+	MessageEntityList  []*MessageEntity `json:"messageEntityList,omitempty"`
+	QuoteClientMsgID   string           `json:"quoteClientMsgID,omitempty"`
+	AtUserIDList       []string         `json:"atUserIDList,omitempty"`
+	AtUsersInfo        []*AtInfo        `json:"atUsersInfo,omitempty"`
+	AttachmentPathList []string         `json:"attachmentPathList,omitempty"`
+	UpdateTime         int64            `json:"updateTime"`
 }
 
 type CmdNewMsgComeToConversation struct {
@@ -79,6 +79,26 @@ func (o *onConversationListener) OnConversationUserInputStatusChanged(change str
 	log.ZInfo(o.ctx, "OnConversationUserInputStatusChanged", "change", change)
 }
 
+func (o *onConversationListener) OnConversationLiveLocationChanged(change string) {
+	log.ZInfo(o.ctx, "OnConversationLiveLocationChanged", "change", change)
+}
+
+func (o *onConversationListener) OnConversationPatched(delta string) {
+	log.ZInfo(o.ctx, "OnConversationPatched", "delta", delta)
+}
+
+func (o *onConversationListener) OnConversationPinnedMessagesChanged(change string) {
+	log.ZInfo(o.ctx, "OnConversationPinnedMessagesChanged", "change", change)
+}
+
+func (o *onConversationListener) OnConversationFolderUnreadCountChanged(folderUnreadCounts string) {
+	log.ZInfo(o.ctx, "OnConversationFolderUnreadCountChanged", "folderUnreadCounts", folderUnreadCounts)
+}
+
+func (o *onConversationListener) OnUnreadCountBreakdownChanged(breakdown string) {
+	log.ZInfo(o.ctx, "OnUnreadCountBreakdownChanged", "breakdown", breakdown)
+}
+
 type onGroupListener struct {
 	ctx context.Context
 }
@@ -135,6 +155,14 @@ func (o *onAdvancedMsgListener) OnRecvOnlineOnlyMessage(message string) {
 	log.ZDebug(o.ctx, "OnRecvOnlineOnlyMessage", "message", message)
 }
 
+func (o *onAdvancedMsgListener) OnPollResultChanged(pollResult string) {
+	log.ZInfo(o.ctx, "OnPollResultChanged", "pollResult", pollResult)
+}
+
+func (o *onAdvancedMsgListener) OnRecvMessageDelivered(deliveryReceiptList string) {
+	log.ZInfo(o.ctx, "OnRecvMessageDelivered", "deliveryReceiptList", deliveryReceiptList)
+}
+
 func (o *onAdvancedMsgListener) OnRecvOfflineNewMessage(message string) {
 	//TODO implement me
 	panic("implement me")
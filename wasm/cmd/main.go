@@ -59,6 +59,7 @@ func registerFunc() {
 	js.Global().Set("createVideoMessageByURL", js.FuncOf(wrapperConMsg.CreateVideoMessageByURL))
 	js.Global().Set("createFileMessageByURL", js.FuncOf(wrapperConMsg.CreateFileMessageByURL))
 	js.Global().Set("createCustomMessage", js.FuncOf(wrapperConMsg.CreateCustomMessage))
+	js.Global().Set("createPollMessage", js.FuncOf(wrapperConMsg.CreatePollMessage))
 	js.Global().Set("createQuoteMessage", js.FuncOf(wrapperConMsg.CreateQuoteMessage))
 	js.Global().Set("createAdvancedQuoteMessage", js.FuncOf(wrapperConMsg.CreateAdvancedQuoteMessage))
 	js.Global().Set("createAdvancedTextMessage", js.FuncOf(wrapperConMsg.CreateAdvancedTextMessage))
@@ -82,9 +83,12 @@ func registerFunc() {
 	js.Global().Set("createImageMessageFromFullPath", js.FuncOf(wrapperConMsg.CreateImageMessageFromFullPath))
 	js.Global().Set("getAtAllTag", js.FuncOf(wrapperConMsg.GetAtAllTag))
 	js.Global().Set("markConversationMessageAsRead", js.FuncOf(wrapperConMsg.MarkConversationMessageAsRead))
+	js.Global().Set("markConversationAsUnread", js.FuncOf(wrapperConMsg.MarkConversationAsUnread))
 	js.Global().Set("markMessagesAsReadByMsgID", js.FuncOf(wrapperConMsg.MarkMessagesAsReadByMsgID))
+	js.Global().Set("getMessageReadMembers", js.FuncOf(wrapperConMsg.GetMessageReadMembers))
 	js.Global().Set("sendMessage", js.FuncOf(wrapperConMsg.SendMessage))
 	js.Global().Set("sendMessageNotOss", js.FuncOf(wrapperConMsg.SendMessageNotOss))
+	js.Global().Set("forwardMessages", js.FuncOf(wrapperConMsg.ForwardMessages))
 	//js.Global().Set("setMessageReactionExtensions", js.FuncOf(wrapperConMsg.SetMessageReactionExtensions))
 	//js.Global().Set("addMessageReactionExtensions", js.FuncOf(wrapperConMsg.AddMessageReactionExtensions))
 	//js.Global().Set("deleteMessageReactionExtensions", js.FuncOf(wrapperConMsg.DeleteMessageReactionExtensions))
@@ -92,22 +96,50 @@ func registerFunc() {
 	//js.Global().Set("getMessageListSomeReactionExtensions", js.FuncOf(wrapperConMsg.GetMessageListSomeReactionExtensions))
 	js.Global().Set("getAllConversationList", js.FuncOf(wrapperConMsg.GetAllConversationList))
 	js.Global().Set("getConversationListSplit", js.FuncOf(wrapperConMsg.GetConversationListSplit))
+	js.Global().Set("getConversationListSplitByFolder", js.FuncOf(wrapperConMsg.GetConversationListSplitByFolder))
+	js.Global().Set("queryConversationList", js.FuncOf(wrapperConMsg.QueryConversationList))
+	js.Global().Set("createConversationFolder", js.FuncOf(wrapperConMsg.CreateConversationFolder))
+	js.Global().Set("updateConversationFolder", js.FuncOf(wrapperConMsg.UpdateConversationFolder))
+	js.Global().Set("deleteConversationFolder", js.FuncOf(wrapperConMsg.DeleteConversationFolder))
+	js.Global().Set("getConversationFolders", js.FuncOf(wrapperConMsg.GetConversationFolders))
+	js.Global().Set("getConversationFolderUnreadCounts", js.FuncOf(wrapperConMsg.GetConversationFolderUnreadCounts))
+	js.Global().Set("starMessage", js.FuncOf(wrapperConMsg.StarMessage))
+	js.Global().Set("unstarMessage", js.FuncOf(wrapperConMsg.UnstarMessage))
+	js.Global().Set("setStarredMessageTags", js.FuncOf(wrapperConMsg.SetStarredMessageTags))
+	js.Global().Set("getStarredMessageList", js.FuncOf(wrapperConMsg.GetStarredMessageList))
+	js.Global().Set("getStarredMessageTags", js.FuncOf(wrapperConMsg.GetStarredMessageTags))
+	js.Global().Set("muteConversationUntil", js.FuncOf(wrapperConMsg.MuteConversationUntil))
+	js.Global().Set("snoozeConversationUntil", js.FuncOf(wrapperConMsg.SnoozeConversationUntil))
+	js.Global().Set("getConversationTimerList", js.FuncOf(wrapperConMsg.GetConversationTimerList))
 	js.Global().Set("getOneConversation", js.FuncOf(wrapperConMsg.GetOneConversation))
 	js.Global().Set("deleteConversationAndDeleteAllMsg", js.FuncOf(wrapperConMsg.DeleteConversationAndDeleteAllMsg))
 	js.Global().Set("getAdvancedHistoryMessageList", js.FuncOf(wrapperConMsg.GetAdvancedHistoryMessageList))
 	js.Global().Set("getAdvancedHistoryMessageListReverse", js.FuncOf(wrapperConMsg.GetAdvancedHistoryMessageListReverse))
+	js.Global().Set("getMessageListAroundAnchor", js.FuncOf(wrapperConMsg.GetMessageListAroundAnchor))
 	js.Global().Set("getMultipleConversation", js.FuncOf(wrapperConMsg.GetMultipleConversation))
 	js.Global().Set("hideConversation", js.FuncOf(wrapperConMsg.HideConversation))
 	js.Global().Set("setConversationDraft", js.FuncOf(wrapperConMsg.SetConversationDraft))
+	js.Global().Set("setConversationRichDraft", js.FuncOf(wrapperConMsg.SetConversationRichDraft))
+	js.Global().Set("getConversationRichDraft", js.FuncOf(wrapperConMsg.GetConversationRichDraft))
 	js.Global().Set("setConversation", js.FuncOf(wrapperConMsg.SetConversation))
 
 	js.Global().Set("getTotalUnreadMsgCount", js.FuncOf(wrapperConMsg.GetTotalUnreadMsgCount))
+	js.Global().Set("getUnreadCountBreakdown", js.FuncOf(wrapperConMsg.GetUnreadCountBreakdown))
 	js.Global().Set("findMessageList", js.FuncOf(wrapperConMsg.FindMessageList))
 
 	js.Global().Set("revokeMessage", js.FuncOf(wrapperConMsg.RevokeMessage))
+	js.Global().Set("revokeMessages", js.FuncOf(wrapperConMsg.RevokeMessages))
+	js.Global().Set("revokeUserMessages", js.FuncOf(wrapperConMsg.RevokeUserMessages))
+	js.Global().Set("pinMessage", js.FuncOf(wrapperConMsg.PinMessage))
+	js.Global().Set("unpinMessage", js.FuncOf(wrapperConMsg.UnpinMessage))
+	js.Global().Set("getPinnedMessageList", js.FuncOf(wrapperConMsg.GetPinnedMessageList))
+	js.Global().Set("votePoll", js.FuncOf(wrapperConMsg.VotePoll))
+	js.Global().Set("getLinkPreview", js.FuncOf(wrapperConMsg.GetLinkPreview))
+	js.Global().Set("setSensitiveWordFilter", js.FuncOf(wrapperConMsg.SetSensitiveWordFilter))
 	js.Global().Set("typingStatusUpdate", js.FuncOf(wrapperConMsg.TypingStatusUpdate))
 	js.Global().Set("deleteMessageFromLocalStorage", js.FuncOf(wrapperConMsg.DeleteMessageFromLocalStorage))
 	js.Global().Set("deleteMessage", js.FuncOf(wrapperConMsg.DeleteMessage))
+	js.Global().Set("deleteMessages", js.FuncOf(wrapperConMsg.DeleteMessages))
 	js.Global().Set("hideAllConversations", js.FuncOf(wrapperConMsg.HideAllConversations))
 	js.Global().Set("deleteAllMsgFromLocalAndSvr", js.FuncOf(wrapperConMsg.DeleteAllMsgFromLocalAndSvr))
 	js.Global().Set("deleteAllMsgFromLocal", js.FuncOf(wrapperConMsg.DeleteAllMsgFromLocal))
@@ -115,10 +147,18 @@ func registerFunc() {
 	js.Global().Set("insertSingleMessageToLocalStorage", js.FuncOf(wrapperConMsg.InsertSingleMessageToLocalStorage))
 	js.Global().Set("insertGroupMessageToLocalStorage", js.FuncOf(wrapperConMsg.InsertGroupMessageToLocalStorage))
 	js.Global().Set("searchLocalMessages", js.FuncOf(wrapperConMsg.SearchLocalMessages))
+	js.Global().Set("globalSearch", js.FuncOf(wrapperConMsg.GlobalSearch))
 	js.Global().Set("setMessageLocalEx", js.FuncOf(wrapperConMsg.SetMessageLocalEx))
 
 	js.Global().Set("changeInputStates", js.FuncOf(wrapperConMsg.ChangeInputStates))
 	js.Global().Set("getInputStates", js.FuncOf(wrapperConMsg.GetInputStates))
+	js.Global().Set("changeInputActivity", js.FuncOf(wrapperConMsg.ChangeInputActivity))
+	js.Global().Set("getConversationInputStates", js.FuncOf(wrapperConMsg.GetConversationInputStates))
+	js.Global().Set("startLiveLocation", js.FuncOf(wrapperConMsg.StartLiveLocation))
+	js.Global().Set("updateLiveLocation", js.FuncOf(wrapperConMsg.UpdateLiveLocation))
+	js.Global().Set("stopLiveLocation", js.FuncOf(wrapperConMsg.StopLiveLocation))
+	js.Global().Set("getLiveLocations", js.FuncOf(wrapperConMsg.GetLiveLocations))
+	js.Global().Set("setConversationDeltaMode", js.FuncOf(wrapperConMsg.SetConversationDeltaMode))
 
 	//register group func
 	wrapperGroup := wasm_wrapper.NewWrapperGroup(globalFuc)
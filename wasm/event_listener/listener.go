@@ -110,6 +110,26 @@ func (c ConversationCallback) OnConversationUserInputStatusChanged(change string
 	c.CallbackWriter.SetEvent(utils.GetSelfFuncName()).SetData(change).SendMessage()
 }
 
+func (c ConversationCallback) OnConversationLiveLocationChanged(change string) {
+	c.CallbackWriter.SetEvent(utils.GetSelfFuncName()).SetData(change).SendMessage()
+}
+
+func (c ConversationCallback) OnConversationPatched(delta string) {
+	c.CallbackWriter.SetEvent(utils.GetSelfFuncName()).SetData(delta).SendMessage()
+}
+
+func (c ConversationCallback) OnConversationPinnedMessagesChanged(change string) {
+	c.CallbackWriter.SetEvent(utils.GetSelfFuncName()).SetData(change).SendMessage()
+}
+
+func (c ConversationCallback) OnConversationFolderUnreadCountChanged(folderUnreadCounts string) {
+	c.CallbackWriter.SetEvent(utils.GetSelfFuncName()).SetData(folderUnreadCounts).SendMessage()
+}
+
+func (c ConversationCallback) OnUnreadCountBreakdownChanged(breakdown string) {
+	c.CallbackWriter.SetEvent(utils.GetSelfFuncName()).SetData(breakdown).SendMessage()
+}
+
 type AdvancedMsgCallback struct {
 	CallbackWriter
 }
@@ -170,6 +190,14 @@ func (a AdvancedMsgCallback) OnRecvOnlineOnlyMessage(message string) {
 	a.CallbackWriter.SetEvent(utils.GetSelfFuncName()).SetData(message).SendMessage()
 }
 
+func (a AdvancedMsgCallback) OnPollResultChanged(pollResult string) {
+	a.CallbackWriter.SetEvent(utils.GetSelfFuncName()).SetData(pollResult).SendMessage()
+}
+
+func (a AdvancedMsgCallback) OnRecvMessageDelivered(deliveryReceiptList string) {
+	a.CallbackWriter.SetEvent(utils.GetSelfFuncName()).SetData(deliveryReceiptList).SendMessage()
+}
+
 type BaseCallback struct {
 	CallbackWriter
 }
@@ -314,6 +342,9 @@ func (b *BatchMessageCallback) OnRecvNewMessages(messageList string) {
 func (b *BatchMessageCallback) OnRecvOfflineNewMessages(messageList string) {
 	b.CallbackWriter.SetEvent(utils.GetSelfFuncName()).SetData(messageList).SendMessage()
 }
+func (b *BatchMessageCallback) OnRecvMessagesRevoked(messageRevokedList string) {
+	b.CallbackWriter.SetEvent(utils.GetSelfFuncName()).SetData(messageRevokedList).SendMessage()
+}
 
 type FriendCallback struct {
 	CallbackWriter
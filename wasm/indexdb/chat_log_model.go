@@ -22,6 +22,7 @@ import (
 
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
 	"github.com/openimsdk/openim-sdk-core/v3/wasm/exec"
 	"github.com/openimsdk/openim-sdk-core/v3/wasm/indexdb/temp_struct"
 )
@@ -522,6 +523,41 @@ func (i *LocalChatLogs) SearchMessageByKeyword(ctx context.Context, contentType
 	}
 }
 
+func (i *LocalChatLogs) SearchMessageByFullText(ctx context.Context, contentType []int, keywordList []string, keywordListMatchType int, conversationID string, startTime, endTime int64, offset, count int) (result []*model_struct.LocalChatLog, err error) {
+	msgList, err := exec.Exec(conversationID, utils.StructToJsonString(contentType), utils.StructToJsonString(keywordList), keywordListMatchType, startTime, endTime, offset, count)
+	if err != nil {
+		return nil, err
+	} else {
+		if v, ok := msgList.(string); ok {
+			err := utils.JsonStringToStruct(v, &result)
+			if err != nil {
+				return nil, err
+			}
+			return result, err
+		} else {
+			return nil, exec.ErrType
+		}
+	}
+}
+
+func (i *LocalChatLogs) SearchMessagePageByFullText(ctx context.Context, query *sdk_struct.MessageSearchQuery, offset, count int) ([]*model_struct.LocalChatLog, int64, error) {
+	page, err := exec.Exec(utils.StructToJsonString(query), offset, count)
+	if err != nil {
+		return nil, 0, err
+	} else {
+		if v, ok := page.(string); ok {
+			var result temp_struct.MessageSearchPage
+			err := utils.JsonStringToStruct(v, &result)
+			if err != nil {
+				return nil, 0, err
+			}
+			return result.MessageList, result.TotalCount, err
+		} else {
+			return nil, 0, exec.ErrType
+		}
+	}
+}
+
 // GetSuperGroupAbnormalMsgSeq get super group abnormal msg seq
 func (i *LocalChatLogs) GetSuperGroupAbnormalMsgSeq(ctx context.Context, groupID string) (uint32, error) {
 	isExist, err := exec.Exec(groupID)
@@ -680,6 +716,42 @@ func (i *LocalChatLogs) GetMessagesByClientMsgIDs(ctx context.Context, conversat
 	}
 }
 
+// GetMessagesBySendID gets the messages of a sender within a time range
+func (i *LocalChatLogs) GetMessagesBySendID(ctx context.Context, conversationID, sendID string, startTime, endTime int64) (result []*model_struct.LocalChatLog, err error) {
+	msgs, err := exec.Exec(conversationID, sendID, startTime, endTime)
+	if err != nil {
+		return nil, err
+	} else {
+		if v, ok := msgs.(string); ok {
+			err := utils.JsonStringToStruct(v, &result)
+			if err != nil {
+				return nil, err
+			}
+			return result, err
+		} else {
+			return nil, exec.ErrType
+		}
+	}
+}
+
+// GetMessagesBySendIDSeqRange gets the messages of a sender whose seq is in (startSeq, endSeq]
+func (i *LocalChatLogs) GetMessagesBySendIDSeqRange(ctx context.Context, conversationID, sendID string, startSeq, endSeq int64) (result []*model_struct.LocalChatLog, err error) {
+	msgs, err := exec.Exec(conversationID, sendID, startSeq, endSeq)
+	if err != nil {
+		return nil, err
+	} else {
+		if v, ok := msgs.(string); ok {
+			err := utils.JsonStringToStruct(v, &result)
+			if err != nil {
+				return nil, err
+			}
+			return result, err
+		} else {
+			return nil, exec.ErrType
+		}
+	}
+}
+
 // GetMessagesBySeqs gets messages by seqs
 func (i *LocalChatLogs) GetMessagesBySeqs(ctx context.Context, conversationID string, seqs []int64) (result []*model_struct.LocalChatLog, err error) {
 	msgs, err := exec.Exec(conversationID, utils.StructToJsonString(seqs))
@@ -22,6 +22,7 @@ import (
 
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
 	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/sdk_struct"
 	"github.com/openimsdk/openim-sdk-core/v3/wasm/exec"
 	"github.com/openimsdk/openim-sdk-core/v3/wasm/indexdb/temp_struct"
 	"github.com/openimsdk/tools/errs"
@@ -138,6 +139,25 @@ func (i *LocalConversations) GetConversationByUserID(ctx context.Context, userID
 	}
 }
 
+func (i *LocalConversations) QueryConversationList(ctx context.Context, query *sdk_struct.ConversationQuery, cursor *sdk_struct.ConversationCursor, count int) (result []*model_struct.LocalConversation, err error) {
+	cList, err := exec.Exec(utils.StructToJsonString(query), utils.StructToJsonString(cursor), count)
+	if err != nil {
+		return nil, err
+	}
+	if v, ok := cList.(string); ok {
+		var temp []model_struct.LocalConversation
+		if err := utils.JsonStringToStruct(v, &temp); err != nil {
+			return nil, err
+		}
+		for _, v := range temp {
+			v1 := v
+			result = append(result, &v1)
+		}
+		return result, nil
+	}
+	return nil, exec.ErrType
+}
+
 func (i *LocalConversations) GetConversationListSplitDB(ctx context.Context, offset, count int) (result []*model_struct.LocalConversation, err error) {
 	cList, err := exec.Exec(offset, count)
 	if err != nil {
@@ -277,6 +297,11 @@ func (i *LocalConversations) SetConversationDraftDB(ctx context.Context, convers
 	return err
 }
 
+func (i *LocalConversations) UpdateConversationDraftText(ctx context.Context, conversationID, draftText string, draftTextTime int64) error {
+	_, err := exec.Exec(conversationID, draftText, draftTextTime)
+	return err
+}
+
 func (i *LocalConversations) RemoveConversationDraft(ctx context.Context, conversationID, draftText string) error {
 	_, err := exec.Exec(conversationID, draftText)
 	return err
@@ -376,6 +401,43 @@ func (i *LocalConversations) GetAllConversationIDList(ctx context.Context) ([]st
 		}
 	}
 }
+
+func (i *LocalConversations) GetMarkedUnreadConversationIDList(ctx context.Context) ([]string, error) {
+	conversationIDList, err := exec.Exec()
+	if err != nil {
+		return nil, err
+	} else {
+		if v, ok := conversationIDList.(string); ok {
+			var result []string
+			err := utils.JsonStringToStruct(v, &result)
+			if err != nil {
+				return nil, err
+			}
+			return result, err
+		} else {
+			return nil, exec.ErrType
+		}
+	}
+}
+
+func (i *LocalConversations) GetConversationUnreadCountGroups(ctx context.Context) ([]*model_struct.ConversationUnreadCountGroup, error) {
+	groups, err := exec.Exec()
+	if err != nil {
+		return nil, err
+	} else {
+		if v, ok := groups.(string); ok {
+			var result []*model_struct.ConversationUnreadCountGroup
+			err := utils.JsonStringToStruct(v, &result)
+			if err != nil {
+				return nil, err
+			}
+			return result, err
+		} else {
+			return nil, exec.ErrType
+		}
+	}
+}
+
 func (i *LocalConversations) SearchConversations(ctx context.Context, searchParam string) ([]*model_struct.LocalConversation, error) {
 
 	var result []*model_struct.LocalConversation
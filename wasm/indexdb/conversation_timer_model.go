@@ -0,0 +1,78 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build js && wasm
+// +build js,wasm
+
+package indexdb
+
+import (
+	"context"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/wasm/exec"
+)
+
+type LocalConversationTimers struct {
+}
+
+func NewLocalConversationTimers() *LocalConversationTimers {
+	return &LocalConversationTimers{}
+}
+
+func (i *LocalConversationTimers) SetConversationTimer(ctx context.Context, timer *model_struct.LocalConversationTimer) error {
+	_, err := exec.Exec(utils.StructToJsonString(timer))
+	return err
+}
+
+func (i *LocalConversationTimers) DeleteConversationTimer(ctx context.Context, conversationID string) error {
+	_, err := exec.Exec(conversationID)
+	return err
+}
+
+func (i *LocalConversationTimers) GetConversationTimer(ctx context.Context, conversationID string) (*model_struct.LocalConversationTimer, error) {
+	c, err := exec.Exec(conversationID)
+	if err != nil {
+		return nil, err
+	} else {
+		if v, ok := c.(string); ok {
+			result := model_struct.LocalConversationTimer{}
+			err := utils.JsonStringToStruct(v, &result)
+			if err != nil {
+				return nil, err
+			}
+			return &result, err
+		} else {
+			return nil, exec.ErrType
+		}
+	}
+}
+
+func (i *LocalConversationTimers) GetConversationTimerList(ctx context.Context) (result []*model_struct.LocalConversationTimer, err error) {
+	c, err := exec.Exec()
+	if err != nil {
+		return nil, err
+	} else {
+		if v, ok := c.(string); ok {
+			err := utils.JsonStringToStruct(v, &result)
+			if err != nil {
+				return nil, err
+			}
+			return result, err
+		} else {
+			return nil, exec.ErrType
+		}
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright © 2023 OpenIM SDK. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build js && wasm
+// +build js,wasm
+
+package indexdb
+
+import (
+	"context"
+
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+	"github.com/openimsdk/openim-sdk-core/v3/pkg/utils"
+	"github.com/openimsdk/openim-sdk-core/v3/wasm/exec"
+)
+
+type LocalGroupMemberReadSeqs struct {
+}
+
+func NewLocalGroupMemberReadSeqs() *LocalGroupMemberReadSeqs {
+	return &LocalGroupMemberReadSeqs{}
+}
+
+func (i *LocalGroupMemberReadSeqs) SetGroupMemberReadSeq(ctx context.Context, readSeq *model_struct.LocalGroupMemberReadSeq) error {
+	_, err := exec.Exec(utils.StructToJsonString(readSeq))
+	return err
+}
+
+func (i *LocalGroupMemberReadSeqs) GetGroupMemberReadSeq(ctx context.Context, conversationID, userID string) (*model_struct.LocalGroupMemberReadSeq, error) {
+	c, err := exec.Exec(conversationID, userID)
+	if err != nil {
+		return nil, err
+	} else {
+		if v, ok := c.(string); ok {
+			result := model_struct.LocalGroupMemberReadSeq{}
+			err := utils.JsonStringToStruct(v, &result)
+			if err != nil {
+				return nil, err
+			}
+			return &result, err
+		} else {
+			return nil, exec.ErrType
+		}
+	}
+}
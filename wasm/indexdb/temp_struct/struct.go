@@ -17,6 +17,8 @@
 
 package temp_struct
 
+import "github.com/openimsdk/openim-sdk-core/v3/pkg/db/model_struct"
+
 type LocalChatLog struct {
 	ServerMsgID          string ` json:"serverMsgID,omitempty"`
 	SendID               string ` json:"sendID,omitempty"`
@@ -155,3 +157,8 @@ type LocalUserCommand struct {
 	Uuid       string `json:"uuid,omitempty"`
 	Value      string `json:"value,omitempty"`
 }
+
+type MessageSearchPage struct {
+	MessageList []*model_struct.LocalChatLog `json:"messageList"`
+	TotalCount  int64                        `json:"totalCount"`
+}
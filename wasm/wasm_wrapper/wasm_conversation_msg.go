@@ -55,6 +55,9 @@ func (w *WrapperConMsg) CreateFileMessageByURL(_ js.Value, args []js.Value) inte
 func (w *WrapperConMsg) CreateCustomMessage(_ js.Value, args []js.Value) interface{} {
 	return event_listener.NewCaller(open_im_sdk.CreateCustomMessage, nil, &args).AsyncCallWithOutCallback()
 }
+func (w *WrapperConMsg) CreatePollMessage(_ js.Value, args []js.Value) interface{} {
+	return event_listener.NewCaller(open_im_sdk.CreatePollMessage, nil, &args).AsyncCallWithOutCallback()
+}
 func (w *WrapperConMsg) CreateQuoteMessage(_ js.Value, args []js.Value) interface{} {
 	return event_listener.NewCaller(open_im_sdk.CreateQuoteMessage, nil, &args).AsyncCallWithOutCallback()
 }
@@ -122,6 +125,14 @@ func (w *WrapperConMsg) MarkConversationMessageAsRead(_ js.Value, args []js.Valu
 	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
 	return event_listener.NewCaller(open_im_sdk.MarkConversationMessageAsRead, callback, &args).AsyncCallWithCallback()
 }
+func (w *WrapperConMsg) MarkConversationAsUnread(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.MarkConversationAsUnread, callback, &args).AsyncCallWithCallback()
+}
+func (w *WrapperConMsg) GetMessageReadMembers(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.GetMessageReadMembers, callback, &args).AsyncCallWithCallback()
+}
 func (w *WrapperConMsg) MarkMessagesAsReadByMsgID(_ js.Value, args []js.Value) interface{} {
 	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
 	return event_listener.NewCaller(open_im_sdk.MarkMessagesAsReadByMsgID, callback, &args).AsyncCallWithCallback()
@@ -134,6 +145,10 @@ func (w *WrapperConMsg) SendMessageNotOss(_ js.Value, args []js.Value) interface
 	callback := event_listener.NewSendMessageCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc).SetClientMsgID(&args)
 	return event_listener.NewCaller(open_im_sdk.SendMessageNotOss, callback, &args).AsyncCallWithCallback()
 }
+func (w *WrapperConMsg) ForwardMessages(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.ForwardMessages, callback, &args).AsyncCallWithCallback()
+}
 
 //func (w *WrapperConMsg) SetMessageReactionExtensions(_ js.Value, args []js.Value) interface{} {
 //	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
@@ -169,6 +184,81 @@ func (w *WrapperConMsg) GetConversationListSplit(_ js.Value, args []js.Value) in
 	return event_listener.NewCaller(open_im_sdk.GetConversationListSplit, callback, &args).AsyncCallWithCallback()
 }
 
+func (w *WrapperConMsg) QueryConversationList(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.QueryConversationList, callback, &args).AsyncCallWithCallback()
+}
+
+func (w *WrapperConMsg) GetConversationListSplitByFolder(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.GetConversationListSplitByFolder, callback, &args).AsyncCallWithCallback()
+}
+
+func (w *WrapperConMsg) CreateConversationFolder(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.CreateConversationFolder, callback, &args).AsyncCallWithCallback()
+}
+
+func (w *WrapperConMsg) UpdateConversationFolder(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.UpdateConversationFolder, callback, &args).AsyncCallWithCallback()
+}
+
+func (w *WrapperConMsg) DeleteConversationFolder(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.DeleteConversationFolder, callback, &args).AsyncCallWithCallback()
+}
+
+func (w *WrapperConMsg) GetConversationFolders(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.GetConversationFolders, callback, &args).AsyncCallWithCallback()
+}
+
+func (w *WrapperConMsg) GetConversationFolderUnreadCounts(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.GetConversationFolderUnreadCounts, callback, &args).AsyncCallWithCallback()
+}
+
+func (w *WrapperConMsg) StarMessage(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.StarMessage, callback, &args).AsyncCallWithCallback()
+}
+
+func (w *WrapperConMsg) UnstarMessage(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.UnstarMessage, callback, &args).AsyncCallWithCallback()
+}
+
+func (w *WrapperConMsg) SetStarredMessageTags(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.SetStarredMessageTags, callback, &args).AsyncCallWithCallback()
+}
+
+func (w *WrapperConMsg) GetStarredMessageList(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.GetStarredMessageList, callback, &args).AsyncCallWithCallback()
+}
+
+func (w *WrapperConMsg) GetStarredMessageTags(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.GetStarredMessageTags, callback, &args).AsyncCallWithCallback()
+}
+
+func (w *WrapperConMsg) MuteConversationUntil(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.MuteConversationUntil, callback, &args).AsyncCallWithCallback()
+}
+
+func (w *WrapperConMsg) SnoozeConversationUntil(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.SnoozeConversationUntil, callback, &args).AsyncCallWithCallback()
+}
+
+func (w *WrapperConMsg) GetConversationTimerList(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.GetConversationTimerList, callback, &args).AsyncCallWithCallback()
+}
+
 func (w *WrapperConMsg) GetOneConversation(_ js.Value, args []js.Value) interface{} {
 	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
 	return event_listener.NewCaller(open_im_sdk.GetOneConversation, callback, &args).AsyncCallWithCallback()
@@ -183,6 +273,10 @@ func (w *WrapperConMsg) GetAdvancedHistoryMessageListReverse(_ js.Value, args []
 	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
 	return event_listener.NewCaller(open_im_sdk.GetAdvancedHistoryMessageListReverse, callback, &args).AsyncCallWithCallback()
 }
+func (w *WrapperConMsg) GetMessageListAroundAnchor(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.GetMessageListAroundAnchor, callback, &args).AsyncCallWithCallback()
+}
 
 //func (w *WrapperConMsg) GetHistoryMessageList(_ js.Value, args []js.Value) interface{} {
 //	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
@@ -204,6 +298,46 @@ func (w *WrapperConMsg) RevokeMessage(_ js.Value, args []js.Value) interface{} {
 	return event_listener.NewCaller(open_im_sdk.RevokeMessage, callback, &args).AsyncCallWithCallback()
 }
 
+func (w *WrapperConMsg) RevokeMessages(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.RevokeMessages, callback, &args).AsyncCallWithCallback()
+}
+
+func (w *WrapperConMsg) RevokeUserMessages(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.RevokeUserMessages, callback, &args).AsyncCallWithCallback()
+}
+
+func (w *WrapperConMsg) PinMessage(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.PinMessage, callback, &args).AsyncCallWithCallback()
+}
+
+func (w *WrapperConMsg) UnpinMessage(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.UnpinMessage, callback, &args).AsyncCallWithCallback()
+}
+
+func (w *WrapperConMsg) GetPinnedMessageList(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.GetPinnedMessageList, callback, &args).AsyncCallWithCallback()
+}
+
+func (w *WrapperConMsg) SetSensitiveWordFilter(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.SetSensitiveWordFilter, callback, &args).AsyncCallWithCallback()
+}
+
+func (w *WrapperConMsg) GetLinkPreview(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.GetLinkPreview, callback, &args).AsyncCallWithCallback()
+}
+
+func (w *WrapperConMsg) VotePoll(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.VotePoll, callback, &args).AsyncCallWithCallback()
+}
+
 func (w *WrapperConMsg) TypingStatusUpdate(_ js.Value, args []js.Value) interface{} {
 	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
 	return event_listener.NewCaller(open_im_sdk.TypingStatusUpdate, callback, &args).AsyncCallWithCallback()
@@ -219,6 +353,11 @@ func (w *WrapperConMsg) DeleteMessage(_ js.Value, args []js.Value) interface{} {
 	return event_listener.NewCaller(open_im_sdk.DeleteMessage, callback, &args).AsyncCallWithCallback()
 }
 
+func (w *WrapperConMsg) DeleteMessages(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.DeleteMessages, callback, &args).AsyncCallWithCallback()
+}
+
 func (w *WrapperConMsg) HideAllConversations(_ js.Value, args []js.Value) interface{} {
 	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
 	return event_listener.NewCaller(open_im_sdk.HideAllConversations, callback, &args).AsyncCallWithCallback()
@@ -253,6 +392,10 @@ func (w *WrapperConMsg) SearchLocalMessages(_ js.Value, args []js.Value) interfa
 	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
 	return event_listener.NewCaller(open_im_sdk.SearchLocalMessages, callback, &args).AsyncCallWithCallback()
 }
+func (w *WrapperConMsg) GlobalSearch(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.GlobalSearch, callback, &args).AsyncCallWithCallback()
+}
 func (w *WrapperConMsg) SetMessageLocalEx(_ js.Value, args []js.Value) interface{} {
 	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
 	return event_listener.NewCaller(open_im_sdk.SetMessageLocalEx, callback, &args).AsyncCallWithCallback()
@@ -276,11 +419,26 @@ func (w *WrapperConMsg) SetConversationDraft(_ js.Value, args []js.Value) interf
 	return event_listener.NewCaller(open_im_sdk.SetConversationDraft, callback, &args).AsyncCallWithCallback()
 }
 
+func (w *WrapperConMsg) SetConversationRichDraft(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.SetConversationRichDraft, callback, &args).AsyncCallWithCallback()
+}
+
+func (w *WrapperConMsg) GetConversationRichDraft(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.GetConversationRichDraft, callback, &args).AsyncCallWithCallback()
+}
+
 func (w *WrapperConMsg) GetTotalUnreadMsgCount(_ js.Value, args []js.Value) interface{} {
 	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
 	return event_listener.NewCaller(open_im_sdk.GetTotalUnreadMsgCount, callback, &args).AsyncCallWithCallback()
 }
 
+func (w *WrapperConMsg) GetUnreadCountBreakdown(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.GetUnreadCountBreakdown, callback, &args).AsyncCallWithCallback()
+}
+
 func (w *WrapperConMsg) ChangeInputStates(_ js.Value, args []js.Value) interface{} {
 	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
 	return event_listener.NewCaller(open_im_sdk.ChangeInputStates, callback, &args).AsyncCallWithCallback()
@@ -290,3 +448,38 @@ func (w *WrapperConMsg) GetInputStates(_ js.Value, args []js.Value) interface{}
 	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
 	return event_listener.NewCaller(open_im_sdk.GetInputStates, callback, &args).AsyncCallWithCallback()
 }
+
+func (w *WrapperConMsg) ChangeInputActivity(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.ChangeInputActivity, callback, &args).AsyncCallWithCallback()
+}
+
+func (w *WrapperConMsg) GetConversationInputStates(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.GetConversationInputStates, callback, &args).AsyncCallWithCallback()
+}
+
+func (w *WrapperConMsg) StartLiveLocation(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.StartLiveLocation, callback, &args).AsyncCallWithCallback()
+}
+
+func (w *WrapperConMsg) UpdateLiveLocation(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.UpdateLiveLocation, callback, &args).AsyncCallWithCallback()
+}
+
+func (w *WrapperConMsg) StopLiveLocation(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.StopLiveLocation, callback, &args).AsyncCallWithCallback()
+}
+
+func (w *WrapperConMsg) GetLiveLocations(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.GetLiveLocations, callback, &args).AsyncCallWithCallback()
+}
+
+func (w *WrapperConMsg) SetConversationDeltaMode(_ js.Value, args []js.Value) interface{} {
+	callback := event_listener.NewBaseCallback(utils.FirstLower(utils.GetSelfFuncName()), w.commonFunc)
+	return event_listener.NewCaller(open_im_sdk.SetConversationDeltaMode, callback, &args).AsyncCallWithCallback()
+}